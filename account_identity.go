@@ -0,0 +1,85 @@
+package main
+
+import (
+	"sync"
+
+	"github.com/gin-gonic/gin"
+	"github.com/xpzouying/xiaohongshu-mcp/configs"
+)
+
+// accountContextKey gin 上下文中保存处理请求的账号信息的键
+const accountContextKey = "account_info"
+
+// AccountInfo 处理请求的账号，UserID 和 Nickname 来自最近一次登录状态检查，检查前或未登录时为空
+type AccountInfo struct {
+	Username string `json:"username,omitempty"` // 配置的账号名称
+	UserID   string `json:"user_id,omitempty"`
+	Nickname string `json:"nickname,omitempty"`
+}
+
+// Label 用于日志的账号标识，优先使用实际登录的小红书账号
+func (a AccountInfo) Label() string {
+	switch {
+	case a.UserID != "" && a.Nickname != "":
+		return a.Nickname + "(" + a.UserID + ")"
+	case a.UserID != "":
+		return a.UserID
+	case a.Username != "":
+		return a.Username
+	default:
+		return "unknown"
+	}
+}
+
+// accountIdentity 缓存最近一次登录状态检查得到的账号信息，避免每个请求都打开浏览器确认身份
+type accountIdentity struct {
+	mu       sync.RWMutex
+	userID   string
+	nickname string
+}
+
+// Get 返回当前的账号信息
+func (a *accountIdentity) Get() AccountInfo {
+	a.mu.RLock()
+	defer a.mu.RUnlock()
+
+	return AccountInfo{
+		Username: configs.Username,
+		UserID:   a.userID,
+		Nickname: a.nickname,
+	}
+}
+
+// Update 按登录状态检查的结果更新账号信息，未登录时清空；已登录但没有读到账号信息时保留原值
+func (a *accountIdentity) Update(status *LoginStatusResponse) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	if !status.IsLoggedIn {
+		a.userID, a.nickname = "", ""
+		return
+	}
+	if status.UserID != "" {
+		a.userID, a.nickname = status.UserID, status.Nickname
+	}
+}
+
+// setRequestAccount 记录处理请求的账号，用于访问日志和响应中的 account 字段
+func (s *AppServer) setRequestAccount(c *gin.Context) {
+	account := s.account.Get()
+	c.Set("account", account.Label())
+	c.Set(accountContextKey, account)
+}
+
+// requestAccount 返回 setRequestAccount 记录的账号信息，没有记录或账号未知时返回 nil
+func requestAccount(c *gin.Context) *AccountInfo {
+	value, ok := c.Get(accountContextKey)
+	if !ok {
+		return nil
+	}
+	account := value.(AccountInfo)
+	if account == (AccountInfo{}) {
+		return nil
+	}
+	return &account
+}
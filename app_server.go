@@ -10,19 +10,28 @@ import (
 
 	"github.com/gin-gonic/gin"
 	"github.com/sirupsen/logrus"
+	"github.com/xpzouying/xiaohongshu-mcp/configs"
 )
 
 // AppServer 应用服务器结构体，封装所有服务和处理器
 type AppServer struct {
-	xiaohongshuService *XiaohongshuService
+	xiaohongshuService XHSService
+	sessions           *sessionStore
+	notifications      *notificationHub
+	connections        *connectionLimiter
+	loginState         loginState
+	account            accountIdentity
 	router             *gin.Engine
 	httpServer         *http.Server
 }
 
 // NewAppServer 创建新的应用服务器实例
-func NewAppServer(xiaohongshuService *XiaohongshuService) *AppServer {
+func NewAppServer(xiaohongshuService XHSService) *AppServer {
 	return &AppServer{
 		xiaohongshuService: xiaohongshuService,
+		sessions:           newSessionStore(mcpSessionTTL),
+		notifications:      newNotificationHub(),
+		connections:        newConnectionLimiter(configs.GetMaxConnections()),
 	}
 }
 
@@ -44,10 +53,17 @@ func (s *AppServer) Start(port string) error {
 		}
 	}()
 
+	// 后台检查登录状态，服务关闭时退出
+	monitorCtx, stopMonitor := context.WithCancel(context.Background())
+	defer stopMonitor()
+	go s.monitorLogin(monitorCtx, configs.GetLoginCheckInterval())
+	go s.keepSessionAlive(monitorCtx, configs.GetSessionKeepAlive())
+
 	// 等待中断信号
 	quit := make(chan os.Signal, 1)
 	signal.Notify(quit, syscall.SIGINT, syscall.SIGTERM)
 	<-quit
+	stopMonitor()
 
 	logrus.Infof("正在关闭服务器...")
 
@@ -0,0 +1,143 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/sirupsen/logrus"
+)
+
+// 审计日志
+//
+// 每次写操作（发布、编辑、评论）结束后追加一行 JSON 记录，包含时间、账号、参数摘要和结果。
+// 参数只记录标题、话题、图片数量等摘要，不记录图片内容。文件超过大小上限时轮转，
+// 最多保留 auditLogBackups 个历史文件（audit.log.1 最新）。写入失败只打印日志，不影响操作本身。
+
+// auditLogBackups 轮转后保留的历史文件数量
+const auditLogBackups = 3
+
+// auditEntry 一条审计记录
+type auditEntry struct {
+	Time     time.Time      `json:"time"`
+	Account  string         `json:"account"`
+	Action   string         `json:"action"`
+	Args     map[string]any `json:"args,omitempty"`
+	Success  bool           `json:"success"`
+	Error    string         `json:"error,omitempty"`
+	Duration string         `json:"duration"`
+}
+
+// auditLogger 以 JSON Lines 格式追加写入审计日志，并按大小轮转。nil 表示不记录审计日志
+type auditLogger struct {
+	mu      sync.Mutex
+	path    string
+	maxSize int64
+	file    *os.File
+	size    int64
+}
+
+// newAuditLogger 创建审计日志，path 为空时返回 nil
+func newAuditLogger(path string, maxSize int64) *auditLogger {
+	if path == "" {
+		return nil
+	}
+
+	return &auditLogger{
+		path:    path,
+		maxSize: maxSize,
+	}
+}
+
+// Record 记录一次写操作的结果，start 为操作开始时间
+func (a *auditLogger) Record(account, action string, args map[string]any, start time.Time, err error) {
+	if a == nil {
+		return
+	}
+
+	entry := auditEntry{
+		Time:     start,
+		Account:  account,
+		Action:   action,
+		Args:     args,
+		Success:  err == nil,
+		Duration: time.Since(start).Round(time.Millisecond).String(),
+	}
+	if err != nil {
+		entry.Error = err.Error()
+	}
+
+	line, marshalErr := json.Marshal(entry)
+	if marshalErr != nil {
+		logrus.Errorf("序列化审计日志失败: %v", marshalErr)
+		return
+	}
+
+	if writeErr := a.write(append(line, '\n')); writeErr != nil {
+		logrus.Errorf("写入审计日志失败: %v", writeErr)
+	}
+}
+
+// write 追加一行记录，写入前文件超过大小上限时先轮转
+func (a *auditLogger) write(line []byte) error {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	if a.file == nil {
+		if err := a.open(); err != nil {
+			return err
+		}
+	}
+
+	if a.size > 0 && a.size+int64(len(line)) > a.maxSize {
+		if err := a.rotate(); err != nil {
+			return err
+		}
+	}
+
+	n, err := a.file.Write(line)
+	a.size += int64(n)
+	return err
+}
+
+// open 以追加模式打开审计日志文件
+func (a *auditLogger) open() error {
+	file, err := os.OpenFile(a.path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0o600)
+	if err != nil {
+		return fmt.Errorf("打开审计日志失败: %w", err)
+	}
+
+	info, err := file.Stat()
+	if err != nil {
+		file.Close()
+		return fmt.Errorf("读取审计日志大小失败: %w", err)
+	}
+
+	a.file = file
+	a.size = info.Size()
+	return nil
+}
+
+// rotate 关闭当前文件，依次重命名为 .1、.2…，超出保留数量的历史文件被覆盖，然后重新打开
+func (a *auditLogger) rotate() error {
+	if err := a.file.Close(); err != nil {
+		logrus.Warnf("关闭审计日志失败: %v", err)
+	}
+	a.file = nil
+
+	for i := auditLogBackups - 1; i >= 1; i-- {
+		from := fmt.Sprintf("%s.%d", a.path, i)
+		if _, err := os.Stat(from); err == nil {
+			if err := os.Rename(from, fmt.Sprintf("%s.%d", a.path, i+1)); err != nil {
+				return fmt.Errorf("轮转审计日志失败: %w", err)
+			}
+		}
+	}
+	if err := os.Rename(a.path, a.path+".1"); err != nil {
+		return fmt.Errorf("轮转审计日志失败: %w", err)
+	}
+
+	return a.open()
+}
@@ -0,0 +1,174 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/go-rod/rod"
+	"github.com/go-rod/rod/lib/launcher"
+	"github.com/go-rod/rod/lib/launcher/flags"
+	"github.com/go-rod/stealth"
+	"github.com/sirupsen/logrus"
+	"github.com/xpzouying/xiaohongshu-mcp/configs"
+)
+
+// 浏览器崩溃恢复
+//
+// 每个请求都会启动独立的浏览器，Chromium 进程偶尔会在请求过程中崩溃。
+// 崩溃后 rod 的 Must 系列方法会 panic，这里统一兜底：
+// 启动失败时重试一次；关闭已退出的浏览器时不再 panic；
+// 读操作失败后检测到浏览器已退出时，重新启动浏览器并重试一次。
+// 写操作不重试，避免崩溃前已提交的内容被重复发布。
+
+// browserProbeTimeout 检测浏览器是否存活的超时时间
+const browserProbeTimeout = 3 * time.Second
+
+// errBrowserCrashed 操作过程中浏览器进程已退出
+var errBrowserCrashed = errors.New("浏览器进程已退出")
+
+// serviceBrowser 每个请求启动的浏览器，浏览器进程已退出时关闭不会 panic
+type serviceBrowser struct {
+	browser  *rod.Browser
+	launcher *launcher.Launcher
+	release  func() // 释放占用的浏览器名额
+}
+
+// NewPage 创建开启了反检测脚本的页面
+func (b *serviceBrowser) NewPage() *rod.Page {
+	return stealth.MustPage(b.browser)
+}
+
+// Close 关闭浏览器并释放占用的名额，浏览器已崩溃时只记录日志
+func (b *serviceBrowser) Close() {
+	defer b.release()
+
+	if err := b.browser.Close(); err != nil {
+		logrus.Warnf("关闭浏览器失败，浏览器进程可能已退出: %v", err)
+		b.launcher.Kill()
+	}
+	b.launcher.Cleanup()
+}
+
+// newBrowser 占用一个浏览器名额后启动浏览器，启动失败时重试一次，仍然失败时释放名额并返回错误。
+// 浏览器数量达到上限且排队超时时返回 ErrServerBusy
+func (s *XiaohongshuService) newBrowser(ctx context.Context) (*serviceBrowser, error) {
+	release, err := s.browsers.Acquire(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	headless := isHeadless(ctx)
+	if !headless && configs.IsHeadless() {
+		logrus.WithContext(ctx).Info("按请求要求启动有界面的浏览器")
+	}
+
+	b, err := launchBrowser(headless)
+	if err != nil {
+		logrus.WithContext(ctx).Warnf("启动浏览器失败，重试一次: %v", err)
+		if b, err = launchBrowser(headless); err != nil {
+			release()
+			return nil, fmt.Errorf("重试后仍然无法启动: %w", err)
+		}
+		logrus.WithContext(ctx).Info("重试启动浏览器成功")
+	}
+
+	b.release = release
+	return b, nil
+}
+
+// launchBrowser 按配置启动浏览器，headless 为 false 时显示浏览器界面，将启动过程中的 panic 转换为错误。
+// 直接使用 rod 的启动器，headless_browser 不能传入 -chrome-arg 等额外的启动参数
+func launchBrowser(headless bool) (b *serviceBrowser, err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("启动浏览器失败: %v", r)
+		}
+	}()
+
+	l := launcher.New().
+		Headless(headless).
+		Set("no-sandbox").
+		Set("user-agent", configs.GetUserAgent())
+	if binPath := configs.GetBinPath(); binPath != "" {
+		l = l.Bin(binPath)
+	}
+	l = applyChromeArgs(l, configs.GetLaunchChromeArgs(headless))
+
+	// 启动失败时浏览器进程可能没有运行，Cleanup 会一直等待进程退出，这里不调用
+	controlURL, err := l.Launch()
+	if err != nil {
+		return nil, fmt.Errorf("启动浏览器失败: %w", err)
+	}
+
+	rb := rod.New().ControlURL(controlURL)
+	if err := rb.Connect(); err != nil {
+		l.Kill()
+		l.Cleanup()
+		return nil, fmt.Errorf("连接浏览器失败: %w", err)
+	}
+
+	return &serviceBrowser{browser: rb, launcher: l}, nil
+}
+
+// applyChromeArgs 将 --name 或 --name=value 形式的启动参数加到启动器上，同名参数覆盖启动器的默认值，
+// 如 --headless=new 替换默认的 --headless
+func applyChromeArgs(l *launcher.Launcher, args []string) *launcher.Launcher {
+	for _, arg := range args {
+		name, value, hasValue := strings.Cut(strings.TrimPrefix(arg, "--"), "=")
+		if hasValue {
+			l = l.Set(flags.Flag(name), value)
+		} else {
+			l = l.Set(flags.Flag(name))
+		}
+	}
+	return l
+}
+
+// visibleBrowserKey 标记请求要求使用有界面的浏览器
+type visibleBrowserKey struct{}
+
+// withVisibleBrowser 标记请求使用有界面的浏览器，便于观察单个账号或单次操作的执行过程，
+// 不需要以 -headless=false 重启整个服务。未开启 -allow-visible-browser 时返回 ErrInvalidArgs，
+// visible 为 false 时原样返回，使用全局设置
+func withVisibleBrowser(ctx context.Context, visible bool) (context.Context, error) {
+	if !visible {
+		return ctx, nil
+	}
+	if !configs.IsVisibleBrowserAllowed() {
+		return ctx, fmt.Errorf("%w: 服务未开启 -allow-visible-browser，不能使用 visible 参数", ErrInvalidArgs)
+	}
+	return context.WithValue(ctx, visibleBrowserKey{}, true), nil
+}
+
+// isHeadless 判断请求使用的浏览器是否为无头模式，请求没有要求显示界面时使用全局设置
+func isHeadless(ctx context.Context) bool {
+	if visible, _ := ctx.Value(visibleBrowserKey{}).(bool); visible {
+		return false
+	}
+	return configs.IsHeadless()
+}
+
+// browserCrashed 判断页面所在的浏览器进程是否已退出
+func browserCrashed(page *rod.Page) bool {
+	_, err := page.Browser().Timeout(browserProbeTimeout).Version()
+	return err != nil
+}
+
+// retryOnBrowserCrash 执行读操作，浏览器在执行过程中崩溃时重新启动浏览器并重试一次
+func retryOnBrowserCrash[T any](name string, fn func() (T, error)) (T, error) {
+	result, err := fn()
+	if !errors.Is(err, errBrowserCrashed) {
+		return result, err
+	}
+
+	logrus.Warnf("%s: 浏览器进程异常退出，重新启动浏览器并重试: %v", name, err)
+	result, err = fn()
+	if err == nil {
+		logrus.Infof("%s: 重新启动浏览器后执行成功", name)
+	}
+
+	return result, err
+}
@@ -0,0 +1,65 @@
+package main
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/go-rod/rod/lib/launcher"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/xpzouying/xiaohongshu-mcp/configs"
+)
+
+func TestApplyChromeArgs(t *testing.T) {
+	l := applyChromeArgs(launcher.New().Headless(true), []string{
+		"--disable-dev-shm-usage",
+		"--disable-features=A,B",
+		"--proxy-server=http://127.0.0.1:8080",
+	})
+
+	args := l.FormatArgs()
+	assert.Contains(t, args, "--disable-dev-shm-usage")
+	assert.Contains(t, args, "--disable-features=A,B")
+	assert.Contains(t, args, "--proxy-server=http://127.0.0.1:8080")
+	assert.Contains(t, args, "--headless")
+}
+
+func TestApplyChromeArgsHeadlessNew(t *testing.T) {
+	require.NoError(t, configs.SetHeadlessMode(string(configs.HeadlessModeNew)))
+	defer configs.SetHeadlessMode(string(configs.HeadlessModeLegacy))
+
+	// --headless=new 替换启动器默认的旧版 --headless
+	args := applyChromeArgs(launcher.New().Headless(true), configs.GetLaunchChromeArgs(true)).FormatArgs()
+	assert.Contains(t, args, "--headless=new")
+	assert.NotContains(t, args, "--headless")
+
+	// 有界面时不追加
+	args = applyChromeArgs(launcher.New().Headless(false), configs.GetLaunchChromeArgs(false)).FormatArgs()
+	assert.NotContains(t, args, "--headless=new")
+}
+
+func TestNewBrowserLaunchFailure(t *testing.T) {
+	configs.SetBinPath(filepath.Join(t.TempDir(), "no-such-chrome"))
+	defer configs.SetBinPath("")
+
+	s := &XiaohongshuService{browsers: newBrowserLimiter(1, time.Second)}
+
+	// 重试后仍然启动失败时返回错误，而不是让整个进程 panic
+	var b *serviceBrowser
+	var err error
+	require.NotPanics(t, func() {
+		b, err = s.newBrowser(context.Background())
+	})
+	require.Error(t, err)
+	assert.Nil(t, b)
+	assert.Contains(t, err.Error(), "启动浏览器失败")
+	assert.NotErrorIs(t, err, ErrServerBusy, "启动失败不是排队超时")
+
+	// 启动失败时释放名额，后续请求不会因名额被占用而排队超时
+	assert.Zero(t, s.browsers.Stats().InUse)
+	_, err = s.newBrowser(context.Background())
+	assert.NotErrorIs(t, err, ErrServerBusy)
+	assert.Zero(t, s.browsers.Stats().InUse)
+}
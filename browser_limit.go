@@ -0,0 +1,111 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"time"
+
+	"github.com/sirupsen/logrus"
+)
+
+// 浏览器并发限制
+//
+// 每个请求都会启动独立的浏览器，突发请求会同时启动大量 Chromium 进程耗尽内存。
+// 同时运行的浏览器数量达到 -max-browsers 时，后续请求排队等待，
+// 超过 -browser-wait 仍未轮到时返回 ErrServerBusy，由调用方稍后重试。
+
+// ErrServerBusy 浏览器数量达到上限且排队超时
+var ErrServerBusy = errors.New("服务繁忙，同时运行的浏览器数量已达上限，请稍后重试")
+
+// browserStats 浏览器使用情况，Idle 为还可以启动的浏览器数量，不限制数量时为 0
+type browserStats struct {
+	Max     int   `json:"max"`
+	InUse   int64 `json:"in_use"`
+	Idle    int64 `json:"idle"`
+	Waiting int64 `json:"waiting"`
+}
+
+// browserLimiter 限制同时运行的浏览器数量，达到上限时排队等待
+type browserLimiter struct {
+	sem     chan struct{} // 为 nil 表示不限制
+	maxWait time.Duration
+	inUse   atomic.Int64
+	waiting atomic.Int64
+}
+
+// newBrowserLimiter 创建浏览器并发限制，max 小于等于 0 表示不限制
+func newBrowserLimiter(max int, maxWait time.Duration) *browserLimiter {
+	l := &browserLimiter{maxWait: maxWait}
+	if max > 0 {
+		l.sem = make(chan struct{}, max)
+	}
+	return l
+}
+
+// Acquire 占用一个浏览器名额，成功时返回释放函数。
+// 排队超过 maxWait 时返回 ErrServerBusy，ctx 结束时返回 ctx 的错误
+func (l *browserLimiter) Acquire(ctx context.Context) (func(), error) {
+	release := func() {
+		l.inUse.Add(-1)
+		if l.sem != nil {
+			<-l.sem
+		}
+	}
+
+	if l.sem == nil {
+		l.inUse.Add(1)
+		return release, nil
+	}
+
+	// 有空闲名额时不排队
+	select {
+	case l.sem <- struct{}{}:
+		l.inUse.Add(1)
+		return release, nil
+	default:
+	}
+
+	if l.maxWait <= 0 {
+		return nil, ErrServerBusy
+	}
+
+	l.waiting.Add(1)
+	defer l.waiting.Add(-1)
+
+	logrus.WithContext(ctx).Debugf("浏览器已达上限 %d 个，排队等待空闲名额", cap(l.sem))
+	start := time.Now()
+
+	timer := time.NewTimer(l.maxWait)
+	defer timer.Stop()
+
+	select {
+	case l.sem <- struct{}{}:
+		l.inUse.Add(1)
+		logrus.WithContext(ctx).Debugf("排队 %s 后取得浏览器名额", time.Since(start).Round(time.Millisecond))
+		return release, nil
+	case <-timer.C:
+		logrus.WithContext(ctx).Warnf("排队等待浏览器超过 %s，返回服务繁忙", l.maxWait)
+		return nil, ErrServerBusy
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+}
+
+// BrowserStats 返回当前的浏览器使用情况
+func (s *XiaohongshuService) BrowserStats() browserStats {
+	return s.browsers.Stats()
+}
+
+// Stats 返回当前的浏览器使用情况
+func (l *browserLimiter) Stats() browserStats {
+	stats := browserStats{
+		Max:     cap(l.sem),
+		InUse:   l.inUse.Load(),
+		Waiting: l.waiting.Load(),
+	}
+	if l.sem != nil {
+		stats.Idle = max(int64(stats.Max)-stats.InUse, 0)
+	}
+	return stats
+}
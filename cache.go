@@ -0,0 +1,157 @@
+package main
+
+import (
+	"container/list"
+	"context"
+	"sync"
+	"time"
+)
+
+// 读结果缓存
+//
+// 热门笔记会被反复请求，每次都要启动浏览器。开启 -cache-ttl 后，笔记详情按 feed_id、
+// 搜索结果按关键词缓存，超过有效期或条目数上限（按最近使用淘汰）后重新获取。
+// 请求带 fresh: true 时跳过缓存读取，获取到的新结果仍会写入缓存。
+
+// cacheStats 缓存命中统计
+type cacheStats struct {
+	Hits    int64 `json:"hits"`
+	Misses  int64 `json:"misses"`
+	Entries int   `json:"entries"`
+}
+
+// ttlCache 并发安全的 LRU 缓存，条目超过 ttl 后失效。nil 表示未开启缓存，所有方法都可以安全调用
+type ttlCache[V any] struct {
+	mu         sync.Mutex
+	ttl        time.Duration
+	maxEntries int
+	order      *list.List // 最近使用的条目在前
+	entries    map[string]*list.Element
+	hits       int64
+	misses     int64
+}
+
+// cacheEntry 缓存条目
+type cacheEntry[V any] struct {
+	key       string
+	value     V
+	expiresAt time.Time
+}
+
+// newTTLCache 创建缓存，ttl 小于等于 0 时返回 nil，表示不缓存
+func newTTLCache[V any](ttl time.Duration, maxEntries int) *ttlCache[V] {
+	if ttl <= 0 {
+		return nil
+	}
+
+	return &ttlCache[V]{
+		ttl:        ttl,
+		maxEntries: maxEntries,
+		order:      list.New(),
+		entries:    make(map[string]*list.Element),
+	}
+}
+
+// Get 读取未过期的缓存。请求要求获取最新数据时不读缓存，也不计入命中统计
+func (c *ttlCache[V]) Get(ctx context.Context, key string) (V, bool) {
+	var zero V
+	if c == nil || isFreshRead(ctx) {
+		return zero, false
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	elem, ok := c.entries[key]
+	if !ok {
+		c.misses++
+		return zero, false
+	}
+
+	entry := elem.Value.(*cacheEntry[V])
+	if time.Now().After(entry.expiresAt) {
+		c.removeElement(elem)
+		c.misses++
+		return zero, false
+	}
+
+	c.order.MoveToFront(elem)
+	c.hits++
+	return entry.value, true
+}
+
+// Set 写入缓存，超出条目数上限时淘汰最久未使用的条目
+func (c *ttlCache[V]) Set(key string, value V) {
+	if c == nil {
+		return
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	expiresAt := time.Now().Add(c.ttl)
+	if elem, ok := c.entries[key]; ok {
+		entry := elem.Value.(*cacheEntry[V])
+		entry.value, entry.expiresAt = value, expiresAt
+		c.order.MoveToFront(elem)
+		return
+	}
+
+	c.entries[key] = c.order.PushFront(&cacheEntry[V]{key: key, value: value, expiresAt: expiresAt})
+	for c.order.Len() > c.maxEntries {
+		c.removeElement(c.order.Back())
+	}
+}
+
+// Delete 删除缓存条目，数据发生变化（如编辑、评论）后调用
+func (c *ttlCache[V]) Delete(key string) {
+	if c == nil {
+		return
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if elem, ok := c.entries[key]; ok {
+		c.removeElement(elem)
+	}
+}
+
+// Stats 返回命中统计，未开启缓存时返回零值
+func (c *ttlCache[V]) Stats() cacheStats {
+	if c == nil {
+		return cacheStats{}
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return cacheStats{
+		Hits:    c.hits,
+		Misses:  c.misses,
+		Entries: c.order.Len(),
+	}
+}
+
+// removeElement 删除条目，调用方需持有锁
+func (c *ttlCache[V]) removeElement(elem *list.Element) {
+	c.order.Remove(elem)
+	delete(c.entries, elem.Value.(*cacheEntry[V]).key)
+}
+
+// freshReadKey 标记请求要求跳过缓存
+type freshReadKey struct{}
+
+// withFreshRead 标记请求跳过缓存读取，fresh 为 false 时原样返回
+func withFreshRead(ctx context.Context, fresh bool) context.Context {
+	if !fresh {
+		return ctx
+	}
+	return context.WithValue(ctx, freshReadKey{}, true)
+}
+
+// isFreshRead 判断请求是否要求跳过缓存
+func isFreshRead(ctx context.Context) bool {
+	fresh, _ := ctx.Value(freshReadKey{}).(bool)
+	return fresh
+}
@@ -0,0 +1,84 @@
+package main
+
+import (
+	"context"
+	"sync"
+
+	"github.com/xpzouying/xiaohongshu-mcp/configs"
+)
+
+// AccountLoginStatus 单个账号的登录状态，检查失败时 Status 为空，Error 为失败原因
+type AccountLoginStatus struct {
+	Account string               `json:"account"`
+	Status  *LoginStatusResponse `json:"status,omitempty"`
+	Error   string               `json:"error,omitempty"`
+}
+
+// AllLoginsResponse 所有账号的登录状态
+type AllLoginsResponse struct {
+	Accounts   []AccountLoginStatus `json:"accounts"`
+	Total      int                  `json:"total"`
+	LoggedIn   int                  `json:"logged_in"`
+	NeedsLogin int                  `json:"needs_login"` // 未登录的账号数，不含检查失败的账号
+	Failed     int                  `json:"failed"`
+}
+
+// configuredAccounts 已配置的账号。服务目前只有一份登录会话，即 -session-dir 下的 cookies，
+// 对应 configs.Username 这一个账号
+func configuredAccounts() []string {
+	return []string{configs.Username}
+}
+
+// CheckAllLogins 检查所有已配置账号的登录状态，同时检查的账号数不超过 -max-browsers
+func (s *XiaohongshuService) CheckAllLogins(ctx context.Context) (*AllLoginsResponse, error) {
+	// 每个账号的检查都会启动浏览器，受浏览器并发限制排队；这里同样按 -max-browsers 限制并发，
+	// 避免账号较多时大量请求同时排队超过 -browser-wait
+	return checkLogins(ctx, configuredAccounts(), configs.GetMaxBrowsers(), func(ctx context.Context, _ string) (*LoginStatusResponse, error) {
+		return s.CheckLoginStatus(ctx)
+	}), nil
+}
+
+// checkLogins 以不超过 concurrency 的并发数检查每个账号的登录状态，concurrency 小于等于 0 表示不限制。
+// 单个账号检查失败只记录在该账号的结果中，不影响其他账号
+func checkLogins(ctx context.Context, accounts []string, concurrency int, check func(ctx context.Context, account string) (*LoginStatusResponse, error)) *AllLoginsResponse {
+	if concurrency <= 0 || concurrency > len(accounts) {
+		concurrency = len(accounts)
+	}
+
+	results := make([]AccountLoginStatus, len(accounts))
+	sem := make(chan struct{}, max(concurrency, 1))
+	var wg sync.WaitGroup
+	for i, account := range accounts {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			sem <- struct{}{}
+			defer func() { <-sem }()
+
+			results[i].Account = account
+			status, err := check(ctx, account)
+			if err != nil {
+				results[i].Error = err.Error()
+				return
+			}
+			results[i].Status = status
+		}()
+	}
+	wg.Wait()
+
+	response := &AllLoginsResponse{
+		Accounts: results,
+		Total:    len(results),
+	}
+	for _, result := range results {
+		switch {
+		case result.Status == nil:
+			response.Failed++
+		case result.Status.IsLoggedIn:
+			response.LoggedIn++
+		default:
+			response.NeedsLogin++
+		}
+	}
+	return response
+}
@@ -0,0 +1,118 @@
+package main
+
+import (
+	"bytes"
+	"compress/gzip"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/sirupsen/logrus"
+)
+
+// 响应压缩
+//
+// Feeds 列表、搜索结果等 JSON 响应较大，客户端在 Accept-Encoding 中声明 gzip 时压缩返回。
+// 响应先缓存在内存中，结束后根据大小决定是否压缩；SSE 流和 WebSocket 连接不做处理，直接透传。
+
+// gzipMinSize 小于该字节数的响应不压缩，压缩收益抵不过开销
+const gzipMinSize = 1024
+
+// gzipMiddleware 对支持 gzip 的客户端压缩较大的响应
+func gzipMiddleware() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if !acceptsGzip(c.GetHeader("Accept-Encoding")) || c.GetHeader("Upgrade") != "" {
+			c.Next()
+			return
+		}
+
+		writer := &gzipResponseWriter{ResponseWriter: c.Writer}
+		c.Writer = writer
+		defer writer.finish()
+
+		c.Next()
+	}
+}
+
+// acceptsGzip 判断 Accept-Encoding 是否接受 gzip，q=0 表示明确拒绝
+func acceptsGzip(acceptEncoding string) bool {
+	for part := range strings.SplitSeq(acceptEncoding, ",") {
+		name, params, _ := strings.Cut(strings.TrimSpace(part), ";")
+		name = strings.ToLower(strings.TrimSpace(name))
+		if name != "gzip" && name != "*" {
+			continue
+		}
+		if q, ok := strings.CutPrefix(strings.ReplaceAll(params, " ", ""), "q="); ok && strings.Trim(q, "0.") == "" {
+			return false
+		}
+		return true
+	}
+	return false
+}
+
+// gzipResponseWriter 缓存响应内容，结束时按大小决定是否压缩。
+// 响应是 SSE 流或被 Flush 时切换为透传，保证流式输出不被缓存
+type gzipResponseWriter struct {
+	gin.ResponseWriter
+	buf         bytes.Buffer
+	passthrough bool
+}
+
+// Write 缓存响应内容，SSE 流直接写出
+func (w *gzipResponseWriter) Write(data []byte) (int, error) {
+	if !w.passthrough && strings.HasPrefix(w.Header().Get("Content-Type"), "text/event-stream") {
+		w.startPassthrough()
+	}
+	if w.passthrough {
+		return w.ResponseWriter.Write(data)
+	}
+	return w.buf.Write(data)
+}
+
+// WriteString 同 Write
+func (w *gzipResponseWriter) WriteString(s string) (int, error) {
+	return w.Write([]byte(s))
+}
+
+// Flush 调用方需要立即输出时不再缓存，写出已缓存的内容并透传后续响应
+func (w *gzipResponseWriter) Flush() {
+	if !w.passthrough {
+		w.startPassthrough()
+	}
+	w.ResponseWriter.Flush()
+}
+
+// startPassthrough 写出已缓存的内容，之后的写入直接透传
+func (w *gzipResponseWriter) startPassthrough() {
+	w.passthrough = true
+	if w.buf.Len() > 0 {
+		_, _ = w.ResponseWriter.Write(w.buf.Bytes())
+		w.buf.Reset()
+	}
+}
+
+// finish 处理结束后写出缓存的响应，足够大时以 gzip 压缩
+func (w *gzipResponseWriter) finish() {
+	if w.passthrough || w.buf.Len() == 0 {
+		return
+	}
+
+	header := w.Header()
+	header.Add("Vary", "Accept-Encoding")
+
+	// 响应头已经发出，或内容已经编码过时，只能原样写出
+	if w.buf.Len() < gzipMinSize || w.ResponseWriter.Written() || header.Get("Content-Encoding") != "" {
+		_, _ = w.ResponseWriter.Write(w.buf.Bytes())
+		return
+	}
+
+	header.Set("Content-Encoding", "gzip")
+	header.Del("Content-Length")
+
+	gz := gzip.NewWriter(w.ResponseWriter)
+	if _, err := gz.Write(w.buf.Bytes()); err != nil {
+		logrus.WithError(err).Warn("写出 gzip 响应失败")
+	}
+	if err := gz.Close(); err != nil {
+		logrus.WithError(err).Warn("写出 gzip 响应失败")
+	}
+}
@@ -0,0 +1,104 @@
+package main
+
+import (
+	"compress/gzip"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// newGzipTestRouter 创建只挂了 gzipMiddleware 的路由：/json 返回较大的 JSON，/small 返回小响应，/sse 返回 SSE 流
+func newGzipTestRouter(body string) *gin.Engine {
+	gin.SetMode(gin.ReleaseMode)
+	router := gin.New()
+	router.Use(gzipMiddleware())
+	router.GET("/json", func(c *gin.Context) {
+		c.JSON(http.StatusOK, gin.H{"data": body})
+	})
+	router.GET("/small", func(c *gin.Context) {
+		c.JSON(http.StatusOK, gin.H{"ok": true})
+	})
+	router.GET("/sse", func(c *gin.Context) {
+		c.Header("Content-Type", "text/event-stream")
+		c.String(http.StatusOK, "data: %s\n\n", body)
+	})
+	return router
+}
+
+func TestGzipMiddleware(t *testing.T) {
+	body := strings.Repeat("小红书", gzipMinSize)
+	router := newGzipTestRouter(body)
+
+	request := func(target string, header map[string]string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodGet, target, nil)
+		for k, v := range header {
+			req.Header.Set(k, v)
+		}
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		return w
+	}
+
+	t.Run("gzip", func(t *testing.T) {
+		w := request("/json", map[string]string{"Accept-Encoding": "gzip, deflate"})
+		require.Equal(t, http.StatusOK, w.Code)
+		assert.Equal(t, "gzip", w.Header().Get("Content-Encoding"))
+		assert.Equal(t, "Accept-Encoding", w.Header().Get("Vary"))
+
+		reader, err := gzip.NewReader(w.Body)
+		require.NoError(t, err)
+		data, err := io.ReadAll(reader)
+		require.NoError(t, err)
+		assert.Contains(t, string(data), body)
+	})
+
+	t.Run("identity", func(t *testing.T) {
+		for _, encoding := range []string{"", "identity", "gzip;q=0"} {
+			w := request("/json", map[string]string{"Accept-Encoding": encoding})
+			assert.Empty(t, w.Header().Get("Content-Encoding"), encoding)
+			assert.Contains(t, w.Body.String(), body, encoding)
+		}
+	})
+
+	t.Run("small response", func(t *testing.T) {
+		w := request("/small", map[string]string{"Accept-Encoding": "gzip"})
+		assert.Empty(t, w.Header().Get("Content-Encoding"))
+		assert.JSONEq(t, `{"ok": true}`, w.Body.String())
+	})
+
+	t.Run("sse bypass", func(t *testing.T) {
+		w := request("/sse", map[string]string{"Accept-Encoding": "gzip"})
+		assert.Empty(t, w.Header().Get("Content-Encoding"))
+		assert.Equal(t, "data: "+body+"\n\n", w.Body.String())
+	})
+
+	t.Run("websocket bypass", func(t *testing.T) {
+		w := request("/json", map[string]string{"Accept-Encoding": "gzip", "Upgrade": "websocket", "Connection": "Upgrade"})
+		assert.Empty(t, w.Header().Get("Content-Encoding"))
+		assert.Contains(t, w.Body.String(), body)
+	})
+}
+
+func TestAcceptsGzip(t *testing.T) {
+	tests := map[string]bool{
+		"":                  false,
+		"gzip":              true,
+		"GZIP":              true,
+		"deflate, gzip":     true,
+		"gzip;q=0.5":        true,
+		"gzip;q=0":          false,
+		"gzip; q=0.0":       false,
+		"*":                 true,
+		"identity":          false,
+		"br;q=1, gzip;q=0.": false,
+	}
+	for header, want := range tests {
+		assert.Equal(t, want, acceptsGzip(header), header)
+	}
+}
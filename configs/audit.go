@@ -0,0 +1,32 @@
+package configs
+
+// DefaultAuditLogMaxSize 审计日志文件的默认大小上限，超出后轮转
+const DefaultAuditLogMaxSize = 10 << 20
+
+var (
+	auditLogPath    = ""
+	auditLogMaxSize = int64(DefaultAuditLogMaxSize)
+)
+
+// SetAuditLogPath 设置审计日志文件路径，为空表示不记录审计日志
+func SetAuditLogPath(path string) {
+	auditLogPath = path
+}
+
+// GetAuditLogPath 获取审计日志文件路径
+func GetAuditLogPath() string {
+	return auditLogPath
+}
+
+// SetAuditLogMaxSize 设置审计日志文件的大小上限（字节），小于等于 0 时使用默认值
+func SetAuditLogMaxSize(size int64) {
+	if size <= 0 {
+		size = DefaultAuditLogMaxSize
+	}
+	auditLogMaxSize = size
+}
+
+// GetAuditLogMaxSize 获取审计日志文件的大小上限（字节）
+func GetAuditLogMaxSize() int64 {
+	return auditLogMaxSize
+}
@@ -0,0 +1,88 @@
+package configs
+
+import (
+	"fmt"
+	"net/url"
+	"strings"
+)
+
+const (
+	// DefaultBaseURL 小红书网页版地址
+	DefaultBaseURL = "https://www.xiaohongshu.com"
+	// DefaultCreatorBaseURL 小红书创作者中心地址，发布、编辑笔记和话题搜索在这里进行
+	DefaultCreatorBaseURL = "https://creator.xiaohongshu.com"
+)
+
+var (
+	baseURL        = DefaultBaseURL
+	creatorBaseURL = DefaultCreatorBaseURL
+)
+
+// SetBaseURL 设置小红书网页版地址，用于接入其他域名、边缘节点或本地模拟服务，为空时使用默认地址
+func SetBaseURL(s string) error {
+	u, err := parseBaseURL(s, DefaultBaseURL)
+	if err != nil {
+		return err
+	}
+	baseURL = u
+	return nil
+}
+
+// SetCreatorBaseURL 设置创作者中心地址，为空时使用默认地址
+func SetCreatorBaseURL(s string) error {
+	u, err := parseBaseURL(s, DefaultCreatorBaseURL)
+	if err != nil {
+		return err
+	}
+	creatorBaseURL = u
+	return nil
+}
+
+// GetBaseURL 获取小红书网页版地址，不带末尾的 /
+func GetBaseURL() string {
+	return baseURL
+}
+
+// GetCreatorBaseURL 获取创作者中心地址，不带末尾的 /
+func GetCreatorBaseURL() string {
+	return creatorBaseURL
+}
+
+// SiteURL 拼接网页版页面地址，path 以 / 开头，可以带查询参数
+func SiteURL(path string) string {
+	return baseURL + path
+}
+
+// CreatorURL 拼接创作者中心页面地址，path 以 / 开头，可以带查询参数
+func CreatorURL(path string) string {
+	return creatorBaseURL + path
+}
+
+// IsSiteHost 判断主机名是否属于小红书，包括 xiaohongshu.com 的子域名和配置的网页版、创作者中心地址
+func IsSiteHost(host string) bool {
+	host = strings.ToLower(host)
+	if host == "xiaohongshu.com" || strings.HasSuffix(host, ".xiaohongshu.com") {
+		return true
+	}
+
+	for _, base := range []string{baseURL, creatorBaseURL} {
+		if u, err := url.Parse(base); err == nil && strings.EqualFold(u.Hostname(), host) {
+			return true
+		}
+	}
+	return false
+}
+
+// parseBaseURL 校验 scheme://host[:port] 形式的地址并去掉末尾的 /
+func parseBaseURL(s, fallback string) (string, error) {
+	s = strings.TrimRight(strings.TrimSpace(s), "/")
+	if s == "" {
+		return fallback, nil
+	}
+
+	u, err := url.Parse(s)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" || u.Path != "" {
+		return "", fmt.Errorf("地址格式错误，应为 scheme://host[:port]: %q", s)
+	}
+	return s, nil
+}
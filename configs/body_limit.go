@@ -0,0 +1,20 @@
+package configs
+
+// DefaultMaxBodyBytes 请求体的默认字节数上限，需要容纳以 data URL 传入的多张图片
+const DefaultMaxBodyBytes = 64 << 20
+
+// maxBodyBytes 请求体的字节数上限
+var maxBodyBytes int64 = DefaultMaxBodyBytes
+
+// SetMaxBodyBytes 设置请求体的字节数上限，小于等于 0 时使用默认值
+func SetMaxBodyBytes(n int64) {
+	if n <= 0 {
+		n = DefaultMaxBodyBytes
+	}
+	maxBodyBytes = n
+}
+
+// GetMaxBodyBytes 获取请求体的字节数上限
+func GetMaxBodyBytes() int64 {
+	return maxBodyBytes
+}
@@ -0,0 +1,135 @@
+package configs
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// DefaultUserAgent 默认使用桌面版 Chrome 的 UA，小红书对不同 UA 会返回不同的页面布局
+const DefaultUserAgent = "Mozilla/5.0 (Macintosh; Intel Mac OS X 10_15_7) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/124.0.0.0 Safari/537.36"
+
+// DefaultLang 默认浏览器语言。小红书会按语言调整部分内容以及数字、日期的格式，
+// 统一使用中文，避免英文系统下返回的计数、时间格式不同导致解析失败
+const DefaultLang = "zh-CN"
+
+// DefaultViewport 默认视口大小。部分元素（如发布页的侧边栏）只在足够宽的窗口下渲染，
+// 不建议设置小于 1280x800 的视口。
+const DefaultViewport = "1280x800"
+
+// Viewport 浏览器视口大小
+type Viewport struct {
+	Width  int
+	Height int
+}
+
+// String 返回 宽x高 形式的视口描述
+func (v Viewport) String() string {
+	return fmt.Sprintf("%dx%d", v.Width, v.Height)
+}
+
+var (
+	userAgent = DefaultUserAgent
+	lang      = DefaultLang
+	viewport  = Viewport{Width: 1280, Height: 800}
+)
+
+// langPattern 语言标签，如 zh-CN、en、zh-Hans-CN
+var langPattern = regexp.MustCompile(`^[A-Za-z]{2,3}(-[A-Za-z0-9]{2,8})*$`)
+
+// SetUserAgent 设置浏览器 UA，为空时使用默认 UA
+func SetUserAgent(ua string) {
+	if ua == "" {
+		ua = DefaultUserAgent
+	}
+	userAgent = ua
+}
+
+// GetUserAgent 获取浏览器 UA
+func GetUserAgent() string {
+	return userAgent
+}
+
+// SetLang 设置浏览器语言，如 zh-CN，为空时使用默认语言
+func SetLang(s string) error {
+	s = strings.TrimSpace(s)
+	if s == "" {
+		s = DefaultLang
+	}
+	if !langPattern.MatchString(s) {
+		return fmt.Errorf("语言格式错误，应为 zh-CN、en-US 这样的语言标签: %q", s)
+	}
+	lang = s
+	return nil
+}
+
+// GetLang 获取浏览器语言
+func GetLang() string {
+	return lang
+}
+
+// GetAcceptLanguage 浏览器请求头中的 Accept-Language，带地区的语言同时附上不带地区的语言，
+// 如 zh-CN 对应 zh-CN,zh;q=0.9
+func GetAcceptLanguage() string {
+	base, _, found := strings.Cut(lang, "-")
+	if !found {
+		return lang
+	}
+	return lang + "," + base + ";q=0.9"
+}
+
+// GetLocale 浏览器页面中 Intl 使用的 ICU 区域设置，如 zh-CN 对应 zh_CN
+func GetLocale() string {
+	return strings.ReplaceAll(lang, "-", "_")
+}
+
+// SetViewport 设置浏览器视口大小，格式为 宽x高，如 1280x800
+func SetViewport(s string) error {
+	v, err := ParseViewport(s)
+	if err != nil {
+		return err
+	}
+	viewport = v
+	return nil
+}
+
+// GetViewport 获取浏览器视口大小
+func GetViewport() Viewport {
+	return viewport
+}
+
+// ParseViewport 解析 宽x高 形式的视口大小
+func ParseViewport(s string) (Viewport, error) {
+	parts := strings.Split(strings.ToLower(strings.TrimSpace(s)), "x")
+	if len(parts) != 2 {
+		return Viewport{}, fmt.Errorf("视口格式错误，应为 宽x高，如 1280x800: %q", s)
+	}
+
+	width, errW := strconv.Atoi(strings.TrimSpace(parts[0]))
+	height, errH := strconv.Atoi(strings.TrimSpace(parts[1]))
+	if errW != nil || errH != nil || width <= 0 || height <= 0 {
+		return Viewport{}, fmt.Errorf("视口格式错误，应为 宽x高，如 1280x800: %q", s)
+	}
+
+	return Viewport{Width: width, Height: height}, nil
+}
+
+// chromeArgs 额外的 Chromium 启动参数
+var chromeArgs []string
+
+// SetChromeArgs 设置额外的 Chromium 启动参数，如 --disable-dev-shm-usage。每个参数都必须以 -- 开头
+func SetChromeArgs(args []string) error {
+	for _, arg := range args {
+		if !strings.HasPrefix(arg, "--") || len(arg) == 2 {
+			return fmt.Errorf("Chromium 启动参数必须以 -- 开头: %q", arg)
+		}
+	}
+	chromeArgs = args
+	return nil
+}
+
+// GetChromeArgs 获取额外的 Chromium 启动参数
+func GetChromeArgs() []string {
+	return chromeArgs
+}
@@ -0,0 +1,37 @@
+package configs
+
+import "time"
+
+const (
+	// DefaultMaxBrowsers 默认最多同时运行的浏览器数量
+	DefaultMaxBrowsers = 4
+	// DefaultBrowserWait 浏览器数量达到上限时，请求默认最多排队等待的时间
+	DefaultBrowserWait = 30 * time.Second
+)
+
+var (
+	// maxBrowsers 最多同时运行的浏览器数量，0 表示不限制
+	maxBrowsers = DefaultMaxBrowsers
+	// browserWait 浏览器数量达到上限时，请求最多排队等待的时间
+	browserWait = DefaultBrowserWait
+)
+
+// SetMaxBrowsers 设置最多同时运行的浏览器数量，小于等于 0 表示不限制
+func SetMaxBrowsers(n int) {
+	maxBrowsers = max(n, 0)
+}
+
+// GetMaxBrowsers 获取最多同时运行的浏览器数量
+func GetMaxBrowsers() int {
+	return maxBrowsers
+}
+
+// SetBrowserWait 设置请求排队等待浏览器的最长时间，0 表示不等待，达到上限时直接返回繁忙
+func SetBrowserWait(d time.Duration) {
+	browserWait = max(d, 0)
+}
+
+// GetBrowserWait 获取请求排队等待浏览器的最长时间
+func GetBrowserWait() time.Duration {
+	return browserWait
+}
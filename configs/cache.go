@@ -0,0 +1,36 @@
+package configs
+
+import "time"
+
+// DefaultCacheMaxEntries 每类缓存默认最多保留的条目数
+const DefaultCacheMaxEntries = 500
+
+var (
+	// cacheTTL 笔记详情和搜索结果的缓存时间，0 表示不缓存
+	cacheTTL time.Duration
+	// cacheMaxEntries 每类缓存最多保留的条目数，超出时淘汰最久未使用的条目
+	cacheMaxEntries = DefaultCacheMaxEntries
+)
+
+// SetCacheTTL 设置笔记详情和搜索结果的缓存时间，小于等于 0 表示不缓存
+func SetCacheTTL(d time.Duration) {
+	cacheTTL = max(d, 0)
+}
+
+// GetCacheTTL 获取笔记详情和搜索结果的缓存时间
+func GetCacheTTL() time.Duration {
+	return cacheTTL
+}
+
+// SetCacheMaxEntries 设置每类缓存最多保留的条目数，小于等于 0 时使用默认值
+func SetCacheMaxEntries(n int) {
+	if n <= 0 {
+		n = DefaultCacheMaxEntries
+	}
+	cacheMaxEntries = n
+}
+
+// GetCacheMaxEntries 获取每类缓存最多保留的条目数
+func GetCacheMaxEntries() int {
+	return cacheMaxEntries
+}
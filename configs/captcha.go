@@ -0,0 +1,19 @@
+package configs
+
+import "time"
+
+// DefaultCaptchaWait 有界面模式下等待人工完成验证码的默认时间
+const DefaultCaptchaWait = 2 * time.Minute
+
+// captchaWait 等待人工完成验证码的最长时间，0 表示不等待
+var captchaWait = DefaultCaptchaWait
+
+// SetCaptchaWait 设置等待人工完成验证码的最长时间，0 表示遇到验证码直接失败
+func SetCaptchaWait(d time.Duration) {
+	captchaWait = max(d, 0)
+}
+
+// GetCaptchaWait 获取等待人工完成验证码的最长时间
+func GetCaptchaWait() time.Duration {
+	return captchaWait
+}
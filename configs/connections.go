@@ -0,0 +1,17 @@
+package configs
+
+// DefaultMaxConnections 默认最多同时保持的 SSE/WebSocket 连接数
+const DefaultMaxConnections = 100
+
+// maxConnections 最多同时保持的 SSE/WebSocket 连接数，0 表示不限制
+var maxConnections = DefaultMaxConnections
+
+// SetMaxConnections 设置最多同时保持的 SSE/WebSocket 连接数，小于等于 0 表示不限制
+func SetMaxConnections(n int) {
+	maxConnections = max(n, 0)
+}
+
+// GetMaxConnections 获取最多同时保持的 SSE/WebSocket 连接数
+func GetMaxConnections() int {
+	return maxConnections
+}
@@ -0,0 +1,21 @@
+package configs
+
+// DefaultMaxContentWidth 正文默认的最大宽度，小红书正文最多 1000 字，按中文字计为 2000。
+// 中文/日文/韩文占 2，英文/数字占 1，与标题的计算方式相同
+const DefaultMaxContentWidth = 2000
+
+// maxContentWidth 正文的最大宽度
+var maxContentWidth = DefaultMaxContentWidth
+
+// SetMaxContentWidth 设置正文的最大宽度，小于等于 0 时使用默认值
+func SetMaxContentWidth(n int) {
+	if n <= 0 {
+		n = DefaultMaxContentWidth
+	}
+	maxContentWidth = n
+}
+
+// GetMaxContentWidth 获取正文的最大宽度
+func GetMaxContentWidth() int {
+	return maxContentWidth
+}
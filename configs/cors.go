@@ -0,0 +1,36 @@
+package configs
+
+import (
+	"fmt"
+	"net/url"
+	"strings"
+)
+
+// corsOrigins 允许跨域访问的来源，为空表示允许任意来源（*）
+var corsOrigins []string
+
+// SetCORSOrigins 设置允许跨域访问的来源，逗号分隔，如 https://a.example.com,http://localhost:3000。
+// 为空时允许任意来源，仅适合本地开发
+func SetCORSOrigins(s string) error {
+	var origins []string
+	for _, origin := range strings.Split(s, ",") {
+		origin = strings.TrimRight(strings.TrimSpace(origin), "/")
+		if origin == "" {
+			continue
+		}
+
+		u, err := url.Parse(origin)
+		if err != nil || u.Scheme == "" || u.Host == "" || u.Path != "" {
+			return fmt.Errorf("来源格式错误，应为 scheme://host[:port]: %q", origin)
+		}
+		origins = append(origins, origin)
+	}
+
+	corsOrigins = origins
+	return nil
+}
+
+// GetCORSOrigins 获取允许跨域访问的来源，为空表示允许任意来源
+func GetCORSOrigins() []string {
+	return corsOrigins
+}
@@ -0,0 +1,14 @@
+package configs
+
+// debug 是否开启调试模式，开启后提供 debug_page_html 等仅供维护者排查问题的工具
+var debug = false
+
+// SetDebug 设置是否开启调试模式
+func SetDebug(enabled bool) {
+	debug = enabled
+}
+
+// IsDebug 是否开启调试模式
+func IsDebug() bool {
+	return debug
+}
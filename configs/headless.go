@@ -0,0 +1,64 @@
+package configs
+
+import (
+	"fmt"
+	"strings"
+)
+
+// HeadlessMode 浏览器的无头模式
+type HeadlessMode string
+
+const (
+	// HeadlessModeLegacy 旧版无头模式（--headless），与 -headless=true 的行为一致
+	HeadlessModeLegacy HeadlessMode = "true"
+	// HeadlessModeOff 有浏览器界面
+	HeadlessModeOff HeadlessMode = "false"
+	// HeadlessModeNew 新版无头模式（--headless=new），与有界面的 Chrome 使用同一套渲染流程
+	HeadlessModeNew HeadlessMode = "new"
+)
+
+// headlessMode 当前使用的无头模式
+var headlessMode = HeadlessModeLegacy
+
+// SetHeadlessMode 设置无头模式，可选 true、false、new，同时更新 IsHeadless 的结果
+func SetHeadlessMode(s string) error {
+	mode := HeadlessMode(strings.ToLower(strings.TrimSpace(s)))
+	switch mode {
+	case HeadlessModeLegacy, HeadlessModeOff, HeadlessModeNew:
+	default:
+		return fmt.Errorf("无头模式只能是 true、false 或 new: %q", s)
+	}
+
+	headlessMode = mode
+	InitHeadless(mode != HeadlessModeOff)
+	return nil
+}
+
+// GetHeadlessMode 获取当前使用的无头模式
+func GetHeadlessMode() HeadlessMode {
+	return headlessMode
+}
+
+// allowVisibleBrowser 是否允许单次请求使用有浏览器界面的模式
+var allowVisibleBrowser bool
+
+// SetAllowVisibleBrowser 设置是否允许单次请求（如 visible=true）使用有浏览器界面的模式。
+// 服务器没有图形界面时有界面的浏览器无法启动，默认不允许
+func SetAllowVisibleBrowser(allow bool) {
+	allowVisibleBrowser = allow
+}
+
+// IsVisibleBrowserAllowed 是否允许单次请求使用有浏览器界面的模式
+func IsVisibleBrowserAllowed() bool {
+	return allowVisibleBrowser
+}
+
+// GetLaunchChromeArgs 返回启动浏览器时使用的全部额外参数：-chrome-arg 指定的参数，
+// 以无头方式启动且使用新版无头模式时再追加 --headless=new，覆盖启动器默认的旧版 --headless
+func GetLaunchChromeArgs(headless bool) []string {
+	args := GetChromeArgs()
+	if headless && headlessMode == HeadlessModeNew {
+		args = append(args[:len(args):len(args)], "--headless=new")
+	}
+	return args
+}
@@ -0,0 +1,47 @@
+package configs
+
+import (
+	"fmt"
+	"time"
+)
+
+const (
+	// DefaultHumanizeMinDelay 模拟人工操作时，两次点击/输入之间的默认最短间隔
+	DefaultHumanizeMinDelay = 50 * time.Millisecond
+	// DefaultHumanizeMaxDelay 模拟人工操作时，两次点击/输入之间的默认最长间隔
+	DefaultHumanizeMaxDelay = 300 * time.Millisecond
+)
+
+var (
+	// humanize 是否模拟人工操作：点击前随机停顿，文本逐字输入
+	humanize bool
+	// humanizeMinDelay、humanizeMaxDelay 随机停顿的范围
+	humanizeMinDelay = DefaultHumanizeMinDelay
+	humanizeMaxDelay = DefaultHumanizeMaxDelay
+)
+
+// SetHumanize 设置是否模拟人工操作
+func SetHumanize(enabled bool) {
+	humanize = enabled
+}
+
+// IsHumanize 是否模拟人工操作
+func IsHumanize() bool {
+	return humanize
+}
+
+// SetHumanizeDelay 设置模拟人工操作时随机停顿的范围
+func SetHumanizeDelay(minDelay, maxDelay time.Duration) error {
+	if minDelay < 0 || maxDelay < minDelay {
+		return fmt.Errorf("停顿范围无效: %s-%s，需满足 0 <= 最短间隔 <= 最长间隔", minDelay, maxDelay)
+	}
+
+	humanizeMinDelay = minDelay
+	humanizeMaxDelay = maxDelay
+	return nil
+}
+
+// GetHumanizeDelay 获取模拟人工操作时随机停顿的范围
+func GetHumanizeDelay() (time.Duration, time.Duration) {
+	return humanizeMinDelay, humanizeMaxDelay
+}
@@ -0,0 +1,59 @@
+package configs
+
+import "time"
+
+// DefaultMaxImages 单篇图文笔记默认最多可上传的图片数，与小红书的限制一致
+const DefaultMaxImages = 18
+
+// maxImages 单篇笔记最多可上传的图片数
+var maxImages = DefaultMaxImages
+
+// SetMaxImages 设置单篇笔记最多可上传的图片数，小于等于 0 时使用默认值
+func SetMaxImages(n int) {
+	if n <= 0 {
+		n = DefaultMaxImages
+	}
+	maxImages = n
+}
+
+// GetMaxImages 获取单篇笔记最多可上传的图片数
+func GetMaxImages() int {
+	return maxImages
+}
+
+const (
+	// DefaultImageDownloadWorkers 默认同时下载的 URL 图片数量
+	DefaultImageDownloadWorkers = 4
+	// DefaultImageDownloadTimeout 默认的单张图片下载超时时间
+	DefaultImageDownloadTimeout = 30 * time.Second
+)
+
+var (
+	// imageDownloadWorkers 同时下载的 URL 图片数量
+	imageDownloadWorkers = DefaultImageDownloadWorkers
+	// imageDownloadTimeout 单张图片的下载超时时间
+	imageDownloadTimeout = DefaultImageDownloadTimeout
+)
+
+// SetImageDownloadWorkers 设置同时下载的 URL 图片数量，小于等于 0 时使用默认值
+func SetImageDownloadWorkers(n int) {
+	if n <= 0 {
+		n = DefaultImageDownloadWorkers
+	}
+	imageDownloadWorkers = n
+}
+
+// GetImageDownloadWorkers 获取同时下载的 URL 图片数量
+func GetImageDownloadWorkers() int {
+	return imageDownloadWorkers
+}
+
+// SetImageDownloadTimeout 设置单张图片的下载超时时间，0 表示只受写操作超时限制
+func SetImageDownloadTimeout(d time.Duration) {
+	imageDownloadTimeout = max(d, 0)
+}
+
+// GetImageDownloadTimeout 获取单张图片的下载超时时间
+func GetImageDownloadTimeout() time.Duration {
+	return imageDownloadTimeout
+}
@@ -0,0 +1,23 @@
+package configs
+
+import "fmt"
+
+// DefaultLogSampleRate 默认记录全部成功请求的访问日志
+const DefaultLogSampleRate = 1.0
+
+// logSampleRate 成功请求记录访问日志的比例，失败请求始终记录
+var logSampleRate = DefaultLogSampleRate
+
+// SetLogSampleRate 设置成功请求记录访问日志的比例，取值范围 0 到 1
+func SetLogSampleRate(rate float64) error {
+	if rate < 0 || rate > 1 {
+		return fmt.Errorf("采样比例无效: %v，应在 0 到 1 之间", rate)
+	}
+	logSampleRate = rate
+	return nil
+}
+
+// GetLogSampleRate 获取成功请求记录访问日志的比例
+func GetLogSampleRate() float64 {
+	return logSampleRate
+}
@@ -0,0 +1,19 @@
+package configs
+
+import "time"
+
+// DefaultLoginCheckInterval 后台检查登录状态的默认间隔
+const DefaultLoginCheckInterval = 30 * time.Minute
+
+// loginCheckInterval 后台检查登录状态的间隔，0 表示不检查
+var loginCheckInterval = DefaultLoginCheckInterval
+
+// SetLoginCheckInterval 设置后台检查登录状态的间隔，小于等于 0 表示不检查
+func SetLoginCheckInterval(d time.Duration) {
+	loginCheckInterval = max(d, 0)
+}
+
+// GetLoginCheckInterval 获取后台检查登录状态的间隔
+func GetLoginCheckInterval() time.Duration {
+	return loginCheckInterval
+}
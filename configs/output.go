@@ -0,0 +1,52 @@
+package configs
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// DefaultMaxOutputBytes MCP 工具结果文本的默认字节数上限，避免一次返回的内容超出模型上下文
+const DefaultMaxOutputBytes = 100 * 1024
+
+var (
+	// maxOutputBytes 工具结果文本的字节数上限，0 表示不限制
+	maxOutputBytes = DefaultMaxOutputBytes
+	// toolOutputLimits 按工具名单独配置的上限，优先于 maxOutputBytes
+	toolOutputLimits map[string]int
+)
+
+// SetMaxOutputBytes 设置工具结果文本的字节数上限，0 表示不限制
+func SetMaxOutputBytes(n int) {
+	maxOutputBytes = max(n, 0)
+}
+
+// SetToolOutputLimits 按工具设置结果文本的字节数上限，格式为 name=bytes，逗号分隔，
+// 如 list_feeds=50000,get_feed_detail=0，0 表示该工具不限制
+func SetToolOutputLimits(s string) error {
+	limits := make(map[string]int)
+	for _, item := range strings.Split(s, ",") {
+		item = strings.TrimSpace(item)
+		if item == "" {
+			continue
+		}
+
+		name, value, ok := strings.Cut(item, "=")
+		n, err := strconv.Atoi(strings.TrimSpace(value))
+		if !ok || strings.TrimSpace(name) == "" || err != nil || n < 0 {
+			return fmt.Errorf("格式错误，应为 工具名=字节数: %q", item)
+		}
+		limits[strings.TrimSpace(name)] = n
+	}
+
+	toolOutputLimits = limits
+	return nil
+}
+
+// GetMaxOutputBytes 获取指定工具结果文本的字节数上限，0 表示不限制
+func GetMaxOutputBytes(tool string) int {
+	if n, ok := toolOutputLimits[tool]; ok {
+		return n
+	}
+	return maxOutputBytes
+}
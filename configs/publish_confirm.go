@@ -0,0 +1,16 @@
+package configs
+
+import "time"
+
+// publishConfirmTimeout 点击发布后等待发布成功提示的最长时间，0 表示不等待
+var publishConfirmTimeout time.Duration
+
+// SetPublishConfirmTimeout 设置点击发布后等待发布成功提示的最长时间，0 表示不等待
+func SetPublishConfirmTimeout(d time.Duration) {
+	publishConfirmTimeout = max(d, 0)
+}
+
+// GetPublishConfirmTimeout 获取点击发布后等待发布成功提示的最长时间
+func GetPublishConfirmTimeout() time.Duration {
+	return publishConfirmTimeout
+}
@@ -0,0 +1,14 @@
+package configs
+
+// publishVerify 发布后是否到创作者中心确认笔记是否真的发布成功
+var publishVerify bool
+
+// SetPublishVerify 设置发布后是否确认笔记是否真的发布成功
+func SetPublishVerify(enabled bool) {
+	publishVerify = enabled
+}
+
+// IsPublishVerify 发布后是否确认笔记是否真的发布成功
+func IsPublishVerify() bool {
+	return publishVerify
+}
@@ -0,0 +1,29 @@
+package configs
+
+import "time"
+
+const (
+	// DefaultRateLimitCooldown 平台提示操作过于频繁后，默认暂停写操作的时间
+	DefaultRateLimitCooldown = 10 * time.Minute
+	// DefaultRateLimitMaxCooldown 连续被限流时暂停时间按指数增长的默认上限
+	DefaultRateLimitMaxCooldown = 2 * time.Hour
+)
+
+var (
+	// rateLimitCooldown 平台提示操作过于频繁后，暂停写操作的初始时间
+	rateLimitCooldown = DefaultRateLimitCooldown
+	// rateLimitMaxCooldown 连续被限流时暂停时间的上限
+	rateLimitMaxCooldown = DefaultRateLimitMaxCooldown
+)
+
+// SetRateLimitCooldown 设置被平台限流后暂停写操作的初始时间和上限，0 表示不暂停。
+// 上限小于初始时间时按初始时间处理
+func SetRateLimitCooldown(cooldown, maxCooldown time.Duration) {
+	rateLimitCooldown = max(cooldown, 0)
+	rateLimitMaxCooldown = max(maxCooldown, rateLimitCooldown)
+}
+
+// GetRateLimitCooldown 获取被平台限流后暂停写操作的初始时间和上限
+func GetRateLimitCooldown() (time.Duration, time.Duration) {
+	return rateLimitCooldown, rateLimitMaxCooldown
+}
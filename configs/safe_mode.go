@@ -0,0 +1,14 @@
+package configs
+
+// safeMode 安全模式，开启后拒绝发布、编辑、评论等会修改账号内容的操作
+var safeMode bool
+
+// SetSafeMode 设置是否开启安全模式
+func SetSafeMode(enabled bool) {
+	safeMode = enabled
+}
+
+// IsSafeMode 是否开启安全模式
+func IsSafeMode() bool {
+	return safeMode
+}
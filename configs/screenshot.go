@@ -0,0 +1,51 @@
+package configs
+
+import (
+	"os"
+	"path/filepath"
+)
+
+// DefaultMaxScreenshots 默认最多保留的错误截图数量，超出时删除最早的截图
+const DefaultMaxScreenshots = 50
+
+var (
+	screenshotOnError = false
+	screenshotDir     = ""
+	maxScreenshots    = DefaultMaxScreenshots
+)
+
+// SetScreenshotOnError 设置操作失败时是否保存页面截图
+func SetScreenshotOnError(enabled bool) {
+	screenshotOnError = enabled
+}
+
+// IsScreenshotOnError 操作失败时是否保存页面截图
+func IsScreenshotOnError() bool {
+	return screenshotOnError
+}
+
+// SetScreenshotDir 设置错误截图的保存目录
+func SetScreenshotDir(dir string) {
+	screenshotDir = dir
+}
+
+// GetScreenshotDir 获取错误截图的保存目录，默认为系统临时目录下的 xiaohongshu-mcp-screenshots
+func GetScreenshotDir() string {
+	if screenshotDir == "" {
+		return filepath.Join(os.TempDir(), "xiaohongshu-mcp-screenshots")
+	}
+	return screenshotDir
+}
+
+// SetMaxScreenshots 设置最多保留的错误截图数量，小于等于 0 时使用默认值
+func SetMaxScreenshots(n int) {
+	if n <= 0 {
+		n = DefaultMaxScreenshots
+	}
+	maxScreenshots = n
+}
+
+// GetMaxScreenshots 获取最多保留的错误截图数量
+func GetMaxScreenshots() int {
+	return maxScreenshots
+}
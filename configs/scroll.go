@@ -0,0 +1,20 @@
+package configs
+
+// DefaultMaxScrolls 单次提取默认最多滚动的次数
+const DefaultMaxScrolls = 30
+
+// maxScrolls 单次提取最多滚动的次数，与请求的 limit 无关
+var maxScrolls = DefaultMaxScrolls
+
+// SetMaxScrolls 设置单次提取最多滚动的次数，小于 1 时使用默认值
+func SetMaxScrolls(n int) {
+	if n < 1 {
+		n = DefaultMaxScrolls
+	}
+	maxScrolls = n
+}
+
+// GetMaxScrolls 获取单次提取最多滚动的次数
+func GetMaxScrolls() int {
+	return maxScrolls
+}
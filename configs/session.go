@@ -0,0 +1,44 @@
+package configs
+
+import (
+	"os"
+	"path/filepath"
+)
+
+// cookiesFileName 持久化 cookies 的文件名
+const cookiesFileName = "cookies.json"
+
+// sessionDirName 默认会话目录在用户配置目录下的名称
+const sessionDirName = "xiaohongshu-mcp"
+
+// sessionDir 登录会话（cookies）的保存目录，为空时使用 DefaultSessionDir
+var sessionDir = ""
+
+// SetSessionDir 设置登录会话的保存目录
+func SetSessionDir(dir string) {
+	sessionDir = dir
+}
+
+// GetSessionDir 获取登录会话的保存目录
+func GetSessionDir() string {
+	if sessionDir == "" {
+		return DefaultSessionDir()
+	}
+	return sessionDir
+}
+
+// DefaultSessionDir 默认的登录会话目录：用户配置目录下的 xiaohongshu-mcp，
+// 如 Linux 的 ~/.config/xiaohongshu-mcp、macOS 的 ~/Library/Application Support/xiaohongshu-mcp，
+// 重启或清理临时文件后登录态仍然保留。无法确定用户配置目录（如未设置 HOME）时使用系统临时目录
+func DefaultSessionDir() string {
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		return filepath.Join(os.TempDir(), sessionDirName)
+	}
+	return filepath.Join(dir, sessionDirName)
+}
+
+// GetCookiesPath 获取持久化 cookies 的文件路径
+func GetCookiesPath() string {
+	return filepath.Join(GetSessionDir(), cookiesFileName)
+}
@@ -0,0 +1,30 @@
+package configs
+
+import "time"
+
+// DefaultSessionKeepAliveInterval 登录会话保活的默认间隔
+const DefaultSessionKeepAliveInterval = time.Hour
+
+var (
+	// sessionKeepAlive 是否定期访问小红书保持登录会话
+	sessionKeepAlive = false
+	// sessionKeepAliveInterval 登录会话保活的间隔
+	sessionKeepAliveInterval = DefaultSessionKeepAliveInterval
+)
+
+// SetSessionKeepAlive 设置是否定期访问小红书保持登录会话，interval 小于等于 0 时使用默认间隔
+func SetSessionKeepAlive(enabled bool, interval time.Duration) {
+	if interval <= 0 {
+		interval = DefaultSessionKeepAliveInterval
+	}
+	sessionKeepAlive = enabled
+	sessionKeepAliveInterval = interval
+}
+
+// GetSessionKeepAlive 获取登录会话保活的间隔，未开启时返回 0
+func GetSessionKeepAlive() time.Duration {
+	if !sessionKeepAlive {
+		return 0
+	}
+	return sessionKeepAliveInterval
+}
@@ -0,0 +1,26 @@
+package configs
+
+import (
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGetSessionDir(t *testing.T) {
+	if runtime.GOOS != "linux" {
+		t.Skip("用户配置目录按 XDG_CONFIG_HOME 计算只适用于 Linux")
+	}
+	configHome := t.TempDir()
+	t.Setenv("XDG_CONFIG_HOME", configHome)
+
+	assert.Equal(t, filepath.Join(configHome, "xiaohongshu-mcp"), GetSessionDir())
+	assert.NotEqual(t, os.TempDir(), GetSessionDir(), "默认目录不能是系统临时目录，重启后会丢失登录态")
+
+	SetSessionDir("/data/session")
+	defer SetSessionDir("")
+	assert.Equal(t, "/data/session", GetSessionDir())
+	assert.Equal(t, filepath.Join("/data/session", "cookies.json"), GetCookiesPath())
+}
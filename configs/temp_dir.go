@@ -0,0 +1,46 @@
+package configs
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+)
+
+// tempDir 下载的图片、转换后的封面等中间文件的保存目录，为空时使用系统临时目录
+var tempDir = ""
+
+// SetTempDir 设置中间文件的保存目录，目录不存在时自动创建，并检查是否可写。
+// 为空时使用系统临时目录
+func SetTempDir(dir string) error {
+	if dir == "" {
+		tempDir = ""
+		return nil
+	}
+
+	abs, err := filepath.Abs(dir)
+	if err != nil {
+		return fmt.Errorf("解析临时目录 %s 失败: %w", dir, err)
+	}
+	if err := os.MkdirAll(abs, 0o755); err != nil {
+		return fmt.Errorf("创建临时目录 %s 失败: %w", abs, err)
+	}
+
+	// 写入一个文件确认目录可写，只读挂载、权限不足时启动阶段就报错
+	file, err := os.CreateTemp(abs, ".xhs-write-check-*")
+	if err != nil {
+		return fmt.Errorf("临时目录 %s 不可写: %w", abs, err)
+	}
+	file.Close()
+	os.Remove(file.Name())
+
+	tempDir = abs
+	return nil
+}
+
+// GetTempDir 获取中间文件的保存目录，默认为系统临时目录
+func GetTempDir() string {
+	if tempDir == "" {
+		return os.TempDir()
+	}
+	return tempDir
+}
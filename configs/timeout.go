@@ -0,0 +1,73 @@
+package configs
+
+import "time"
+
+const (
+	// DefaultTimeout 读操作（列表、搜索、详情等）的默认超时时间
+	DefaultTimeout = 3 * time.Minute
+	// DefaultWriteTimeout 写操作（发布、评论、编辑）的默认超时时间，包含上传图片和排队等待的时间
+	DefaultWriteTimeout = 10 * time.Minute
+	// DefaultNavTimeout 打开页面并等待加载完成的默认超时时间，主要受网络影响
+	DefaultNavTimeout = 60 * time.Second
+	// DefaultElementTimeout 页面加载完成后等待某个元素出现的默认超时时间，主要受页面渲染影响
+	DefaultElementTimeout = 20 * time.Second
+)
+
+var (
+	timeout        = DefaultTimeout
+	writeTimeout   = DefaultWriteTimeout
+	navTimeout     = DefaultNavTimeout
+	elementTimeout = DefaultElementTimeout
+)
+
+// SetTimeout 设置读操作的超时时间，小于等于 0 时使用默认值
+func SetTimeout(d time.Duration) {
+	if d <= 0 {
+		d = DefaultTimeout
+	}
+	timeout = d
+}
+
+// GetTimeout 获取读操作的超时时间
+func GetTimeout() time.Duration {
+	return timeout
+}
+
+// SetWriteTimeout 设置写操作的超时时间，小于等于 0 时使用默认值
+func SetWriteTimeout(d time.Duration) {
+	if d <= 0 {
+		d = DefaultWriteTimeout
+	}
+	writeTimeout = d
+}
+
+// GetWriteTimeout 获取写操作的超时时间
+func GetWriteTimeout() time.Duration {
+	return writeTimeout
+}
+
+// SetNavTimeout 设置页面导航的超时时间，小于等于 0 时使用默认值
+func SetNavTimeout(d time.Duration) {
+	if d <= 0 {
+		d = DefaultNavTimeout
+	}
+	navTimeout = d
+}
+
+// GetNavTimeout 获取页面导航的超时时间
+func GetNavTimeout() time.Duration {
+	return navTimeout
+}
+
+// SetElementTimeout 设置等待页面元素的超时时间，小于等于 0 时使用默认值
+func SetElementTimeout(d time.Duration) {
+	if d <= 0 {
+		d = DefaultElementTimeout
+	}
+	elementTimeout = d
+}
+
+// GetElementTimeout 获取等待页面元素的超时时间
+func GetElementTimeout() time.Duration {
+	return elementTimeout
+}
@@ -0,0 +1,67 @@
+package main
+
+import (
+	"net/http"
+	"sync/atomic"
+
+	"github.com/sirupsen/logrus"
+)
+
+// 长连接数量限制
+//
+// SSE 和 WebSocket 连接会一直保持打开，每个连接占用一个 goroutine 和通知订阅，
+// 不限制数量时大量连接（客户端异常重连或恶意连接）会耗尽服务器资源。
+// 同时保持的连接数达到 -max-connections 时，新的 SSE/WebSocket 连接直接返回 503。
+
+// connectionStats 长连接使用情况，Rejected 为启动以来因达到上限被拒绝的连接数
+type connectionStats struct {
+	Max      int   `json:"max"`
+	Active   int64 `json:"active"`
+	Rejected int64 `json:"rejected"`
+}
+
+// connectionLimiter 限制同时保持的 SSE/WebSocket 连接数
+type connectionLimiter struct {
+	max      int // 小于等于 0 表示不限制
+	active   atomic.Int64
+	rejected atomic.Int64
+}
+
+// newConnectionLimiter 创建长连接数量限制，max 小于等于 0 表示不限制
+func newConnectionLimiter(max int) *connectionLimiter {
+	return &connectionLimiter{max: max}
+}
+
+// TryAcquire 占用一个连接名额，成功时返回释放函数；达到上限时不等待，直接返回 false
+func (l *connectionLimiter) TryAcquire() (func(), bool) {
+	if n := l.active.Add(1); l.max > 0 && n > int64(l.max) {
+		l.active.Add(-1)
+		l.rejected.Add(1)
+		return nil, false
+	}
+	return func() { l.active.Add(-1) }, true
+}
+
+// Stats 当前长连接使用情况
+func (l *connectionLimiter) Stats() connectionStats {
+	return connectionStats{
+		Max:      l.max,
+		Active:   l.active.Load(),
+		Rejected: l.rejected.Load(),
+	}
+}
+
+// acquireConnection 为新的 SSE/WebSocket 连接占用名额，达到上限时返回 503 并返回 false
+func (s *AppServer) acquireConnection(w http.ResponseWriter, r *http.Request, transport string) (func(), bool) {
+	release, ok := s.connections.TryAcquire()
+	if !ok {
+		logrus.WithFields(logrus.Fields{
+			"transport": transport,
+			"remote":    r.RemoteAddr,
+			"max":       s.connections.max,
+		}).Warn("连接数已达上限，拒绝新连接")
+		http.Error(w, "Service Unavailable: too many concurrent connections, please retry later", http.StatusServiceUnavailable)
+		return nil, false
+	}
+	return release, true
+}
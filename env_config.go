@@ -0,0 +1,94 @@
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"regexp"
+	"strings"
+
+	"github.com/sirupsen/logrus"
+)
+
+// 环境变量配置
+//
+// 每个命令行参数都可以通过 XHS_ 开头的环境变量设置：参数名转为大写、- 换成 _，
+// 如 -headless 对应 XHS_HEADLESS，-max-browsers 对应 XHS_MAX_BROWSERS。
+// 同时指定时命令行参数优先。可重复的参数（-chrome-arg）在环境变量中用空白分隔多个值。
+
+// envPrefix 参数对应的环境变量前缀
+const envPrefix = "XHS_"
+
+// secretFlagPattern 名称中包含这些词的参数视为敏感信息，启动日志中只显示是否已设置
+var secretFlagPattern = regexp.MustCompile(`(?i)key|token|secret|password`)
+
+// flagEnvName 返回参数对应的环境变量名
+func flagEnvName(name string) string {
+	return envPrefix + strings.ToUpper(strings.ReplaceAll(name, "-", "_"))
+}
+
+// flagSources 记录命令行或环境变量指定了的参数，值为来源 flag 或 env
+type flagSources map[string]string
+
+// loadFlags 解析命令行参数，命令行没有指定的参数再从对应的环境变量读取。
+// 环境变量的值按参数类型校验，无效时返回包含环境变量名的错误
+func loadFlags(fs *flag.FlagSet, args []string) (flagSources, error) {
+	if err := fs.Parse(args); err != nil {
+		return nil, err
+	}
+
+	sources := make(flagSources)
+	fs.Visit(func(f *flag.Flag) {
+		sources[f.Name] = "flag"
+	})
+
+	var errs []error
+	fs.VisitAll(func(f *flag.Flag) {
+		if sources[f.Name] != "" {
+			return
+		}
+		env := flagEnvName(f.Name)
+		value, ok := os.LookupEnv(env)
+		if !ok {
+			return
+		}
+
+		values := []string{value}
+		if _, repeatable := f.Value.(*stringsFlag); repeatable {
+			values = strings.Fields(value)
+		}
+		for _, v := range values {
+			if err := fs.Set(f.Name, v); err != nil {
+				errs = append(errs, fmt.Errorf("%s=%q: %w", env, v, err))
+				return
+			}
+		}
+		sources[f.Name] = "env"
+	})
+
+	return sources, errors.Join(errs...)
+}
+
+// logEffectiveConfig 在启动日志中列出命令行或环境变量指定了的参数及其来源，敏感参数的值不输出
+func logEffectiveConfig(fs *flag.FlagSet, sources flagSources) {
+	fields := logrus.Fields{}
+	fs.VisitAll(func(f *flag.Flag) {
+		source := sources[f.Name]
+		if source == "" {
+			return
+		}
+
+		value := f.Value.String()
+		if secretFlagPattern.MatchString(f.Name) {
+			value = "******"
+		}
+		fields[f.Name] = fmt.Sprintf("%s (%s)", value, source)
+	})
+
+	if len(fields) == 0 {
+		logrus.Info("启动配置：全部使用默认值")
+		return
+	}
+	logrus.WithFields(fields).Info("启动配置")
+}
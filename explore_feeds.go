@@ -0,0 +1,87 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/xpzouying/xiaohongshu-mcp/xiaohongshu"
+)
+
+// ExploreFeedsResponse 发现页推荐笔记响应
+type ExploreFeedsResponse struct {
+	Channel   string             `json:"channel"` // 频道ID
+	Feeds     []xiaohongshu.Feed `json:"feeds"`
+	Count     int                `json:"count"`
+	Truncated bool               `json:"truncated,omitempty"` // 滚动次数达到 -max-scrolls 上限，只返回了已加载的部分
+	Warnings  []string           `json:"warnings,omitempty"`
+}
+
+// exploreChannelID 校验频道并转换为频道ID，无法识别时返回包装了 ErrInvalidArgs 的错误
+func exploreChannelID(channel string) (string, error) {
+	id, ok := xiaohongshu.ExploreChannelID(channel)
+	if !ok {
+		return "", fmt.Errorf("%w: 未知的频道 %s，可选：%s，或 homefeed 开头的频道ID", ErrInvalidArgs, channel, strings.Join(xiaohongshu.ExploreChannelNames(), "、"))
+	}
+	return id, nil
+}
+
+// ExploreFeeds 获取发现页的推荐笔记，不需要关键词。channel 为频道名称（如“美食”、“穿搭”）或频道ID，
+// 为空时使用推荐频道；limit 为 0 时使用默认数量
+func (s *XiaohongshuService) ExploreFeeds(ctx context.Context, channel string, limit int) (*ExploreFeedsResponse, error) {
+	channelID, err := exploreChannelID(channel)
+	if err != nil {
+		return nil, err
+	}
+
+	result, err := retryOnBrowserCrash("explore_feeds", func() (*ExploreFeedsResponse, error) {
+		return s.exploreFeeds(ctx, channelID, limit)
+	})
+	if err == nil {
+		s.recordFeedSources(result.Feeds, feedTokenSource{Tool: "explore_feeds", Channel: channelID})
+	}
+	return result, err
+}
+
+// exploreFeeds ExploreFeeds 的单次执行，浏览器崩溃时由 ExploreFeeds 重试
+func (s *XiaohongshuService) exploreFeeds(ctx context.Context, channelID string, limit int) (*ExploreFeedsResponse, error) {
+	ctx, done := withTimeout(ctx, "explore_feeds", false)
+	defer done()
+
+	b, err := s.newBrowser(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer b.Close()
+
+	page := newPage(b)
+	defer page.Close()
+	defer saveCookies(page)
+
+	action := xiaohongshu.NewExploreAction(page.Context(ctx))
+
+	var feeds []xiaohongshu.Feed
+	var truncated bool
+	err = retryOnCaptcha(ctx, page, func() (err error) {
+		feeds, truncated, err = action.ExploreFeeds(ctx, channelID, limit)
+		return err
+	})
+	if err = checkBlockedPage(page, err, len(feeds) == 0); err != nil {
+		return nil, screenshotOnError(page, err)
+	}
+
+	response := newExploreFeedsResponse(channelID, feeds)
+	response.Truncated = truncated
+	return response, nil
+}
+
+// newExploreFeedsResponse 组装发现页推荐笔记响应，丢弃缺少笔记ID或 xsec_token 的结果
+func newExploreFeedsResponse(channelID string, feeds []xiaohongshu.Feed) *ExploreFeedsResponse {
+	list := newFeedsListResponse("explore_feeds", feeds)
+	return &ExploreFeedsResponse{
+		Channel:  channelID,
+		Feeds:    list.Feeds,
+		Count:    list.Count,
+		Warnings: list.Warnings,
+	}
+}
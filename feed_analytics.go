@@ -0,0 +1,53 @@
+package main
+
+import (
+	"context"
+
+	"github.com/xpzouying/xiaohongshu-mcp/xiaohongshu"
+)
+
+// FeedAnalyticsResponse 单篇笔记数据响应，指标字段与 xiaohongshu.FeedAnalytics 相同
+type FeedAnalyticsResponse struct {
+	FeedID string `json:"feed_id"`
+	*xiaohongshu.FeedAnalytics
+}
+
+// FeedAnalytics 从创作者中心获取当前账号某篇笔记的观看、曝光、互动等数据，这些数据只有作者能看到。
+// 笔记不属于当前账号时返回 xiaohongshu.ErrNotNoteOwner
+func (s *XiaohongshuService) FeedAnalytics(ctx context.Context, feedID string) (*FeedAnalyticsResponse, error) {
+	return retryOnBrowserCrash("feed_analytics", func() (*FeedAnalyticsResponse, error) {
+		return s.feedAnalytics(ctx, feedID)
+	})
+}
+
+// feedAnalytics FeedAnalytics 的单次执行，浏览器崩溃时由 FeedAnalytics 重试
+func (s *XiaohongshuService) feedAnalytics(ctx context.Context, feedID string) (*FeedAnalyticsResponse, error) {
+	ctx, done := withTimeout(ctx, "feed_analytics", false)
+	defer done()
+
+	b, err := s.newBrowser(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer b.Close()
+
+	page := newPage(b)
+	defer page.Close()
+	defer saveCookies(page)
+
+	action := xiaohongshu.NewFeedAnalyticsAction(page.Context(ctx))
+
+	var analytics *xiaohongshu.FeedAnalytics
+	err = retryOnCaptcha(ctx, page, func() (err error) {
+		analytics, err = action.Analytics(ctx, feedID)
+		return err
+	})
+	if err = checkBlockedPage(page, err, false); err != nil {
+		return nil, screenshotOnError(page, err)
+	}
+
+	return &FeedAnalyticsResponse{
+		FeedID:        feedID,
+		FeedAnalytics: analytics,
+	}, nil
+}
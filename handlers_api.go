@@ -1,10 +1,18 @@
 package main
 
 import (
+	"context"
+	"errors"
+	"fmt"
+	"math/rand/v2"
 	"net/http"
+	"strconv"
+	"time"
 
 	"github.com/gin-gonic/gin"
 	"github.com/sirupsen/logrus"
+	"github.com/xpzouying/xiaohongshu-mcp/configs"
+	"github.com/xpzouying/xiaohongshu-mcp/xiaohongshu"
 )
 
 // respondError 返回错误响应
@@ -21,20 +29,127 @@ func respondError(c *gin.Context, statusCode int, code, message string, details
 	c.JSON(statusCode, response)
 }
 
+// serviceBusyRetryAfter 小红书服务繁忙时建议客户端等待的秒数
+const serviceBusyRetryAfter = 30
+
+// respondServiceError 返回服务调用失败的响应，未登录时统一返回 401 NOT_LOGGED_IN，
+// 遇到验证码返回 403 CAPTCHA_REQUIRED，游标无效返回 400 INVALID_CURSOR，xsec_token 过期且无法自动刷新返回 400 TOKEN_EXPIRED，
+// 浏览器数量达到上限返回 429 SERVER_BUSY，
+// 账号被平台限流返回 429 RATE_LIMITED，平台繁忙返回 503 SERVICE_BUSY，
+// 其他错误返回 500 和指定的错误码
+func respondServiceError(c *gin.Context, code, message string, err error) {
+	if errors.Is(err, xiaohongshu.ErrNotLoggedIn) {
+		respondError(c, http.StatusUnauthorized, "NOT_LOGGED_IN",
+			"未登录", err.Error())
+		return
+	}
+	if errors.Is(err, xiaohongshu.ErrCaptchaRequired) {
+		respondError(c, http.StatusForbidden, "CAPTCHA_REQUIRED",
+			"需要完成验证码验证", err.Error())
+		return
+	}
+	if errors.Is(err, xiaohongshu.ErrInvalidCursor) {
+		respondError(c, http.StatusBadRequest, "INVALID_CURSOR",
+			"游标无效", err.Error())
+		return
+	}
+	if errors.Is(err, xiaohongshu.ErrTokenExpired) {
+		respondError(c, http.StatusBadRequest, "TOKEN_EXPIRED",
+			"xsec_token 已过期，请重新获取列表", err.Error())
+		return
+	}
+	if errors.Is(err, xiaohongshu.ErrNoteUnavailable) {
+		respondError(c, http.StatusNotFound, "NOTE_NOT_FOUND",
+			"笔记不存在或已不可见", err.Error())
+		return
+	}
+	if errors.Is(err, ErrServerBusy) {
+		respondError(c, http.StatusTooManyRequests, "SERVER_BUSY",
+			"服务繁忙", err.Error())
+		return
+	}
+	if errors.Is(err, xiaohongshu.ErrRateLimited) {
+		setRateLimitRetryAfter(c, err)
+		respondError(c, http.StatusTooManyRequests, "RATE_LIMITED",
+			"操作过于频繁，账号被平台限流", err.Error())
+		return
+	}
+	if errors.Is(err, xiaohongshu.ErrServiceBusy) {
+		c.Header("Retry-After", strconv.Itoa(serviceBusyRetryAfter))
+		respondError(c, http.StatusServiceUnavailable, "SERVICE_BUSY",
+			"小红书服务繁忙", err.Error())
+		return
+	}
+
+	respondError(c, http.StatusInternalServerError, code, message, err.Error())
+}
+
+// setRateLimitRetryAfter 账号处于写操作冷却期时，通过 Retry-After 告知客户端剩余的秒数
+func setRateLimitRetryAfter(c *gin.Context, err error) {
+	var limitErr *RateLimitedError
+	if errors.As(err, &limitErr) && limitErr.RetryAfterSeconds() > 0 {
+		c.Header("Retry-After", strconv.Itoa(limitErr.RetryAfterSeconds()))
+	}
+}
+
+// visibleBrowserContext 解析查询参数 visible，为 true 时本次请求使用有界面的浏览器。
+// 参数格式错误或服务未开启 -allow-visible-browser 时返回 400 并返回 false
+func visibleBrowserContext(c *gin.Context) (context.Context, bool) {
+	visible := false
+	if value := c.Query("visible"); value != "" {
+		var err error
+		if visible, err = strconv.ParseBool(value); err != nil {
+			respondError(c, http.StatusBadRequest, "INVALID_REQUEST",
+				"请求参数错误", fmt.Sprintf("visible 只能是 true 或 false: %q", value))
+			return nil, false
+		}
+	}
+
+	ctx, err := withVisibleBrowser(c.Request.Context(), visible)
+	if err != nil {
+		respondError(c, http.StatusBadRequest, "INVALID_ARGS",
+			"请求参数错误", err.Error())
+		return nil, false
+	}
+	return ctx, true
+}
+
+// respondBindError 返回请求参数错误，请求体超过 -max-body-bytes 时返回 413
+func respondBindError(c *gin.Context, err error) {
+	var maxBytesErr *http.MaxBytesError
+	if errors.As(err, &maxBytesErr) {
+		respondError(c, http.StatusRequestEntityTooLarge, "REQUEST_TOO_LARGE",
+			"请求体过大", fmt.Sprintf("请求体超过 %d 字节", maxBytesErr.Limit))
+		return
+	}
+
+	respondError(c, http.StatusBadRequest, "INVALID_REQUEST",
+		"请求参数错误", err.Error())
+}
+
 // respondSuccess 返回成功响应
 func respondSuccess(c *gin.Context, data any, message string) {
 	response := SuccessResponse{
 		Success: true,
 		Data:    data,
 		Message: message,
+		Account: requestAccount(c),
 	}
 
-	logrus.Infof("%s %s %s %d", c.Request.Method, c.Request.URL.Path,
-		c.GetString("account"), http.StatusOK)
+	if sampleSuccessLog() {
+		logrus.WithContext(c.Request.Context()).Infof("%s %s %s %d", c.Request.Method, c.Request.URL.Path,
+			c.GetString("account"), http.StatusOK)
+	}
 
 	c.JSON(http.StatusOK, response)
 }
 
+// sampleSuccessLog 按 -log-sample-rate 决定是否记录成功请求的访问日志，失败请求不经过采样
+func sampleSuccessLog() bool {
+	rate := configs.GetLogSampleRate()
+	return rate >= 1 || rand.Float64() < rate
+}
+
 // checkLoginStatusHandler 检查登录状态
 func (s *AppServer) checkLoginStatusHandler(c *gin.Context) {
 	status, err := s.xiaohongshuService.CheckLoginStatus(c.Request.Context())
@@ -43,43 +158,112 @@ func (s *AppServer) checkLoginStatusHandler(c *gin.Context) {
 			"检查登录状态失败", err.Error())
 		return
 	}
+	s.updateLoginState(status)
 
-	c.Set("account", "ai-report")
+	s.setRequestAccount(c)
 	respondSuccess(c, status, "检查登录状态成功")
 }
 
+// logoutHandler 退出当前账号并删除保存的登录会话
+func (s *AppServer) logoutHandler(c *gin.Context) {
+	result, err := s.xiaohongshuService.Logout(c.Request.Context())
+	if err != nil {
+		respondServiceError(c, "LOGOUT_FAILED", "退出登录失败", err)
+		return
+	}
+	s.updateLoginState(&LoginStatusResponse{})
+
+	s.setRequestAccount(c)
+	respondSuccess(c, result, result.Message)
+}
+
+// checkAllLoginsHandler 检查所有已配置账号的登录状态
+func (s *AppServer) checkAllLoginsHandler(c *gin.Context) {
+	result, err := s.xiaohongshuService.CheckAllLogins(c.Request.Context())
+	if err != nil {
+		respondServiceError(c, "STATUS_CHECK_FAILED", "检查所有账号的登录状态失败", err)
+		return
+	}
+
+	s.setRequestAccount(c)
+	respondSuccess(c, result, "检查所有账号的登录状态成功")
+}
+
 // publishHandler 发布内容
 func (s *AppServer) publishHandler(c *gin.Context) {
 	var req PublishRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
-		respondError(c, http.StatusBadRequest, "INVALID_REQUEST",
-			"请求参数错误", err.Error())
+		respondBindError(c, err)
+		return
+	}
+
+	ctx, ok := visibleBrowserContext(c)
+	if !ok {
 		return
 	}
 
 	// 执行发布
-	result, err := s.xiaohongshuService.PublishContent(c.Request.Context(), &req)
+	result, err := s.xiaohongshuService.PublishContent(ctx, &req)
 	if err != nil {
-		respondError(c, http.StatusInternalServerError, "PUBLISH_FAILED",
-			"发布失败", err.Error())
+		code := publishErrorCode(err)
+		statusCode := http.StatusInternalServerError
+		switch code {
+		case "INVALID_ARGS":
+			statusCode = http.StatusBadRequest
+		case "NOT_LOGGED_IN":
+			statusCode = http.StatusUnauthorized
+		case "SERVER_BUSY":
+			statusCode = http.StatusTooManyRequests
+		case "RATE_LIMITED":
+			setRateLimitRetryAfter(c, err)
+			statusCode = http.StatusTooManyRequests
+		case "SERVICE_BUSY":
+			statusCode = http.StatusServiceUnavailable
+		case "CONTENT_REJECTED":
+			statusCode = http.StatusUnprocessableEntity
+		case "PUBLISH_UNCONFIRMED":
+			statusCode = http.StatusGatewayTimeout
+		}
+
+		respondError(c, statusCode, code, "发布失败", err.Error())
 		return
 	}
 
 	respondSuccess(c, result, "发布成功")
 }
 
+// publishErrorCode 将发布失败的原因映射为错误码
+func publishErrorCode(err error) string {
+	return toolErrorCode(err, "PUBLISH_FAILED")
+}
+
 // listFeedsHandler 获取Feeds列表
+//
+// 查询参数（均为可选，不传时返回全部）：
+//   - limit: 每页数量，1-100
+//   - cursor: 上一页响应中的 next_cursor，响应中没有 next_cursor 表示已是最后一页
+//   - note_type: 笔记类型过滤，normal（图文）或 video（视频）
 func (s *AppServer) listFeedsHandler(c *gin.Context) {
+	var query ListFeedsQuery
+	if err := c.ShouldBindQuery(&query); err != nil {
+		respondBindError(c, err)
+		return
+	}
+
 	// 获取 Feeds 列表
-	result, err := s.xiaohongshuService.ListFeeds(c.Request.Context())
+	result, err := s.xiaohongshuService.ListFeedsPage(c.Request.Context(), query)
 	if err != nil {
-		respondError(c, http.StatusInternalServerError, "LIST_FEEDS_FAILED",
-			"获取Feeds列表失败", err.Error())
+		respondServiceError(c, "LIST_FEEDS_FAILED", "获取Feeds列表失败", err)
 		return
 	}
 
-	c.Set("account", "ai-report")
-	respondSuccess(c, result, "获取Feeds列表成功")
+	message := "获取Feeds列表成功"
+	if result.Count == 0 {
+		message += "，" + noFeedsMessage
+	}
+
+	s.setRequestAccount(c)
+	respondSuccess(c, result, message)
 }
 
 // searchFeedsHandler 搜索Feeds
@@ -91,87 +275,502 @@ func (s *AppServer) searchFeedsHandler(c *gin.Context) {
 		return
 	}
 
-	// 搜索 Feeds
-	result, err := s.xiaohongshuService.SearchFeeds(c.Request.Context(), keyword)
+	// 搜索 Feeds，fresh=true 时跳过缓存
+	ctx := withFreshRead(c.Request.Context(), c.Query("fresh") == "true")
+	result, err := s.xiaohongshuService.SearchFeeds(ctx, keyword)
+	if err != nil {
+		respondServiceError(c, "SEARCH_FEEDS_FAILED", "搜索Feeds失败", err)
+		return
+	}
+
+	message := "搜索Feeds成功"
+	if result.Count == 0 {
+		message += "，" + noFeedsMessage
+	}
+
+	s.setRequestAccount(c)
+	respondSuccess(c, result, message)
+}
+
+// searchAndDetailHandler 搜索并获取前 N 篇笔记的详情，查询参数：keyword、limit（可选）、fresh（可选）
+func (s *AppServer) searchAndDetailHandler(c *gin.Context) {
+	var query SearchDetailQuery
+	if err := c.ShouldBindQuery(&query); err != nil {
+		respondBindError(c, err)
+		return
+	}
+
+	ctx := withFreshRead(c.Request.Context(), query.Fresh)
+	result, err := s.xiaohongshuService.SearchAndDetail(ctx, query.Keyword, query.Limit)
+	if err != nil {
+		respondServiceError(c, "SEARCH_AND_DETAIL_FAILED", "搜索并获取详情失败", err)
+		return
+	}
+
+	message := "搜索并获取详情成功"
+	if result.Count == 0 {
+		message += "，" + noFeedsMessage
+	}
+
+	s.setRequestAccount(c)
+	respondSuccess(c, result, message)
+}
+
+// searchTopicsHandler 搜索话题
+func (s *AppServer) searchTopicsHandler(c *gin.Context) {
+	keyword := c.Query("keyword")
+	if keyword == "" {
+		respondError(c, http.StatusBadRequest, "MISSING_KEYWORD",
+			"缺少关键词参数", "keyword parameter is required")
+		return
+	}
+
+	result, err := s.xiaohongshuService.SearchTopics(c.Request.Context(), keyword)
 	if err != nil {
-		respondError(c, http.StatusInternalServerError, "SEARCH_FEEDS_FAILED",
-			"搜索Feeds失败", err.Error())
+		respondError(c, http.StatusInternalServerError, "SEARCH_TOPICS_FAILED",
+			"搜索话题失败", err.Error())
 		return
 	}
 
-	c.Set("account", "ai-report")
-	respondSuccess(c, result, "搜索Feeds成功")
+	s.setRequestAccount(c)
+	respondSuccess(c, result, "搜索话题成功")
+}
+
+// trendingTopicsHandler 获取热门话题
+func (s *AppServer) trendingTopicsHandler(c *gin.Context) {
+	result, err := s.xiaohongshuService.TrendingTopics(c.Request.Context())
+	if err != nil {
+		respondError(c, http.StatusInternalServerError, "TRENDING_TOPICS_FAILED",
+			"获取热门话题失败", err.Error())
+		return
+	}
+
+	s.setRequestAccount(c)
+	respondSuccess(c, result, "获取热门话题成功")
+}
+
+// exploreFeedsHandler 获取发现页的推荐笔记，查询参数 channel、limit 可选
+func (s *AppServer) exploreFeedsHandler(c *gin.Context) {
+	var query ExploreFeedsQuery
+	if err := c.ShouldBindQuery(&query); err != nil {
+		respondBindError(c, err)
+		return
+	}
+	if _, err := exploreChannelID(query.Channel); err != nil {
+		respondError(c, http.StatusBadRequest, "INVALID_REQUEST",
+			"请求参数错误", err.Error())
+		return
+	}
+
+	result, err := s.xiaohongshuService.ExploreFeeds(c.Request.Context(), query.Channel, query.Limit)
+	if err != nil {
+		respondServiceError(c, "EXPLORE_FEEDS_FAILED", "获取发现页笔记失败", err)
+		return
+	}
+
+	message := "获取发现页笔记成功"
+	if result.Count == 0 {
+		message += "，" + noFeedsMessage
+	}
+	s.setRequestAccount(c)
+	respondSuccess(c, result, message)
+}
+
+// listNotificationsHandler 获取通知，查询参数 category 可选：likes、comments、mentions、follows
+func (s *AppServer) listNotificationsHandler(c *gin.Context) {
+	category := c.Query("category")
+	if !xiaohongshu.ValidNotificationCategory(category) {
+		respondError(c, http.StatusBadRequest, "INVALID_REQUEST",
+			"请求参数错误", "category must be one of likes, comments, mentions, follows")
+		return
+	}
+
+	result, err := s.xiaohongshuService.ListNotifications(c.Request.Context(), category)
+	if err != nil {
+		respondServiceError(c, "LIST_NOTIFICATIONS_FAILED", "获取通知失败", err)
+		return
+	}
+
+	s.setRequestAccount(c)
+	respondSuccess(c, result, "获取通知成功")
 }
 
 // getFeedDetailHandler 获取Feed详情
 func (s *AppServer) getFeedDetailHandler(c *gin.Context) {
 	var req FeedDetailRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
-		respondError(c, http.StatusBadRequest, "INVALID_REQUEST",
-			"请求参数错误", err.Error())
+		respondBindError(c, err)
 		return
 	}
 
 	// 获取 Feed 详情
-	result, err := s.xiaohongshuService.GetFeedDetail(c.Request.Context(), req.FeedID, req.XsecToken)
+	ctx := withFreshRead(c.Request.Context(), req.Fresh)
+	result, err := s.xiaohongshuService.GetFeedDetail(ctx, req.FeedID, req.XsecToken)
 	if err != nil {
-		respondError(c, http.StatusInternalServerError, "GET_FEED_DETAIL_FAILED",
-			"获取Feed详情失败", err.Error())
+		respondServiceError(c, "GET_FEED_DETAIL_FAILED", "获取Feed详情失败", err)
 		return
 	}
 
-	c.Set("account", "ai-report")
+	s.setRequestAccount(c)
 	respondSuccess(c, result, "获取Feed详情成功")
 }
 
+// listCommentsHandler 分页获取笔记评论
+func (s *AppServer) listCommentsHandler(c *gin.Context) {
+	var req ListCommentsRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondBindError(c, err)
+		return
+	}
+
+	// 获取评论
+	result, err := s.xiaohongshuService.ListComments(c.Request.Context(), req.FeedID, req.XsecToken, req.Limit, req.Cursor)
+	if err != nil {
+		respondServiceError(c, "LIST_COMMENTS_FAILED", "获取评论失败", err)
+		return
+	}
+
+	s.setRequestAccount(c)
+	respondSuccess(c, result, "获取评论成功")
+}
+
+// commentTreeHandler 分页获取笔记评论树
+func (s *AppServer) commentTreeHandler(c *gin.Context) {
+	var req CommentTreeRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondBindError(c, err)
+		return
+	}
+
+	result, err := s.xiaohongshuService.GetCommentTree(c.Request.Context(), req.FeedID, req.XsecToken, req.Limit, req.RepliesLimit, req.Cursor)
+	if err != nil {
+		respondServiceError(c, "GET_COMMENT_TREE_FAILED", "获取评论树失败", err)
+		return
+	}
+
+	s.setRequestAccount(c)
+	respondSuccess(c, result, "获取评论树成功")
+}
+
+// editFeedHandler 编辑笔记
+func (s *AppServer) editFeedHandler(c *gin.Context) {
+	var req EditFeedRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondBindError(c, err)
+		return
+	}
+
+	// 编辑笔记
+	result, err := s.xiaohongshuService.EditFeed(c.Request.Context(), req.FeedID, req.XsecToken, req)
+	if err != nil {
+		if errors.Is(err, ErrInvalidArgs) {
+			respondError(c, http.StatusBadRequest, "INVALID_ARGS",
+				"请求参数错误", err.Error())
+			return
+		}
+		respondError(c, http.StatusInternalServerError, "EDIT_FEED_FAILED",
+			"编辑笔记失败", err.Error())
+		return
+	}
+
+	s.setRequestAccount(c)
+	respondSuccess(c, result, "编辑笔记成功")
+}
+
+// feedVisibilityHandler 修改笔记可见范围，visibility 为空时设为仅自己可见
+func (s *AppServer) feedVisibilityHandler(c *gin.Context) {
+	var req FeedVisibilityRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondBindError(c, err)
+		return
+	}
+
+	result, err := s.xiaohongshuService.SetFeedVisibility(c.Request.Context(), req.FeedID, req.XsecToken, req.Visibility)
+	if err != nil {
+		switch {
+		case errors.Is(err, ErrInvalidArgs):
+			respondError(c, http.StatusBadRequest, "INVALID_ARGS",
+				"请求参数错误", err.Error())
+		case errors.Is(err, xiaohongshu.ErrNotNoteOwner):
+			respondError(c, http.StatusForbidden, "NOT_OWNER",
+				"笔记不属于当前登录账号", err.Error())
+		case errors.Is(err, xiaohongshu.ErrVisibilityUnsupported):
+			respondError(c, http.StatusUnprocessableEntity, "VISIBILITY_UNSUPPORTED",
+				"该笔记不支持修改可见范围", err.Error())
+		default:
+			respondServiceError(c, "SET_VISIBILITY_FAILED", "修改笔记可见范围失败", err)
+		}
+		return
+	}
+
+	s.setRequestAccount(c)
+	respondSuccess(c, result, result.Message)
+}
+
 // userProfileHandler 用户主页
 func (s *AppServer) userProfileHandler(c *gin.Context) {
 	var req UserProfileRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
+		respondBindError(c, err)
+		return
+	}
+	if err := req.Validate(); err != nil {
 		respondError(c, http.StatusBadRequest, "INVALID_REQUEST",
 			"请求参数错误", err.Error())
 		return
 	}
 
 	// 获取用户信息
-	result, err := s.xiaohongshuService.UserProfile(c.Request.Context(), req.UserID, req.XsecToken)
+	var (
+		result *UserProfileResponse
+		err    error
+	)
+	if req.ProfileURL != "" {
+		result, err = s.xiaohongshuService.UserProfileByURL(c.Request.Context(), req.ProfileURL)
+	} else {
+		result, err = s.xiaohongshuService.UserProfile(c.Request.Context(), req.UserID, req.XsecToken)
+	}
 	if err != nil {
-		respondError(c, http.StatusInternalServerError, "GET_USER_PROFILE_FAILED",
-			"获取用户主页失败", err.Error())
+		respondServiceError(c, "GET_USER_PROFILE_FAILED", "获取用户主页失败", err)
 		return
 	}
 
-	c.Set("account", "ai-report")
+	s.setRequestAccount(c)
 	respondSuccess(c, map[string]any{"data": result}, "result.Message")
 }
 
+// userCollectionsHandler 用户主页的收藏专辑列表
+func (s *AppServer) userCollectionsHandler(c *gin.Context) {
+	var req UserCollectionsRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondBindError(c, err)
+		return
+	}
+
+	result, err := s.xiaohongshuService.UserCollections(c.Request.Context(), req.UserID, req.XsecToken)
+	if err != nil {
+		respondServiceError(c, "GET_USER_COLLECTIONS_FAILED", "获取用户收藏专辑失败", err)
+		return
+	}
+
+	s.setRequestAccount(c)
+	respondSuccess(c, result, "获取用户收藏专辑成功")
+}
+
+// myProfileHandler 当前登录账号的主页
+func (s *AppServer) myProfileHandler(c *gin.Context) {
+	result, err := s.xiaohongshuService.MyProfile(c.Request.Context())
+	if err != nil {
+		respondServiceError(c, "GET_MY_PROFILE_FAILED", "获取当前账号主页失败", err)
+		return
+	}
+
+	s.setRequestAccount(c)
+	respondSuccess(c, result, "获取当前账号主页成功")
+}
+
+// feedAnalyticsHandler 当前账号某篇笔记的创作者中心数据，查询参数：feed_id
+func (s *AppServer) feedAnalyticsHandler(c *gin.Context) {
+	var query FeedAnalyticsQuery
+	if err := c.ShouldBindQuery(&query); err != nil {
+		respondBindError(c, err)
+		return
+	}
+
+	result, err := s.xiaohongshuService.FeedAnalytics(c.Request.Context(), query.FeedID)
+	if errors.Is(err, xiaohongshu.ErrNotNoteOwner) {
+		respondError(c, http.StatusForbidden, "NOT_OWNER",
+			"笔记不属于当前登录账号", err.Error())
+		return
+	}
+	if err != nil {
+		respondServiceError(c, "GET_FEED_ANALYTICS_FAILED", "获取笔记数据失败", err)
+		return
+	}
+
+	s.setRequestAccount(c)
+	respondSuccess(c, result, "获取笔记数据成功")
+}
+
+// feedLinkHandler 获取笔记链接，查询参数：feed_id、xsec_token、short（可选）
+func (s *AppServer) feedLinkHandler(c *gin.Context) {
+	var query FeedLinkQuery
+	if err := c.ShouldBindQuery(&query); err != nil {
+		respondBindError(c, err)
+		return
+	}
+
+	result, err := s.xiaohongshuService.GetFeedLink(c.Request.Context(), query.FeedID, query.XsecToken, query.Short)
+	if err != nil {
+		respondServiceError(c, "GET_FEED_LINK_FAILED", "获取笔记链接失败", err)
+		return
+	}
+
+	s.setRequestAccount(c)
+	respondSuccess(c, result, "获取笔记链接成功")
+}
+
+// feedTagsHandler 获取笔记的话题标签，查询参数：feed_id、xsec_token
+func (s *AppServer) feedTagsHandler(c *gin.Context) {
+	var query FeedTagsQuery
+	if err := c.ShouldBindQuery(&query); err != nil {
+		respondBindError(c, err)
+		return
+	}
+
+	result, err := s.xiaohongshuService.GetFeedTags(c.Request.Context(), query.FeedID, query.XsecToken)
+	if err != nil {
+		respondServiceError(c, "GET_FEED_TAGS_FAILED", "获取笔记话题失败", err)
+		return
+	}
+
+	s.setRequestAccount(c)
+	respondSuccess(c, result, "获取笔记话题成功")
+}
+
+// relatedFeedsHandler 获取笔记详情页的相关推荐，查询参数：feed_id、xsec_token
+func (s *AppServer) relatedFeedsHandler(c *gin.Context) {
+	var query RelatedFeedsQuery
+	if err := c.ShouldBindQuery(&query); err != nil {
+		respondBindError(c, err)
+		return
+	}
+
+	result, err := s.xiaohongshuService.RelatedFeeds(c.Request.Context(), query.FeedID, query.XsecToken)
+	if err != nil {
+		respondServiceError(c, "GET_RELATED_FEEDS_FAILED", "获取相关推荐失败", err)
+		return
+	}
+
+	s.setRequestAccount(c)
+	respondSuccess(c, result, "获取相关推荐成功")
+}
+
+// screenshotFeedHandler 截取笔记详情页，直接返回 PNG 图片，查询参数：feed_id、xsec_token、width、scope
+func (s *AppServer) screenshotFeedHandler(c *gin.Context) {
+	var query ScreenshotFeedQuery
+	if err := c.ShouldBindQuery(&query); err != nil {
+		respondBindError(c, err)
+		return
+	}
+
+	result, err := s.xiaohongshuService.ScreenshotFeed(c.Request.Context(), query.FeedID, query.XsecToken, query.Width, query.Scope)
+	if err != nil {
+		respondServiceError(c, "SCREENSHOT_FEED_FAILED", "笔记截图失败", err)
+		return
+	}
+
+	if result.XsecToken != "" {
+		c.Header("X-Xsec-Token", result.XsecToken)
+	}
+	if len(result.Warnings) > 0 {
+		c.Header("X-Screenshot-Truncated", "true")
+	}
+	s.setRequestAccount(c)
+	c.Data(http.StatusOK, "image/png", result.Image)
+}
+
+// noteOwnershipHandler 判断笔记是否属于当前登录账号，查询参数：feed_id、xsec_token
+func (s *AppServer) noteOwnershipHandler(c *gin.Context) {
+	var query NoteOwnershipQuery
+	if err := c.ShouldBindQuery(&query); err != nil {
+		respondBindError(c, err)
+		return
+	}
+
+	owned, err := s.xiaohongshuService.IsOwnNote(c.Request.Context(), query.FeedID, query.XsecToken)
+	if err != nil {
+		respondServiceError(c, "CHECK_OWNERSHIP_FAILED", "检查笔记归属失败", err)
+		return
+	}
+
+	s.setRequestAccount(c)
+	respondSuccess(c, NoteOwnershipResponse{FeedID: query.FeedID, Owned: owned}, "检查笔记归属成功")
+}
+
 // postCommentHandler 发表评论到Feed
 func (s *AppServer) postCommentHandler(c *gin.Context) {
 	var req PostCommentRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
-		respondError(c, http.StatusBadRequest, "INVALID_REQUEST",
-			"请求参数错误", err.Error())
+		respondBindError(c, err)
+		return
+	}
+
+	ctx, ok := visibleBrowserContext(c)
+	if !ok {
 		return
 	}
 
 	// 发表评论
-	result, err := s.xiaohongshuService.PostCommentToFeed(c.Request.Context(), req.FeedID, req.XsecToken, req.Content)
+	result, err := s.xiaohongshuService.PostCommentToFeed(ctx, req.FeedID, req.XsecToken, req.Content, req.Image)
 	if err != nil {
+		if errors.Is(err, ErrInvalidArgs) {
+			respondError(c, http.StatusBadRequest, "INVALID_ARGS",
+				"请求参数错误", err.Error())
+			return
+		}
 		respondError(c, http.StatusInternalServerError, "POST_COMMENT_FAILED",
 			"发表评论失败", err.Error())
 		return
 	}
 
-	c.Set("account", "ai-report")
+	s.setRequestAccount(c)
 	respondSuccess(c, result, result.Message)
 }
 
-// healthHandler 健康检查
-func healthHandler(c *gin.Context) {
+// postCommentsHandler 批量发表评论
+func (s *AppServer) postCommentsHandler(c *gin.Context) {
+	var req PostCommentsRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondBindError(c, err)
+		return
+	}
+
+	delay := defaultCommentDelay
+	if req.DelaySeconds != nil {
+		delay = time.Duration(*req.DelaySeconds) * time.Second
+	}
+
+	ctx, ok := visibleBrowserContext(c)
+	if !ok {
+		return
+	}
+
+	// 批量发表评论
+	result, err := s.xiaohongshuService.PostCommentsBatch(ctx, req.Comments, delay)
+	if err != nil {
+		if errors.Is(err, ErrInvalidArgs) {
+			respondError(c, http.StatusBadRequest, "INVALID_ARGS",
+				"请求参数错误", err.Error())
+			return
+		}
+		// 中途被取消或超时，返回已发表和未尝试的评论，调用方只需重试未尝试的部分
+		if result != nil {
+			respondError(c, http.StatusInternalServerError, "POST_COMMENTS_FAILED",
+				"批量发表评论中断: "+err.Error(), result)
+			return
+		}
+		respondError(c, http.StatusInternalServerError, "POST_COMMENTS_FAILED",
+			"批量发表评论失败", err.Error())
+		return
+	}
+
+	s.setRequestAccount(c)
+	respondSuccess(c, result, fmt.Sprintf("批量发表评论完成，成功 %d 条，失败 %d 条", result.Succeeded, result.Failed))
+}
+
+// healthHandler 健康检查，write_queue 为每个账号正在执行和排队等待的写操作数量，
+// cache 为笔记详情和搜索结果缓存的命中统计，browsers 为正在运行、空闲和排队等待的浏览器数量，
+// connections 为当前的 SSE/WebSocket 连接数及因达到上限被拒绝的连接数
+func (s *AppServer) healthHandler(c *gin.Context) {
 	respondSuccess(c, map[string]any{
-		"status":    "healthy",
-		"service":   "xiaohongshu-mcp",
-		"account":   "ai-report",
-		"timestamp": "now",
+		"status":      "healthy",
+		"service":     "xiaohongshu-mcp",
+		"account":     s.account.Get(),
+		"timestamp":   "now",
+		"write_queue": s.xiaohongshuService.WriteQueueDepth(),
+		"cache":       s.xiaohongshuService.CacheStats(),
+		"browsers":    s.xiaohongshuService.BrowserStats(),
+		"connections": s.connections.Stats(),
 	}, "服务正常")
 }
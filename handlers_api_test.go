@@ -0,0 +1,178 @@
+package main
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/xpzouying/xiaohongshu-mcp/xiaohongshu"
+)
+
+// newTestRouter 使用 mock 服务创建完整的路由，与 -mock 模式相同
+func newTestRouter(t *testing.T) (*gin.Engine, *mockService) {
+	t.Helper()
+
+	service, err := newMockService()
+	require.NoError(t, err)
+	return setupRoutes(NewAppServer(service)), service
+}
+
+// serve 发送请求并返回响应，body 为空时不带请求体
+func serve(t *testing.T, router http.Handler, method, target, body string) *httptest.ResponseRecorder {
+	t.Helper()
+
+	var reader io.Reader
+	if body != "" {
+		reader = strings.NewReader(body)
+	}
+	req := httptest.NewRequest(method, target, reader)
+	if body != "" {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	return w
+}
+
+// decodeSuccess 解析成功响应中的 data
+func decodeSuccess(t *testing.T, w *httptest.ResponseRecorder, data any) {
+	t.Helper()
+
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	var response struct {
+		Success bool            `json:"success"`
+		Data    json.RawMessage `json:"data"`
+	}
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+	require.True(t, response.Success)
+	require.NoError(t, json.Unmarshal(response.Data, data))
+}
+
+// decodeError 解析错误响应
+func decodeError(t *testing.T, w *httptest.ResponseRecorder, status int) ErrorResponse {
+	t.Helper()
+
+	require.Equal(t, status, w.Code, w.Body.String())
+	var response ErrorResponse
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+	return response
+}
+
+func TestListFeedsHandler(t *testing.T) {
+	router, service := newTestRouter(t)
+
+	var result FeedsListResponse
+	decodeSuccess(t, serve(t, router, http.MethodGet, "/api/v1/feeds/list?limit=1", ""), &result)
+	assert.Equal(t, 1, result.Count)
+	require.Len(t, result.Feeds, 1)
+	assert.Equal(t, service.feeds[0].ID, result.Feeds[0].ID)
+	if len(service.feeds) > 1 {
+		assert.NotEmpty(t, result.NextCursor)
+	}
+
+	response := decodeError(t, serve(t, router, http.MethodGet, "/api/v1/feeds/list?cursor=bad", ""), http.StatusBadRequest)
+	assert.Equal(t, "INVALID_CURSOR", response.Code)
+}
+
+func TestListFeedsHandlerDroppedWarning(t *testing.T) {
+	router, service := newTestRouter(t)
+	service.feeds = append(service.feeds, xiaohongshu.Feed{ID: "ad", ModelType: "ads"})
+
+	// 分页时按本页重新生成警告，丢弃条目的说明需要保留
+	for _, target := range []string{"/api/v1/feeds/list", "/api/v1/feeds/list?limit=1"} {
+		t.Run(target, func(t *testing.T) {
+			var result FeedsListResponse
+			decodeSuccess(t, serve(t, router, http.MethodGet, target, ""), &result)
+			assert.Contains(t, result.Warnings, "已丢弃 1 条缺少笔记ID或 xsec_token 的结果")
+		})
+	}
+}
+
+func TestListFeedsHandlerEmpty(t *testing.T) {
+	router, service := newTestRouter(t)
+	service.feeds = nil
+
+	for _, target := range []string{"/api/v1/feeds/list", "/api/v1/feeds/list?limit=5"} {
+		t.Run(target, func(t *testing.T) {
+			w := serve(t, router, http.MethodGet, target, "")
+
+			var result struct {
+				Feeds json.RawMessage `json:"feeds"`
+				Count *int            `json:"count"`
+			}
+			decodeSuccess(t, w, &result)
+			assert.JSONEq(t, `[]`, string(result.Feeds), "没有结果时 feeds 应为空数组而不是 null")
+			require.NotNil(t, result.Count)
+			assert.Zero(t, *result.Count)
+		})
+	}
+}
+
+func TestGetFeedDetailHandler(t *testing.T) {
+	router, _ := newTestRouter(t)
+
+	var result FeedDetailResponse
+	decodeSuccess(t, serve(t, router, http.MethodPost, "/api/v1/feeds/detail",
+		`{"feed_id": "6650a1b2000000001e01a001", "xsec_token": "token"}`), &result)
+	assert.Equal(t, "6650a1b2000000001e01a001", result.FeedID)
+	assert.NotNil(t, result.Data)
+
+	response := decodeError(t, serve(t, router, http.MethodPost, "/api/v1/feeds/detail", `{"feed_id": "1"}`), http.StatusBadRequest)
+	assert.Equal(t, "INVALID_REQUEST", response.Code)
+}
+
+func TestPublishHandlerInvalidArgs(t *testing.T) {
+	router, _ := newTestRouter(t)
+
+	response := decodeError(t, serve(t, router, http.MethodPost, "/api/v1/publish",
+		`{"title": "标题", "content": "正文", "images": ["https://example.com/1.jpg"], "visibility": "unknown"}`), http.StatusBadRequest)
+	assert.Equal(t, "INVALID_ARGS", response.Code)
+}
+
+func TestEditFeedHandlerInvalidArgs(t *testing.T) {
+	router, _ := newTestRouter(t)
+
+	response := decodeError(t, serve(t, router, http.MethodPost, "/api/v1/feeds/edit",
+		`{"feed_id": "6650a1b2000000001e01a001", "xsec_token": "token"}`), http.StatusBadRequest)
+	assert.Equal(t, "INVALID_ARGS", response.Code)
+}
+
+func TestPublishHandlerVisibility(t *testing.T) {
+	router, _ := newTestRouter(t)
+
+	tests := []struct {
+		visibility string
+		want       string
+	}{
+		{"", "public"},
+		{"friends", "friends"},
+		{"Private", "private"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.want, func(t *testing.T) {
+			var result PublishResponse
+			decodeSuccess(t, serve(t, router, http.MethodPost, "/api/v1/publish",
+				`{"title": "标题", "content": "正文", "images": ["https://example.com/1.jpg"], "visibility": "`+tt.visibility+`"}`), &result)
+			assert.Equal(t, tt.want, result.Visibility)
+		})
+	}
+}
+
+func TestPostCommentsHandler(t *testing.T) {
+	router, _ := newTestRouter(t)
+
+	var result PostCommentsResponse
+	decodeSuccess(t, serve(t, router, http.MethodPost, "/api/v1/feeds/comment/batch",
+		`{"comments": [{"feed_id": "1", "xsec_token": "t", "content": "a"}, {"feed_id": "2", "xsec_token": "t", "content": "b"}]}`), &result)
+	assert.Equal(t, 2, result.Succeeded)
+
+	response := decodeError(t, serve(t, router, http.MethodPost, "/api/v1/feeds/comment/batch",
+		`{"comments": [{"feed_id": "1", "xsec_token": "t", "content": "a"}], "delay_seconds": -1}`), http.StatusBadRequest)
+	assert.Equal(t, "INVALID_ARGS", response.Code)
+}
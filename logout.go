@@ -0,0 +1,68 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/xpzouying/xiaohongshu-mcp/configs"
+	"github.com/xpzouying/xiaohongshu-mcp/xiaohongshu"
+)
+
+// LogoutResponse 退出登录响应
+type LogoutResponse struct {
+	Username         string `json:"username,omitempty"` // 配置的账号名称
+	AlreadyLoggedOut bool   `json:"already_logged_out"` // 退出前就没有登录
+	SessionCleared   bool   `json:"session_cleared"`    // 是否已删除保存的登录会话
+	Message          string `json:"message"`
+}
+
+// Logout 在浏览器中退出当前账号，确认页面回到未登录状态后删除保存的 cookies，
+// 之后需要重新扫码登录或导入 cookies。
+// 与发布、评论等写操作共用写锁，避免退出时打断正在进行的操作；限流冷却期内也可以退出
+func (s *XiaohongshuService) Logout(ctx context.Context) (_ *LogoutResponse, err error) {
+	start := time.Now()
+	defer func() {
+		s.audit.Record(configs.Username, "logout", nil, start, err)
+	}()
+
+	ctx, done := withTimeout(ctx, "logout", true)
+	defer done()
+
+	release, err := s.writeGuard.Acquire(ctx, configs.Username)
+	if err != nil {
+		return nil, fmt.Errorf("等待其他写操作完成时取消: %w", err)
+	}
+	defer release()
+
+	b, err := s.newBrowser(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer b.Close()
+
+	// 不在结束时保存 cookies，退出后会话文件直接删除
+	page := newPage(b)
+	defer page.Close()
+
+	alreadyLoggedOut, err := xiaohongshu.NewLogoutAction(page.Context(ctx)).Logout(ctx)
+	if err != nil {
+		return nil, screenshotOnError(page, err)
+	}
+
+	if err := clearCookies(); err != nil {
+		return nil, err
+	}
+
+	response := &LogoutResponse{
+		Username:         configs.Username,
+		AlreadyLoggedOut: alreadyLoggedOut,
+		SessionCleared:   true,
+		Message:          "已退出登录，登录会话已删除",
+	}
+	if alreadyLoggedOut {
+		response.Message = "当前没有登录，已删除保存的登录会话"
+	}
+
+	return response, nil
+}
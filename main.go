@@ -1,26 +1,257 @@
 package main
 
 import (
+	"context"
 	"flag"
+	"os"
+	"os/signal"
+	"strconv"
+	"strings"
+	"time"
 
 	"github.com/sirupsen/logrus"
 	"github.com/xpzouying/xiaohongshu-mcp/configs"
+	"github.com/xpzouying/xiaohongshu-mcp/pkg/downloader"
+	"github.com/xpzouying/xiaohongshu-mcp/xiaohongshu"
 )
 
 func main() {
+	logrus.AddHook(xiaohongshu.NewOpIDHook())
+
 	var (
-		headless bool
-		binPath  string // 浏览器二进制文件路径
+		headless     bool
+		headlessMode string // 无头模式：true、false、new
+		allowVisible bool   // 允许单次请求使用有界面的浏览器
+		binPath      string // 浏览器二进制文件路径
+		userAgent    string // 浏览器 UA
+		lang         string // 浏览器语言
+		viewport     string // 浏览器视口大小
+		sessionDir   string // 登录会话保存目录
+		tempDir      string // 中间文件保存目录
+
+		screenshotOnError bool   // 操作失败时保存页面截图
+		screenshotDir     string // 错误截图保存目录
+		maxScreenshots    int    // 最多保留的错误截图数量
+
+		debug    bool // 调试模式
+		mock     bool // mock 模式，返回固定数据
+		selftest bool // 自检模式，检查浏览器和登录会话后退出
+
+		timeout        time.Duration // 读操作超时时间
+		writeTimeout   time.Duration // 写操作超时时间
+		navTimeout     time.Duration // 页面导航超时时间
+		elementTimeout time.Duration // 等待页面元素超时时间
+		captchaWait    time.Duration // 等待人工完成验证码的最长时间
+
+		humanize         bool          // 模拟人工操作
+		humanizeMinDelay time.Duration // 模拟人工操作的最短停顿
+		humanizeMaxDelay time.Duration // 模拟人工操作的最长停顿
+
+		loginCheckInterval time.Duration // 后台检查登录状态的间隔
+
+		sessionKeepAlive         bool          // 定期访问小红书保持登录会话
+		sessionKeepAliveInterval time.Duration // 登录会话保活的间隔
+
+		cacheTTL        time.Duration // 笔记详情和搜索结果的缓存时间
+		cacheMaxEntries int           // 每类缓存最多保留的条目数
+
+		chromeArgs stringsFlag // 额外的 Chromium 启动参数
+
+		auditLog        string // 审计日志文件路径
+		auditLogMaxSize int64  // 审计日志文件大小上限
+
+		logSampleRate float64 // 成功请求记录访问日志的比例
+
+		corsOrigins string // 允许跨域访问的来源
+		safeMode    bool   // 安全模式，禁用写操作
+
+		baseURL        string // 小红书网页版地址
+		creatorBaseURL string // 创作者中心地址
+
+		maxImages       int  // 单篇笔记最多可上传的图片数
+		maxContentWidth int  // 正文的最大宽度
+		publishVerify   bool // 发布后到创作者中心确认笔记是否发出
+
+		publishConfirmTimeout time.Duration // 点击发布后等待发布成功提示的最长时间
+
+		maxScrolls int // 单次提取最多滚动的次数
+
+		imageDownloadWorkers int           // 同时下载的 URL 图片数量
+		imageDownloadTimeout time.Duration // 单张图片的下载超时时间
+		imageHostAllow       string        // 允许下载图片的主机名或 CIDR
+		imageHostDeny        string        // 拒绝下载图片的主机名或 CIDR
+		imageAllowPrivate    bool          // 允许从内网地址下载图片
+
+		maxBrowsers int           // 最多同时运行的浏览器数量
+		browserWait time.Duration // 排队等待浏览器的最长时间
+
+		maxConnections int // 最多同时保持的 SSE/WebSocket 连接数
+
+		rateLimitCooldown    time.Duration // 被平台限流后暂停写操作的初始时间
+		rateLimitMaxCooldown time.Duration // 连续被限流时暂停时间的上限
+
+		maxBodyBytes     int64  // 请求体的字节数上限
+		maxOutputBytes   int    // MCP 工具结果文本的字节数上限
+		toolOutputLimits string // 按工具配置的结果字节数上限
 	)
 	flag.BoolVar(&headless, "headless", true, "是否无头模式")
+	flag.StringVar(&headlessMode, "headless-mode", "", "无头模式：true 为旧版无头模式，false 为有界面，new 为 Chromium 新版无头模式；指定时覆盖 -headless")
+	flag.BoolVar(&allowVisible, "allow-visible-browser", false, "允许发布、评论请求通过 visible 参数使用有界面的浏览器，便于排查单个请求，需要运行环境有图形界面")
 	flag.StringVar(&binPath, "bin", "", "浏览器二进制文件路径")
-	flag.Parse()
+	flag.StringVar(&userAgent, "user-agent", configs.DefaultUserAgent, "浏览器 UA")
+	flag.StringVar(&lang, "lang", configs.DefaultLang, "浏览器语言，设置 Accept-Language 和 navigator.language，如 zh-CN")
+	flag.StringVar(&viewport, "viewport", configs.DefaultViewport, "浏览器视口大小，格式为 宽x高")
+	flag.StringVar(&sessionDir, "session-dir", "", "登录会话（cookies）保存目录，默认为用户配置目录下的 xiaohongshu-mcp（如 ~/.config/xiaohongshu-mcp）")
+	flag.StringVar(&tempDir, "temp-dir", "", "下载的图片、转换后的封面等中间文件的保存目录，不存在时自动创建，默认为系统临时目录")
+	flag.BoolVar(&screenshotOnError, "screenshot-on-error", false, "操作失败时保存页面截图，并在错误信息中返回截图路径")
+	flag.StringVar(&screenshotDir, "screenshot-dir", "", "错误截图保存目录，默认为系统临时目录下的 xiaohongshu-mcp-screenshots")
+	flag.IntVar(&maxScreenshots, "max-screenshots", configs.DefaultMaxScreenshots, "最多保留的错误截图数量，超出时删除最早的截图")
+	flag.DurationVar(&timeout, "timeout", configs.DefaultTimeout, "读操作（列表、搜索、详情等）的超时时间")
+	flag.DurationVar(&writeTimeout, "write-timeout", configs.DefaultWriteTimeout, "写操作（发布、评论、编辑）的超时时间")
+	flag.DurationVar(&navTimeout, "nav-timeout", configs.DefaultNavTimeout, "打开页面并等待加载完成的超时时间，网络较慢时可调大")
+	flag.DurationVar(&elementTimeout, "element-timeout", configs.DefaultElementTimeout, "页面加载后等待某个元素出现的超时时间")
+	flag.DurationVar(&captchaWait, "captcha-wait", configs.DefaultCaptchaWait, "非无头模式下遇到验证码时，等待人工完成验证的最长时间，0 表示不等待")
+	flag.BoolVar(&humanize, "humanize", false, "模拟人工操作：点击前随机停顿，文本逐字输入，降低被识别为自动化的概率，会让写操作变慢")
+	flag.DurationVar(&humanizeMinDelay, "humanize-min-delay", configs.DefaultHumanizeMinDelay, "-humanize 开启时两次操作之间的最短停顿")
+	flag.DurationVar(&humanizeMaxDelay, "humanize-max-delay", configs.DefaultHumanizeMaxDelay, "-humanize 开启时两次操作之间的最长停顿")
+	flag.DurationVar(&loginCheckInterval, "login-check-interval", configs.DefaultLoginCheckInterval, "有 SSE/WebSocket 连接时后台检查登录状态的间隔，登录过期时推送 notifications/login_expired，0 表示不检查")
+	flag.BoolVar(&sessionKeepAlive, "session-keepalive", false, "定期访问小红书并重新保存 cookies，避免长时间空闲后登录过期；检测到过期时推送 notifications/login_expired")
+	flag.DurationVar(&sessionKeepAliveInterval, "session-keepalive-interval", configs.DefaultSessionKeepAliveInterval, "-session-keepalive 开启时保活的间隔")
+	flag.DurationVar(&cacheTTL, "cache-ttl", 0, "笔记详情和搜索结果的缓存时间，如 5m，0 表示不缓存")
+	flag.IntVar(&cacheMaxEntries, "cache-max-entries", configs.DefaultCacheMaxEntries, "每类缓存最多保留的条目数，超出时淘汰最久未使用的条目")
+	flag.IntVar(&maxBrowsers, "max-browsers", configs.DefaultMaxBrowsers, "最多同时运行的浏览器数量，达到上限时请求排队等待，0 表示不限制")
+	flag.DurationVar(&browserWait, "browser-wait", configs.DefaultBrowserWait, "浏览器数量达到上限时请求最多排队等待的时间，超时返回 429 SERVER_BUSY，0 表示不等待")
+	flag.IntVar(&maxConnections, "max-connections", configs.DefaultMaxConnections, "最多同时保持的 SSE/WebSocket 连接数，达到上限时新连接返回 503，0 表示不限制")
+	flag.DurationVar(&rateLimitCooldown, "rate-limit-cooldown", configs.DefaultRateLimitCooldown, "小红书提示操作过于频繁后暂停写操作的时间，期间写操作返回 429 RATE_LIMITED，连续被限流时按指数增长，0 表示不暂停")
+	flag.DurationVar(&rateLimitMaxCooldown, "rate-limit-max-cooldown", configs.DefaultRateLimitMaxCooldown, "连续被限流时暂停写操作时间的上限")
+	flag.Var(&chromeArgs, "chrome-arg", "额外的 Chromium 启动参数，必须以 -- 开头，可重复指定，如 -chrome-arg=--disable-dev-shm-usage")
+	flag.StringVar(&auditLog, "audit-log", "", "审计日志文件路径，记录每次发布、编辑、评论操作，为空表示不记录")
+	flag.Int64Var(&auditLogMaxSize, "audit-log-max-size", configs.DefaultAuditLogMaxSize, "审计日志文件大小上限（字节），超出后轮转")
+	flag.Float64Var(&logSampleRate, "log-sample-rate", configs.DefaultLogSampleRate, "成功请求记录访问日志的比例，0 到 1 之间，如 0.1 表示约十分之一；失败请求始终记录")
+	flag.StringVar(&corsOrigins, "cors-origins", "", "允许跨域访问的来源，逗号分隔，如 https://a.example.com,http://localhost:3000；为空时允许任意来源")
+	flag.BoolVar(&safeMode, "safe-mode", false, "安全模式，禁用发布、编辑、评论、退出登录等会修改账号的工具和接口，只保留只读操作，适合把服务开放给不受信任的调用方做数据分析")
+	flag.IntVar(&maxImages, "max-images", configs.DefaultMaxImages, "单篇笔记最多可上传的图片数，超出时在启动浏览器前返回 INVALID_ARGS")
+	flag.IntVar(&maxContentWidth, "max-content-width", configs.DefaultMaxContentWidth, "正文的最大宽度，中文字计 2、英文字母和数字计 1，超出时在启动浏览器前返回 INVALID_ARGS")
+	flag.BoolVar(&publishVerify, "publish-verify", false, "发布后到创作者中心确认笔记是否真的发出，发布报错但已发出时按成功返回，避免重试造成重复发布；需要额外启动一次浏览器")
+	flag.IntVar(&maxScrolls, "max-scrolls", configs.DefaultMaxScrolls, "单次提取（用户笔记、评论、发现页）最多滚动的次数，与 limit 无关；达到上限时返回已加载的部分并标记 truncated")
+	flag.DurationVar(&publishConfirmTimeout, "publish-confirm-timeout", 0, "点击发布后等待页面出现发布成功提示或跳转到发布成功页的最长时间，超时返回 PUBLISH_UNCONFIRMED；0 表示不等待")
+	flag.IntVar(&imageDownloadWorkers, "image-download-workers", configs.DefaultImageDownloadWorkers, "发布、编辑时同时下载的 URL 图片数量")
+	flag.DurationVar(&imageDownloadTimeout, "image-download-timeout", configs.DefaultImageDownloadTimeout, "单张 URL 图片的下载超时时间，0 表示只受写操作超时限制")
+	flag.StringVar(&imageHostAllow, "image-host-allow", "", "只允许从这些主机下载 URL 图片，逗号分隔的主机名（含子域名）或 CIDR，如 xhscdn.com,203.0.113.0/24；为空时不限制")
+	flag.StringVar(&imageHostDeny, "image-host-deny", "", "拒绝从这些主机下载 URL 图片，逗号分隔的主机名（含子域名）或 CIDR，优先于 -image-host-allow")
+	flag.BoolVar(&imageAllowPrivate, "image-allow-private", false, "允许从内网、回环等地址下载 URL 图片；默认拒绝，防止服务被用来访问内网（SSRF）")
+	flag.Int64Var(&maxBodyBytes, "max-body-bytes", configs.DefaultMaxBodyBytes, "请求体的字节数上限，超出时返回 413")
+	flag.IntVar(&maxOutputBytes, "max-output-bytes", configs.DefaultMaxOutputBytes, "MCP 工具结果文本的字节数上限，超出时截断列表并标记 truncated，0 表示不限制")
+	flag.StringVar(&toolOutputLimits, "tool-output-limits", "", "按工具设置结果字节数上限，如 list_feeds=50000,get_feed_detail=0，优先于 -max-output-bytes")
+	flag.StringVar(&baseURL, "base-url", configs.DefaultBaseURL, "小红书网页版地址，用于接入其他域名、边缘节点或本地模拟服务")
+	flag.StringVar(&creatorBaseURL, "creator-base-url", configs.DefaultCreatorBaseURL, "小红书创作者中心地址，发布、编辑笔记和话题搜索使用")
+	flag.BoolVar(&mock, "mock", false, "mock 模式，不启动浏览器，所有接口返回内置的固定数据，用于对接和 CI 测试")
+	flag.BoolVar(&selftest, "selftest", false, "自检模式：检查浏览器能否启动、是否已登录、能否读取首页 Feeds，打印结果后退出，不启动 HTTP 服务；有检查失败时退出码为 1")
+	flag.BoolVar(&debug, "debug", false, "调试模式，提供 debug_page_html 工具用于排查页面改版问题")
+	sources, err := loadFlags(flag.CommandLine, os.Args[1:])
+	if err != nil {
+		logrus.Fatalf("invalid environment variable: %v", err)
+	}
+	logEffectiveConfig(flag.CommandLine, sources)
 
-	configs.InitHeadless(headless)
+	if headlessMode == "" {
+		headlessMode = strconv.FormatBool(headless)
+	}
+	if err := configs.SetHeadlessMode(headlessMode); err != nil {
+		logrus.Fatalf("invalid -headless-mode: %v", err)
+	}
+	configs.SetAllowVisibleBrowser(allowVisible)
 	configs.SetBinPath(binPath)
+	configs.SetUserAgent(userAgent)
+	if err := configs.SetLang(lang); err != nil {
+		logrus.Fatalf("invalid -lang: %v", err)
+	}
+	if err := configs.SetViewport(viewport); err != nil {
+		logrus.Fatalf("invalid -viewport: %v", err)
+	}
+	if err := configs.SetChromeArgs(chromeArgs); err != nil {
+		logrus.Fatalf("invalid -chrome-arg: %v", err)
+	}
+	if err := configs.SetBaseURL(baseURL); err != nil {
+		logrus.Fatalf("invalid -base-url: %v", err)
+	}
+	if err := configs.SetCreatorBaseURL(creatorBaseURL); err != nil {
+		logrus.Fatalf("invalid -creator-base-url: %v", err)
+	}
+	configs.SetSessionDir(sessionDir)
+	if err := configs.SetTempDir(tempDir); err != nil {
+		logrus.Fatalf("invalid -temp-dir: %v", err)
+	}
+	downloader.SetTempDir(configs.GetTempDir())
+	if err := downloader.SetHostPolicy(strings.Split(imageHostAllow, ","), strings.Split(imageHostDeny, ","), imageAllowPrivate); err != nil {
+		logrus.Fatalf("invalid -image-host-allow/-image-host-deny: %v", err)
+	}
+	if err := configs.SetCORSOrigins(corsOrigins); err != nil {
+		logrus.Fatalf("invalid -cors-origins: %v", err)
+	}
+	configs.SetSafeMode(safeMode)
+	configs.SetMaxImages(maxImages)
+	configs.SetMaxContentWidth(maxContentWidth)
+	configs.SetPublishVerify(publishVerify)
+	configs.SetPublishConfirmTimeout(publishConfirmTimeout)
+	configs.SetMaxScrolls(maxScrolls)
+	configs.SetImageDownloadWorkers(imageDownloadWorkers)
+	configs.SetImageDownloadTimeout(imageDownloadTimeout)
+	configs.SetMaxBodyBytes(maxBodyBytes)
+	configs.SetMaxOutputBytes(maxOutputBytes)
+	if err := configs.SetToolOutputLimits(toolOutputLimits); err != nil {
+		logrus.Fatalf("invalid -tool-output-limits: %v", err)
+	}
+	configs.SetScreenshotOnError(screenshotOnError)
+	configs.SetScreenshotDir(screenshotDir)
+	configs.SetMaxScreenshots(maxScreenshots)
+	configs.SetDebug(debug)
+	configs.SetAuditLogPath(auditLog)
+	configs.SetAuditLogMaxSize(auditLogMaxSize)
+	if err := configs.SetLogSampleRate(logSampleRate); err != nil {
+		logrus.Fatalf("invalid -log-sample-rate: %v", err)
+	}
+	configs.SetTimeout(timeout)
+	configs.SetWriteTimeout(writeTimeout)
+	configs.SetNavTimeout(navTimeout)
+	configs.SetElementTimeout(elementTimeout)
+	configs.SetCaptchaWait(captchaWait)
+	configs.SetHumanize(humanize)
+	if err := configs.SetHumanizeDelay(humanizeMinDelay, humanizeMaxDelay); err != nil {
+		logrus.Fatalf("invalid -humanize-min-delay/-humanize-max-delay: %v", err)
+	}
+	configs.SetLoginCheckInterval(loginCheckInterval)
+	configs.SetSessionKeepAlive(sessionKeepAlive, sessionKeepAliveInterval)
+	configs.SetMaxBrowsers(maxBrowsers)
+	configs.SetBrowserWait(browserWait)
+	configs.SetMaxConnections(maxConnections)
+	configs.SetRateLimitCooldown(rateLimitCooldown, rateLimitMaxCooldown)
+	configs.SetCacheTTL(cacheTTL)
+	configs.SetCacheMaxEntries(cacheMaxEntries)
 
 	// 初始化服务
-	xiaohongshuService := NewXiaohongshuService()
+	var xiaohongshuService XHSService
+	if mock {
+		service, err := newMockService()
+		if err != nil {
+			logrus.Fatalf("failed to load mock data: %v", err)
+		}
+		logrus.Warn("mock 模式：所有接口返回固定数据，不会访问小红书")
+		xiaohongshuService = service
+	} else {
+		logRestoredSession()
+		xiaohongshuService = NewXiaohongshuService()
+	}
+
+	if selftest {
+		ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+		ok := runSelfTest(ctx, xiaohongshuService, os.Stdout)
+		stop()
+		if !ok {
+			os.Exit(1)
+		}
+		return
+	}
 
 	// 创建并启动应用服务器
 	appServer := NewAppServer(xiaohongshuService)
@@ -28,3 +259,15 @@ func main() {
 		logrus.Fatalf("failed to run server: %v", err)
 	}
 }
+
+// stringsFlag 可重复指定的字符串参数
+type stringsFlag []string
+
+func (f *stringsFlag) String() string {
+	return strings.Join(*f, " ")
+}
+
+func (f *stringsFlag) Set(value string) error {
+	*f = append(*f, value)
+	return nil
+}
@@ -2,28 +2,27 @@ package main
 
 import (
 	"context"
+	"encoding/base64"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"time"
 
 	"github.com/sirupsen/logrus"
+	"github.com/xpzouying/xiaohongshu-mcp/xiaohongshu"
 )
 
 // MCP 工具处理函数
 
 // handleCheckLoginStatus 处理检查登录状态
 func (s *AppServer) handleCheckLoginStatus(ctx context.Context) *MCPToolResult {
-	logrus.Info("MCP: 检查登录状态")
+	logrus.WithContext(ctx).Info("MCP: 检查登录状态")
 
 	status, err := s.xiaohongshuService.CheckLoginStatus(ctx)
 	if err != nil {
-		return &MCPToolResult{
-			Content: []MCPContent{{
-				Type: "text",
-				Text: "检查登录状态失败: " + err.Error(),
-			}},
-			IsError: true,
-		}
+		return toolErrorResult("检查登录状态失败: "+err.Error(), err)
 	}
+	s.updateLoginState(status)
 
 	resultText := fmt.Sprintf("登录状态检查成功: %+v", status)
 	return &MCPToolResult{
@@ -34,15 +33,68 @@ func (s *AppServer) handleCheckLoginStatus(ctx context.Context) *MCPToolResult {
 	}
 }
 
+// handleLogout 退出当前账号并删除保存的登录会话
+func (s *AppServer) handleLogout(ctx context.Context) *MCPToolResult {
+	logrus.WithContext(ctx).Info("MCP: 退出登录")
+
+	result, err := s.xiaohongshuService.Logout(ctx)
+	if err != nil {
+		return toolErrorResult("退出登录失败: "+err.Error(), err)
+	}
+	s.updateLoginState(&LoginStatusResponse{})
+
+	return &MCPToolResult{
+		Content: []MCPContent{{
+			Type: "text",
+			Text: result.Message,
+		}},
+	}
+}
+
+// handleCheckAllLogins 检查所有已配置账号的登录状态
+func (s *AppServer) handleCheckAllLogins(ctx context.Context) *MCPToolResult {
+	logrus.WithContext(ctx).Info("MCP: 检查所有账号的登录状态")
+
+	result, err := s.xiaohongshuService.CheckAllLogins(ctx)
+	if err != nil {
+		return toolErrorResult("检查所有账号的登录状态失败: "+err.Error(), err)
+	}
+
+	// 格式化输出，转换为JSON字符串
+	jsonData, err := json.MarshalIndent(result, "", "  ")
+	if err != nil {
+		return toolErrorResult(fmt.Sprintf("检查所有账号的登录状态成功，但序列化失败: %v", err), err)
+	}
+
+	return &MCPToolResult{
+		Content: []MCPContent{{
+			Type: "text",
+			Text: string(jsonData),
+		}},
+	}
+}
+
 // handlePublishContent 处理发布内容
 func (s *AppServer) handlePublishContent(ctx context.Context, args map[string]interface{}) *MCPToolResult {
-	logrus.Info("MCP: 发布内容")
+	logrus.WithContext(ctx).Info("MCP: 发布内容")
 
 	// 解析参数
 	title, _ := args["title"].(string)
 	content, _ := args["content"].(string)
 	imagePathsInterface, _ := args["images"].([]interface{})
+	imageAltsInterface, _ := args["image_alts"].([]interface{})
 	tagsInterface, _ := args["tags"].([]interface{})
+	coverIndex, _ := args["cover_index"].(float64)
+	visibility, _ := args["visibility"].(string)
+	imageReferer, _ := args["image_referer"].(string)
+	imageHeadersInterface, _ := args["image_headers"].(map[string]interface{})
+	visible, _ := args["visible"].(bool)
+
+	// visible 不可用属于参数错误，不按发布失败的错误码处理
+	ctx, err := withVisibleBrowser(ctx, visible)
+	if err != nil {
+		return toolErrorResult("发布失败: "+err.Error(), err)
+	}
 
 	var imagePaths []string
 	for _, path := range imagePathsInterface {
@@ -51,6 +103,13 @@ func (s *AppServer) handlePublishContent(ctx context.Context, args map[string]in
 		}
 	}
 
+	// 图片描述按序号与图片对应，非字符串的元素按空描述处理，保持序号不变
+	var imageAlts []string
+	for _, alt := range imageAltsInterface {
+		altStr, _ := alt.(string)
+		imageAlts = append(imageAlts, altStr)
+	}
+
 	var tags []string
 	for _, tag := range tagsInterface {
 		if tagStr, ok := tag.(string); ok {
@@ -58,26 +117,37 @@ func (s *AppServer) handlePublishContent(ctx context.Context, args map[string]in
 		}
 	}
 
-	logrus.Infof("MCP: 发布内容 - 标题: %s, 图片数量: %d, 标签数量: %d", title, len(imagePaths), len(tags))
+	var imageHeaders map[string]string
+	for key, value := range imageHeadersInterface {
+		if valueStr, ok := value.(string); ok {
+			if imageHeaders == nil {
+				imageHeaders = make(map[string]string, len(imageHeadersInterface))
+			}
+			imageHeaders[key] = valueStr
+		}
+	}
+
+	logrus.WithContext(ctx).Infof("MCP: 发布内容 - 标题: %s, 图片数量: %d, 标签数量: %d", title, len(imagePaths), len(tags))
 
 	// 构建发布请求
 	req := &PublishRequest{
-		Title:   title,
-		Content: content,
-		Images:  imagePaths,
-		Tags:    tags,
+		Title:      title,
+		Content:    content,
+		Images:     imagePaths,
+		Tags:       tags,
+		CoverIndex: int(coverIndex),
+		Visibility: visibility,
+		ImageAlts:  imageAlts,
+
+		ImageHeaders: imageHeaders,
+		ImageReferer: imageReferer,
 	}
 
 	// 执行发布
 	result, err := s.xiaohongshuService.PublishContent(ctx, req)
 	if err != nil {
-		return &MCPToolResult{
-			Content: []MCPContent{{
-				Type: "text",
-				Text: "发布失败: " + err.Error(),
-			}},
-			IsError: true,
-		}
+		// 带上错误码，便于客户端区分未登录、上传失败、内容被拦截等原因
+		return toolErrorResult(fmt.Sprintf("发布失败: %s，%s", publishErrorCode(err), err.Error()), err)
 	}
 
 	resultText := fmt.Sprintf("内容发布成功: %+v", result)
@@ -91,78 +161,175 @@ func (s *AppServer) handlePublishContent(ctx context.Context, args map[string]in
 
 // handleListFeeds 处理获取Feeds列表
 func (s *AppServer) handleListFeeds(ctx context.Context) *MCPToolResult {
-	logrus.Info("MCP: 获取Feeds列表")
+	logrus.WithContext(ctx).Info("MCP: 获取Feeds列表")
 
 	result, err := s.xiaohongshuService.ListFeeds(ctx)
 	if err != nil {
-		return &MCPToolResult{
-			Content: []MCPContent{{
-				Type: "text",
-				Text: "获取Feeds列表失败: " + err.Error(),
-			}},
-			IsError: true,
-		}
+		return toolErrorResult("获取Feeds列表失败: "+err.Error(), err)
 	}
 
 	// 格式化输出，转换为JSON字符串
 	jsonData, err := json.MarshalIndent(result, "", "  ")
 	if err != nil {
-		return &MCPToolResult{
-			Content: []MCPContent{{
-				Type: "text",
-				Text: fmt.Sprintf("获取Feeds列表成功，但序列化失败: %v", err),
-			}},
-			IsError: true,
-		}
+		return toolErrorResult(fmt.Sprintf("获取Feeds列表成功，但序列化失败: %v", err), err)
 	}
 
-	return &MCPToolResult{
+	return withNoFeedsHint(&MCPToolResult{
+		Content: []MCPContent{{
+			Type: "text",
+			Text: string(jsonData),
+		}},
+	}, result.Count)
+}
+
+// handleExploreFeeds 获取发现页的推荐笔记
+func (s *AppServer) handleExploreFeeds(ctx context.Context, args map[string]any) *MCPToolResult {
+	channel, _ := args["channel"].(string)
+	limit, _ := args["limit"].(float64)
+	if _, err := exploreChannelID(channel); err != nil {
+		return toolErrorResult("获取发现页笔记失败: "+err.Error(), err)
+	}
+
+	logrus.WithContext(ctx).Infof("MCP: 获取发现页笔记 - 频道: %s, limit: %d", channel, int(limit))
+
+	result, err := s.xiaohongshuService.ExploreFeeds(ctx, channel, int(limit))
+	if err != nil {
+		return toolErrorResult("获取发现页笔记失败: "+err.Error(), err)
+	}
+
+	// 格式化输出，转换为JSON字符串
+	jsonData, err := json.MarshalIndent(result, "", "  ")
+	if err != nil {
+		return toolErrorResult(fmt.Sprintf("获取发现页笔记成功，但序列化失败: %v", err), err)
+	}
+
+	return withNoFeedsHint(&MCPToolResult{
 		Content: []MCPContent{{
 			Type: "text",
 			Text: string(jsonData),
 		}},
+	}, result.Count)
+}
+
+// withNoFeedsHint 列表、搜索没有结果时在 JSON 之后附加一段说明，
+// 让调用方明确这是成功但结果为空，而不是调用失败
+func withNoFeedsHint(result *MCPToolResult, count int) *MCPToolResult {
+	if count == 0 {
+		result.Content = append(result.Content, MCPContent{
+			Type: "text",
+			Text: noFeedsMessage + "（调用成功，结果为空）",
+		})
 	}
+	return result
 }
 
 // handleSearchFeeds 处理搜索Feeds
 func (s *AppServer) handleSearchFeeds(ctx context.Context, args map[string]interface{}) *MCPToolResult {
-	logrus.Info("MCP: 搜索Feeds")
+	logrus.WithContext(ctx).Info("MCP: 搜索Feeds")
 
 	// 解析参数
 	keyword, ok := args["keyword"].(string)
 	if !ok || keyword == "" {
-		return &MCPToolResult{
-			Content: []MCPContent{{
-				Type: "text",
-				Text: "搜索Feeds失败: 缺少关键词参数",
-			}},
-			IsError: true,
-		}
+		return toolErrorResult("搜索Feeds失败: 缺少关键词参数", ErrInvalidArgs)
 	}
 
-	logrus.Infof("MCP: 搜索Feeds - 关键词: %s", keyword)
+	logrus.WithContext(ctx).Infof("MCP: 搜索Feeds - 关键词: %s", keyword)
 
-	result, err := s.xiaohongshuService.SearchFeeds(ctx, keyword)
+	fresh, _ := args["fresh"].(bool)
+	result, err := s.xiaohongshuService.SearchFeeds(withFreshRead(ctx, fresh), keyword)
 	if err != nil {
-		return &MCPToolResult{
-			Content: []MCPContent{{
-				Type: "text",
-				Text: "搜索Feeds失败: " + err.Error(),
-			}},
-			IsError: true,
-		}
+		return toolErrorResult("搜索Feeds失败: "+err.Error(), err)
 	}
 
 	// 格式化输出，转换为JSON字符串
 	jsonData, err := json.MarshalIndent(result, "", "  ")
 	if err != nil {
-		return &MCPToolResult{
-			Content: []MCPContent{{
-				Type: "text",
-				Text: fmt.Sprintf("搜索Feeds成功，但序列化失败: %v", err),
-			}},
-			IsError: true,
-		}
+		return toolErrorResult(fmt.Sprintf("搜索Feeds成功，但序列化失败: %v", err), err)
+	}
+
+	return withNoFeedsHint(&MCPToolResult{
+		Content: []MCPContent{{
+			Type: "text",
+			Text: string(jsonData),
+		}},
+		StructuredContent: result,
+	}, result.Count)
+}
+
+// handleSearchAndDetail 搜索并获取前 N 篇笔记的详情
+func (s *AppServer) handleSearchAndDetail(ctx context.Context, args map[string]interface{}) *MCPToolResult {
+	keyword, _ := args["keyword"].(string)
+	if keyword == "" {
+		return toolErrorResult("搜索并获取详情失败: 缺少关键词参数", ErrInvalidArgs)
+	}
+	limit, _ := args["limit"].(float64)
+	fresh, _ := args["fresh"].(bool)
+
+	logrus.WithContext(ctx).Infof("MCP: 搜索并获取详情 - 关键词: %s, limit: %d", keyword, int(limit))
+
+	result, err := s.xiaohongshuService.SearchAndDetail(withFreshRead(ctx, fresh), keyword, int(limit))
+	if err != nil {
+		return toolErrorResult("搜索并获取详情失败: "+err.Error(), err)
+	}
+
+	// 格式化输出，转换为JSON字符串
+	jsonData, err := json.MarshalIndent(result, "", "  ")
+	if err != nil {
+		return toolErrorResult(fmt.Sprintf("搜索并获取详情成功，但序列化失败: %v", err), err)
+	}
+
+	return withNoFeedsHint(&MCPToolResult{
+		Content: []MCPContent{{
+			Type: "text",
+			Text: string(jsonData),
+		}},
+	}, result.Count)
+}
+
+// handleSearchTopics 处理搜索话题
+func (s *AppServer) handleSearchTopics(ctx context.Context, args map[string]interface{}) *MCPToolResult {
+	logrus.WithContext(ctx).Info("MCP: 搜索话题")
+
+	// 解析参数
+	keyword, ok := args["keyword"].(string)
+	if !ok || keyword == "" {
+		return toolErrorResult("搜索话题失败: 缺少关键词参数", ErrInvalidArgs)
+	}
+
+	logrus.WithContext(ctx).Infof("MCP: 搜索话题 - 关键词: %s", keyword)
+
+	result, err := s.xiaohongshuService.SearchTopics(ctx, keyword)
+	if err != nil {
+		return toolErrorResult("搜索话题失败: "+err.Error(), err)
+	}
+
+	// 格式化输出，转换为JSON字符串
+	jsonData, err := json.MarshalIndent(result, "", "  ")
+	if err != nil {
+		return toolErrorResult(fmt.Sprintf("搜索话题成功，但序列化失败: %v", err), err)
+	}
+
+	return &MCPToolResult{
+		Content: []MCPContent{{
+			Type: "text",
+			Text: string(jsonData),
+		}},
+	}
+}
+
+// handleTrendingTopics 处理获取热门话题
+func (s *AppServer) handleTrendingTopics(ctx context.Context) *MCPToolResult {
+	logrus.WithContext(ctx).Info("MCP: 获取热门话题")
+
+	result, err := s.xiaohongshuService.TrendingTopics(ctx)
+	if err != nil {
+		return toolErrorResult("获取热门话题失败: "+err.Error(), err)
+	}
+
+	// 格式化输出，转换为JSON字符串
+	jsonData, err := json.MarshalIndent(result, "", "  ")
+	if err != nil {
+		return toolErrorResult(fmt.Sprintf("获取热门话题成功，但序列化失败: %v", err), err)
 	}
 
 	return &MCPToolResult{
@@ -175,54 +342,160 @@ func (s *AppServer) handleSearchFeeds(ctx context.Context, args map[string]inter
 
 // handleGetFeedDetail 处理获取Feed详情
 func (s *AppServer) handleGetFeedDetail(ctx context.Context, args map[string]any) *MCPToolResult {
-	logrus.Info("MCP: 获取Feed详情")
+	logrus.WithContext(ctx).Info("MCP: 获取Feed详情")
 
 	// 解析参数
 	feedID, ok := args["feed_id"].(string)
 	if !ok || feedID == "" {
-		return &MCPToolResult{
-			Content: []MCPContent{{
-				Type: "text",
-				Text: "获取Feed详情失败: 缺少feed_id参数",
-			}},
-			IsError: true,
-		}
+		return toolErrorResult("获取Feed详情失败: 缺少feed_id参数", ErrInvalidArgs)
 	}
 
 	xsecToken, ok := args["xsec_token"].(string)
 	if !ok || xsecToken == "" {
-		return &MCPToolResult{
-			Content: []MCPContent{{
-				Type: "text",
-				Text: "获取Feed详情失败: 缺少xsec_token参数",
-			}},
-			IsError: true,
-		}
+		return toolErrorResult("获取Feed详情失败: 缺少xsec_token参数", ErrInvalidArgs)
 	}
 
-	logrus.Infof("MCP: 获取Feed详情 - Feed ID: %s", feedID)
+	logrus.WithContext(ctx).Infof("MCP: 获取Feed详情 - Feed ID: %s", feedID)
 
-	result, err := s.xiaohongshuService.GetFeedDetail(ctx, feedID, xsecToken)
+	fresh, _ := args["fresh"].(bool)
+	result, err := s.xiaohongshuService.GetFeedDetail(withFreshRead(ctx, fresh), feedID, xsecToken)
 	if err != nil {
-		return &MCPToolResult{
-			Content: []MCPContent{{
-				Type: "text",
-				Text: "获取Feed详情失败: " + err.Error(),
-			}},
-			IsError: true,
-		}
+		return toolErrorResult("获取Feed详情失败: "+err.Error(), err)
+	}
+
+	// 格式化输出，转换为JSON字符串
+	jsonData, err := json.MarshalIndent(result, "", "  ")
+	if err != nil {
+		return toolErrorResult(fmt.Sprintf("获取Feed详情成功，但序列化失败: %v", err), err)
+	}
+
+	return &MCPToolResult{
+		Content: []MCPContent{{
+			Type: "text",
+			Text: string(jsonData),
+		}},
+	}
+}
+
+// handleGetFeedTags 获取笔记的话题标签
+func (s *AppServer) handleGetFeedTags(ctx context.Context, args map[string]any) *MCPToolResult {
+	feedID, _ := args["feed_id"].(string)
+	xsecToken, _ := args["xsec_token"].(string)
+	if feedID == "" || xsecToken == "" {
+		return toolErrorResult("获取笔记话题失败: 缺少feed_id或xsec_token参数", ErrInvalidArgs)
+	}
+
+	logrus.WithContext(ctx).Infof("MCP: 获取笔记话题 - Feed ID: %s", feedID)
+
+	result, err := s.xiaohongshuService.GetFeedTags(ctx, feedID, xsecToken)
+	if err != nil {
+		return toolErrorResult("获取笔记话题失败: "+err.Error(), err)
+	}
+
+	// 格式化输出，转换为JSON字符串
+	jsonData, err := json.MarshalIndent(result, "", "  ")
+	if err != nil {
+		return toolErrorResult(fmt.Sprintf("获取笔记话题成功，但序列化失败: %v", err), err)
+	}
+
+	return &MCPToolResult{
+		Content: []MCPContent{{
+			Type: "text",
+			Text: string(jsonData),
+		}},
+	}
+}
+
+// handleRelatedFeeds 获取笔记详情页的相关推荐
+func (s *AppServer) handleRelatedFeeds(ctx context.Context, args map[string]any) *MCPToolResult {
+	feedID, _ := args["feed_id"].(string)
+	xsecToken, _ := args["xsec_token"].(string)
+	if feedID == "" || xsecToken == "" {
+		return toolErrorResult("获取相关推荐失败: 缺少feed_id或xsec_token参数", ErrInvalidArgs)
+	}
+
+	logrus.WithContext(ctx).Infof("MCP: 获取相关推荐 - Feed ID: %s", feedID)
+
+	result, err := s.xiaohongshuService.RelatedFeeds(ctx, feedID, xsecToken)
+	if err != nil {
+		return toolErrorResult("获取相关推荐失败: "+err.Error(), err)
+	}
+
+	// 格式化输出，转换为JSON字符串
+	jsonData, err := json.MarshalIndent(result, "", "  ")
+	if err != nil {
+		return toolErrorResult(fmt.Sprintf("获取相关推荐成功，但序列化失败: %v", err), err)
+	}
+
+	return &MCPToolResult{
+		Content: []MCPContent{{
+			Type: "text",
+			Text: string(jsonData),
+		}},
+	}
+}
+
+// handleScreenshotFeed 截取笔记详情页，返回截图信息和 PNG 图片
+func (s *AppServer) handleScreenshotFeed(ctx context.Context, args map[string]any) *MCPToolResult {
+	feedID, _ := args["feed_id"].(string)
+	xsecToken, _ := args["xsec_token"].(string)
+	if feedID == "" || xsecToken == "" {
+		return toolErrorResult("笔记截图失败: 缺少feed_id或xsec_token参数", ErrInvalidArgs)
+	}
+	width, _ := args["width"].(float64)
+	scope, _ := args["scope"].(string)
+	if err := xiaohongshu.ValidateScreenshotOptions(int(width), scope); err != nil {
+		return toolErrorResult("笔记截图失败: "+err.Error(), ErrInvalidArgs)
+	}
+
+	logrus.WithContext(ctx).Infof("MCP: 笔记截图 - Feed ID: %s, width: %d, scope: %s", feedID, int(width), scope)
+
+	result, err := s.xiaohongshuService.ScreenshotFeed(ctx, feedID, xsecToken, int(width), scope)
+	if err != nil {
+		return toolErrorResult("笔记截图失败: "+err.Error(), err)
 	}
 
 	// 格式化输出，转换为JSON字符串
 	jsonData, err := json.MarshalIndent(result, "", "  ")
 	if err != nil {
-		return &MCPToolResult{
-			Content: []MCPContent{{
+		return toolErrorResult(fmt.Sprintf("笔记截图成功，但序列化失败: %v", err), err)
+	}
+
+	return &MCPToolResult{
+		Content: []MCPContent{
+			{
 				Type: "text",
-				Text: fmt.Sprintf("获取Feed详情成功，但序列化失败: %v", err),
-			}},
-			IsError: true,
-		}
+				Text: string(jsonData),
+			},
+			{
+				Type:     "image",
+				Data:     base64.StdEncoding.EncodeToString(result.Image),
+				MimeType: "image/png",
+			},
+		},
+	}
+}
+
+// handleGetFeedLink 获取笔记链接，short 为 true 时获取分享短链
+func (s *AppServer) handleGetFeedLink(ctx context.Context, args map[string]any) *MCPToolResult {
+	feedID, _ := args["feed_id"].(string)
+	xsecToken, _ := args["xsec_token"].(string)
+	if feedID == "" || xsecToken == "" {
+		return toolErrorResult("获取笔记链接失败: 缺少feed_id或xsec_token参数", ErrInvalidArgs)
+	}
+	short, _ := args["short"].(bool)
+
+	logrus.WithContext(ctx).Infof("MCP: 获取笔记链接 - Feed ID: %s, short: %v", feedID, short)
+
+	result, err := s.xiaohongshuService.GetFeedLink(ctx, feedID, xsecToken, short)
+	if err != nil {
+		return toolErrorResult("获取笔记链接失败: "+err.Error(), err)
+	}
+
+	// 格式化输出，转换为JSON字符串
+	jsonData, err := json.MarshalIndent(result, "", "  ")
+	if err != nil {
+		return toolErrorResult(fmt.Sprintf("获取笔记链接成功，但序列化失败: %v", err), err)
 	}
 
 	return &MCPToolResult{
@@ -233,56 +506,348 @@ func (s *AppServer) handleGetFeedDetail(ctx context.Context, args map[string]any
 	}
 }
 
-// handleUserProfile 获取用户主页
-func (s *AppServer) handleUserProfile(ctx context.Context, args map[string]any) *MCPToolResult {
-	logrus.Info("MCP: 获取用户主页")
+// handleGetFeedByURL 处理通过分享链接获取Feed详情
+func (s *AppServer) handleGetFeedByURL(ctx context.Context, args map[string]any) *MCPToolResult {
+	logrus.WithContext(ctx).Info("MCP: 通过链接获取Feed详情")
 
 	// 解析参数
-	userID, ok := args["user_id"].(string)
-	if !ok || userID == "" {
-		return &MCPToolResult{
-			Content: []MCPContent{{
-				Type: "text",
-				Text: "获取用户主页失败: 缺少user_id参数",
-			}},
-			IsError: true,
-		}
+	url, ok := args["url"].(string)
+	if !ok || url == "" {
+		return toolErrorResult("获取Feed详情失败: 缺少url参数", ErrInvalidArgs)
+	}
+
+	logrus.WithContext(ctx).Infof("MCP: 通过链接获取Feed详情 - URL: %s", url)
+
+	result, err := s.xiaohongshuService.GetFeedByURL(ctx, url)
+	if err != nil {
+		return toolErrorResult("获取Feed详情失败: "+err.Error(), err)
+	}
+
+	// 格式化输出，转换为JSON字符串
+	jsonData, err := json.MarshalIndent(result, "", "  ")
+	if err != nil {
+		return toolErrorResult(fmt.Sprintf("获取Feed详情成功，但序列化失败: %v", err), err)
+	}
+
+	return &MCPToolResult{
+		Content: []MCPContent{{
+			Type: "text",
+			Text: string(jsonData),
+		}},
+	}
+}
+
+// handleHideFeed 处理修改笔记可见范围，默认设为仅自己可见
+func (s *AppServer) handleHideFeed(ctx context.Context, args map[string]any) *MCPToolResult {
+	logrus.WithContext(ctx).Info("MCP: 修改笔记可见范围")
+
+	feedID, ok := args["feed_id"].(string)
+	if !ok || feedID == "" {
+		return toolErrorResult("修改笔记可见范围失败: 缺少feed_id参数", ErrInvalidArgs)
 	}
 
 	xsecToken, ok := args["xsec_token"].(string)
 	if !ok || xsecToken == "" {
-		return &MCPToolResult{
-			Content: []MCPContent{{
-				Type: "text",
-				Text: "获取用户主页失败: 缺少xsec_token参数",
-			}},
-			IsError: true,
-		}
+		return toolErrorResult("修改笔记可见范围失败: 缺少xsec_token参数", ErrInvalidArgs)
 	}
 
-	logrus.Infof("MCP: 获取用户主页 - User ID: %s", userID)
+	visibility, _ := args["visibility"].(string)
 
-	result, err := s.xiaohongshuService.UserProfile(ctx, userID, xsecToken)
+	logrus.WithContext(ctx).Infof("MCP: 修改笔记可见范围 - Feed ID: %s, 可见范围: %s", feedID, visibility)
+
+	result, err := s.xiaohongshuService.SetFeedVisibility(ctx, feedID, xsecToken, visibility)
 	if err != nil {
-		return &MCPToolResult{
-			Content: []MCPContent{{
-				Type: "text",
-				Text: "获取用户主页失败: " + err.Error(),
-			}},
-			IsError: true,
+		return toolErrorResult("修改笔记可见范围失败: "+err.Error(), err)
+	}
+
+	return &MCPToolResult{
+		Content: []MCPContent{{
+			Type: "text",
+			Text: fmt.Sprintf("%s: %s", result.Message, result.FeedID),
+		}},
+	}
+}
+
+// handleEditFeed 处理编辑笔记
+func (s *AppServer) handleEditFeed(ctx context.Context, args map[string]any) *MCPToolResult {
+	logrus.WithContext(ctx).Info("MCP: 编辑笔记")
+
+	// 解析参数
+	feedID, ok := args["feed_id"].(string)
+	if !ok || feedID == "" {
+		return toolErrorResult("编辑笔记失败: 缺少feed_id参数", ErrInvalidArgs)
+	}
+
+	xsecToken, ok := args["xsec_token"].(string)
+	if !ok || xsecToken == "" {
+		return toolErrorResult("编辑笔记失败: 缺少xsec_token参数", ErrInvalidArgs)
+	}
+
+	// 只修改提供了的字段
+	updates := EditFeedRequest{
+		FeedID:    feedID,
+		XsecToken: xsecToken,
+	}
+	if title, ok := args["title"].(string); ok {
+		updates.Title = &title
+	}
+	if content, ok := args["content"].(string); ok {
+		updates.Content = &content
+	}
+	if tagsInterface, ok := args["tags"].([]any); ok {
+		updates.Tags = []string{}
+		for _, tag := range tagsInterface {
+			if tagStr, ok := tag.(string); ok {
+				updates.Tags = append(updates.Tags, tagStr)
+			}
+		}
+	}
+	if imagesInterface, ok := args["images"].([]any); ok {
+		updates.Images = []string{}
+		for _, path := range imagesInterface {
+			if pathStr, ok := path.(string); ok {
+				updates.Images = append(updates.Images, pathStr)
+			}
 		}
 	}
 
+	logrus.WithContext(ctx).Infof("MCP: 编辑笔记 - Feed ID: %s", feedID)
+
+	result, err := s.xiaohongshuService.EditFeed(ctx, feedID, xsecToken, updates)
+	if err != nil {
+		return toolErrorResult("编辑笔记失败: "+err.Error(), err)
+	}
+
 	// 格式化输出，转换为JSON字符串
 	jsonData, err := json.MarshalIndent(result, "", "  ")
 	if err != nil {
-		return &MCPToolResult{
-			Content: []MCPContent{{
-				Type: "text",
-				Text: fmt.Sprintf("获取用户主页，但序列化失败: %v", err),
-			}},
-			IsError: true,
-		}
+		return toolErrorResult(fmt.Sprintf("编辑笔记成功，但序列化失败: %v", err), err)
+	}
+
+	return &MCPToolResult{
+		Content: []MCPContent{{
+			Type: "text",
+			Text: string(jsonData),
+		}},
+	}
+}
+
+// handleUserProfile 获取用户主页
+func (s *AppServer) handleUserProfile(ctx context.Context, args map[string]any) *MCPToolResult {
+	logrus.WithContext(ctx).Info("MCP: 获取用户主页")
+
+	// 解析参数：需要 user_id+xsec_token 或 profile_url 其中一组
+	req := &UserProfileRequest{}
+	req.UserID, _ = args["user_id"].(string)
+	req.XsecToken, _ = args["xsec_token"].(string)
+	req.ProfileURL, _ = args["profile_url"].(string)
+
+	if err := req.Validate(); err != nil {
+		return toolErrorResult("获取用户主页失败: "+err.Error(), err)
+	}
+
+	var (
+		result *UserProfileResponse
+		err    error
+	)
+	if req.ProfileURL != "" {
+		logrus.WithContext(ctx).Infof("MCP: 获取用户主页 - Profile URL: %s", req.ProfileURL)
+		result, err = s.xiaohongshuService.UserProfileByURL(ctx, req.ProfileURL)
+	} else {
+		logrus.WithContext(ctx).Infof("MCP: 获取用户主页 - User ID: %s", req.UserID)
+		result, err = s.xiaohongshuService.UserProfile(ctx, req.UserID, req.XsecToken)
+	}
+	if err != nil {
+		return toolErrorResult("获取用户主页失败: "+err.Error(), err)
+	}
+
+	// 格式化输出，转换为JSON字符串
+	jsonData, err := json.MarshalIndent(result, "", "  ")
+	if err != nil {
+		return toolErrorResult(fmt.Sprintf("获取用户主页，但序列化失败: %v", err), err)
+	}
+
+	return &MCPToolResult{
+		Content: []MCPContent{{
+			Type: "text",
+			Text: string(jsonData),
+		}},
+	}
+}
+
+// handleUserFeeds 分页获取用户主页的全部笔记
+func (s *AppServer) handleUserFeeds(ctx context.Context, args map[string]any) *MCPToolResult {
+	logrus.WithContext(ctx).Info("MCP: 获取用户笔记列表")
+
+	// 解析参数
+	userID, _ := args["user_id"].(string)
+	xsecToken, _ := args["xsec_token"].(string)
+	if userID == "" || xsecToken == "" {
+		return toolErrorResult("获取用户笔记失败: 缺少user_id或xsec_token参数", ErrInvalidArgs)
+	}
+	limit, _ := args["limit"].(float64)
+	cursor, _ := args["cursor"].(string)
+
+	logrus.WithContext(ctx).Infof("MCP: 获取用户笔记列表 - User ID: %s, limit: %d, cursor: %s", userID, int(limit), cursor)
+
+	result, err := s.xiaohongshuService.UserFeeds(ctx, userID, xsecToken, int(limit), cursor)
+	if errors.Is(err, xiaohongshu.ErrProfilePrivate) {
+		return toolErrorResult("获取用户笔记失败: PROFILE_PRIVATE，"+err.Error(), err)
+	}
+	if err != nil {
+		return toolErrorResult("获取用户笔记失败: "+err.Error(), err)
+	}
+
+	// 格式化输出，转换为JSON字符串
+	jsonData, err := json.MarshalIndent(result, "", "  ")
+	if err != nil {
+		return toolErrorResult(fmt.Sprintf("获取用户笔记成功，但序列化失败: %v", err), err)
+	}
+
+	return &MCPToolResult{
+		Content: []MCPContent{{
+			Type: "text",
+			Text: string(jsonData),
+		}},
+	}
+}
+
+// handleUserCollections 获取用户主页的收藏专辑列表
+func (s *AppServer) handleUserCollections(ctx context.Context, args map[string]any) *MCPToolResult {
+	userID, _ := args["user_id"].(string)
+	xsecToken, _ := args["xsec_token"].(string)
+	if userID == "" || xsecToken == "" {
+		return toolErrorResult("获取用户收藏专辑失败: 缺少user_id或xsec_token参数", ErrInvalidArgs)
+	}
+
+	logrus.WithContext(ctx).Infof("MCP: 获取用户收藏专辑 - User ID: %s", userID)
+
+	result, err := s.xiaohongshuService.UserCollections(ctx, userID, xsecToken)
+	if err != nil {
+		return toolErrorResult("获取用户收藏专辑失败: "+err.Error(), err)
+	}
+
+	jsonData, err := json.MarshalIndent(result, "", "  ")
+	if err != nil {
+		return toolErrorResult(fmt.Sprintf("获取用户收藏专辑成功，但序列化失败: %v", err), err)
+	}
+
+	return &MCPToolResult{
+		Content: []MCPContent{{
+			Type: "text",
+			Text: string(jsonData),
+		}},
+	}
+}
+
+// handleFeedAnalytics 获取当前账号某篇笔记的创作者中心数据
+func (s *AppServer) handleFeedAnalytics(ctx context.Context, args map[string]any) *MCPToolResult {
+	feedID, _ := args["feed_id"].(string)
+	if feedID == "" {
+		return toolErrorResult("获取笔记数据失败: 缺少feed_id参数", ErrInvalidArgs)
+	}
+
+	logrus.WithContext(ctx).Infof("MCP: 获取笔记数据 - Feed ID: %s", feedID)
+
+	result, err := s.xiaohongshuService.FeedAnalytics(ctx, feedID)
+	if errors.Is(err, xiaohongshu.ErrNotNoteOwner) {
+		return toolErrorResult("获取笔记数据失败: NOT_OWNER，"+err.Error(), err)
+	}
+	if err != nil {
+		return toolErrorResult("获取笔记数据失败: "+err.Error(), err)
+	}
+
+	// 格式化输出，转换为JSON字符串
+	jsonData, err := json.MarshalIndent(result, "", "  ")
+	if err != nil {
+		return toolErrorResult(fmt.Sprintf("获取笔记数据成功，但序列化失败: %v", err), err)
+	}
+
+	return &MCPToolResult{
+		Content: []MCPContent{{
+			Type: "text",
+			Text: string(jsonData),
+		}},
+	}
+}
+
+// handleMyProfile 获取当前登录账号的主页
+func (s *AppServer) handleMyProfile(ctx context.Context) *MCPToolResult {
+	logrus.WithContext(ctx).Info("MCP: 获取当前账号主页")
+
+	result, err := s.xiaohongshuService.MyProfile(ctx)
+	if errors.Is(err, xiaohongshu.ErrNotLoggedIn) {
+		return toolErrorResult("获取当前账号主页失败: NOT_LOGGED_IN，"+err.Error(), err)
+	}
+	if err != nil {
+		return toolErrorResult("获取当前账号主页失败: "+err.Error(), err)
+	}
+
+	// 格式化输出，转换为JSON字符串
+	jsonData, err := json.MarshalIndent(result, "", "  ")
+	if err != nil {
+		return toolErrorResult(fmt.Sprintf("获取当前账号主页成功，但序列化失败: %v", err), err)
+	}
+
+	return &MCPToolResult{
+		Content: []MCPContent{{
+			Type: "text",
+			Text: string(jsonData),
+		}},
+	}
+}
+
+// handleListNotifications 获取当前账号的通知
+func (s *AppServer) handleListNotifications(ctx context.Context, args map[string]interface{}) *MCPToolResult {
+	category, _ := args["category"].(string)
+	logrus.WithContext(ctx).Infof("MCP: 获取通知 - 类别: %s", category)
+
+	result, err := s.xiaohongshuService.ListNotifications(ctx, category)
+	if errors.Is(err, xiaohongshu.ErrNotLoggedIn) {
+		return toolErrorResult("获取通知失败: NOT_LOGGED_IN，"+err.Error(), err)
+	}
+	if err != nil {
+		return toolErrorResult("获取通知失败: "+err.Error(), err)
+	}
+
+	// 格式化输出，转换为JSON字符串
+	jsonData, err := json.MarshalIndent(result, "", "  ")
+	if err != nil {
+		return toolErrorResult(fmt.Sprintf("获取通知成功，但序列化失败: %v", err), err)
+	}
+
+	return &MCPToolResult{
+		Content: []MCPContent{{
+			Type: "text",
+			Text: string(jsonData),
+		}},
+	}
+}
+
+// handleGetCommentTree 获取笔记的评论树
+func (s *AppServer) handleGetCommentTree(ctx context.Context, args map[string]interface{}) *MCPToolResult {
+	feedID, _ := args["feed_id"].(string)
+	if feedID == "" {
+		return toolErrorResult("获取评论树失败: 缺少feed_id参数", ErrInvalidArgs)
+	}
+	xsecToken, _ := args["xsec_token"].(string)
+	if xsecToken == "" {
+		return toolErrorResult("获取评论树失败: 缺少xsec_token参数", ErrInvalidArgs)
+	}
+	limit, _ := args["limit"].(float64)
+	repliesLimit, _ := args["replies_limit"].(float64)
+	cursor, _ := args["cursor"].(string)
+	logrus.WithContext(ctx).Infof("MCP: 获取评论树 - Feed ID: %s, limit: %d, replies_limit: %d", feedID, int(limit), int(repliesLimit))
+
+	result, err := s.xiaohongshuService.GetCommentTree(ctx, feedID, xsecToken, int(limit), int(repliesLimit), cursor)
+	if err != nil {
+		return toolErrorResult("获取评论树失败: "+err.Error(), err)
+	}
+
+	// 格式化输出，转换为JSON字符串
+	jsonData, err := json.MarshalIndent(result, "", "  ")
+	if err != nil {
+		return toolErrorResult(fmt.Sprintf("获取评论树成功，但序列化失败: %v", err), err)
 	}
 
 	return &MCPToolResult{
@@ -295,58 +860,45 @@ func (s *AppServer) handleUserProfile(ctx context.Context, args map[string]any)
 
 // handlePostComment 处理发表评论到Feed
 func (s *AppServer) handlePostComment(ctx context.Context, args map[string]interface{}) *MCPToolResult {
-	logrus.Info("MCP: 发表评论到Feed")
+	logrus.WithContext(ctx).Info("MCP: 发表评论到Feed")
 
 	// 解析参数
 	feedID, ok := args["feed_id"].(string)
 	if !ok || feedID == "" {
-		return &MCPToolResult{
-			Content: []MCPContent{{
-				Type: "text",
-				Text: "发表评论失败: 缺少feed_id参数",
-			}},
-			IsError: true,
-		}
+		return toolErrorResult("发表评论失败: 缺少feed_id参数", ErrInvalidArgs)
 	}
 
 	xsecToken, ok := args["xsec_token"].(string)
 	if !ok || xsecToken == "" {
-		return &MCPToolResult{
-			Content: []MCPContent{{
-				Type: "text",
-				Text: "发表评论失败: 缺少xsec_token参数",
-			}},
-			IsError: true,
-		}
+		return toolErrorResult("发表评论失败: 缺少xsec_token参数", ErrInvalidArgs)
 	}
 
 	content, ok := args["content"].(string)
 	if !ok || content == "" {
-		return &MCPToolResult{
-			Content: []MCPContent{{
-				Type: "text",
-				Text: "发表评论失败: 缺少content参数",
-			}},
-			IsError: true,
-		}
+		return toolErrorResult("发表评论失败: 缺少content参数", ErrInvalidArgs)
+	}
+
+	image, _ := args["image"].(string)
+
+	visible, _ := args["visible"].(bool)
+	ctx, err := withVisibleBrowser(ctx, visible)
+	if err != nil {
+		return toolErrorResult("发表评论失败: "+err.Error(), err)
 	}
 
-	logrus.Infof("MCP: 发表评论 - Feed ID: %s, 内容长度: %d", feedID, len(content))
+	logrus.WithContext(ctx).Infof("MCP: 发表评论 - Feed ID: %s, 内容长度: %d, 图片: %t", feedID, len(content), image != "")
 
 	// 发表评论
-	result, err := s.xiaohongshuService.PostCommentToFeed(ctx, feedID, xsecToken, content)
+	result, err := s.xiaohongshuService.PostCommentToFeed(ctx, feedID, xsecToken, content, image)
 	if err != nil {
-		return &MCPToolResult{
-			Content: []MCPContent{{
-				Type: "text",
-				Text: "发表评论失败: " + err.Error(),
-			}},
-			IsError: true,
-		}
+		return toolErrorResult("发表评论失败: "+err.Error(), err)
 	}
 
-	// 返回成功结果，只包含feed_id
+	// 返回成功结果，只包含feed_id和警告
 	resultText := fmt.Sprintf("评论发表成功 - Feed ID: %s", result.FeedID)
+	for _, warning := range result.Warnings {
+		resultText += "\n警告: " + warning
+	}
 	return &MCPToolResult{
 		Content: []MCPContent{{
 			Type: "text",
@@ -354,3 +906,104 @@ func (s *AppServer) handlePostComment(ctx context.Context, args map[string]inter
 		}},
 	}
 }
+
+// handlePostComments 处理批量发表评论
+func (s *AppServer) handlePostComments(ctx context.Context, args map[string]interface{}) *MCPToolResult {
+	logrus.WithContext(ctx).Info("MCP: 批量发表评论")
+
+	// 解析参数
+	commentsInterface, _ := args["comments"].([]interface{})
+	if len(commentsInterface) == 0 {
+		return toolErrorResult("批量发表评论失败: 缺少comments参数", ErrInvalidArgs)
+	}
+
+	var comments []PostCommentRequest
+	for i, item := range commentsInterface {
+		itemMap, _ := item.(map[string]interface{})
+		feedID, _ := itemMap["feed_id"].(string)
+		xsecToken, _ := itemMap["xsec_token"].(string)
+		content, _ := itemMap["content"].(string)
+
+		if feedID == "" || xsecToken == "" || content == "" {
+			return toolErrorResult(fmt.Sprintf("批量发表评论失败: 第%d条评论缺少feed_id、xsec_token或content参数", i+1), ErrInvalidArgs)
+		}
+
+		comments = append(comments, PostCommentRequest{
+			FeedID:    feedID,
+			XsecToken: xsecToken,
+			Content:   content,
+		})
+	}
+
+	delay := defaultCommentDelay
+	if delaySeconds, ok := args["delay_seconds"].(float64); ok {
+		delay = time.Duration(delaySeconds * float64(time.Second))
+	}
+
+	visible, _ := args["visible"].(bool)
+	ctx, err := withVisibleBrowser(ctx, visible)
+	if err != nil {
+		return toolErrorResult("批量发表评论失败: "+err.Error(), err)
+	}
+
+	logrus.WithContext(ctx).Infof("MCP: 批量发表评论 - 数量: %d, 间隔: %s", len(comments), delay)
+
+	result, err := s.xiaohongshuService.PostCommentsBatch(ctx, comments, delay)
+	if err != nil {
+		// 中途被取消或超时，同时返回已发表和未尝试的评论
+		if result != nil {
+			jsonData, _ := json.MarshalIndent(result, "", "  ")
+			return toolErrorResult("批量发表评论中断: "+err.Error()+"\n"+string(jsonData), err)
+		}
+		return toolErrorResult("批量发表评论失败: "+err.Error(), err)
+	}
+
+	// 格式化输出，转换为JSON字符串
+	jsonData, err := json.MarshalIndent(result, "", "  ")
+	if err != nil {
+		return toolErrorResult(fmt.Sprintf("批量发表评论完成，但序列化失败: %v", err), err)
+	}
+
+	return &MCPToolResult{
+		Content: []MCPContent{{
+			Type: "text",
+			Text: string(jsonData),
+		}},
+	}
+}
+
+// handleDebugPageHTML 处理调试页面，返回渲染后的 HTML 或截图 data URL
+func (s *AppServer) handleDebugPageHTML(ctx context.Context, args map[string]any) *MCPToolResult {
+	logrus.WithContext(ctx).Info("MCP: 调试页面")
+
+	// 解析参数
+	pageURL, ok := args["url"].(string)
+	if !ok || pageURL == "" {
+		return toolErrorResult("调试页面失败: 缺少url参数", ErrInvalidArgs)
+	}
+
+	format, _ := args["format"].(string)
+	if format != "" && format != "html" && format != "screenshot" {
+		return toolErrorResult("调试页面失败: format 只支持 html 或 screenshot", ErrInvalidArgs)
+	}
+
+	logrus.WithContext(ctx).Infof("MCP: 调试页面 - URL: %s, 格式: %s", pageURL, format)
+
+	result, err := s.xiaohongshuService.DebugPage(ctx, pageURL, format == "screenshot")
+	if err != nil {
+		return toolErrorResult("调试页面失败: "+err.Error(), err)
+	}
+
+	// 格式化输出，转换为JSON字符串
+	jsonData, err := json.MarshalIndent(result, "", "  ")
+	if err != nil {
+		return toolErrorResult(fmt.Sprintf("调试页面成功，但序列化失败: %v", err), err)
+	}
+
+	return &MCPToolResult{
+		Content: []MCPContent{{
+			Type: "text",
+			Text: string(jsonData),
+		}},
+	}
+}
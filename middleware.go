@@ -2,15 +2,31 @@ package main
 
 import (
 	"net/http"
+	"slices"
 
 	"github.com/gin-gonic/gin"
 	"github.com/sirupsen/logrus"
+	"github.com/xpzouying/xiaohongshu-mcp/configs"
+	"github.com/xpzouying/xiaohongshu-mcp/xiaohongshu"
 )
 
+// opIDHeader 响应头中返回本次请求的操作ID，排查问题时用它在日志中查找
+const opIDHeader = "X-Op-Id"
+
+// opIDMiddleware 为每个请求生成操作ID，放入请求的 context 并通过响应头返回
+func opIDMiddleware() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		ctx := xiaohongshu.WithOpID(c.Request.Context())
+		c.Request = c.Request.WithContext(ctx)
+		c.Header(opIDHeader, xiaohongshu.OpIDFrom(ctx))
+		c.Next()
+	}
+}
+
 // corsMiddleware CORS 中间件
 func corsMiddleware() gin.HandlerFunc {
 	return func(c *gin.Context) {
-		c.Header("Access-Control-Allow-Origin", "*")
+		setAllowOrigin(c.Writer.Header(), c.GetHeader("Origin"))
 		c.Header("Access-Control-Allow-Methods", "GET, POST, PUT, DELETE, OPTIONS")
 		c.Header("Access-Control-Allow-Headers", "Content-Type, Authorization")
 
@@ -23,10 +39,41 @@ func corsMiddleware() gin.HandlerFunc {
 	}
 }
 
+// setAllowOrigin 按 -cors-origins 设置 Access-Control-Allow-Origin，gin 中间件和 MCP 端点共用。
+// 未配置时允许任意来源；配置后只回显白名单中的 Origin，不在白名单中时不设置该头
+func setAllowOrigin(header http.Header, origin string) {
+	origins := configs.GetCORSOrigins()
+	if len(origins) == 0 {
+		header.Set("Access-Control-Allow-Origin", "*")
+		return
+	}
+
+	header.Add("Vary", "Origin")
+	if isOriginAllowed(origin) {
+		header.Set("Access-Control-Allow-Origin", origin)
+	}
+}
+
+// isOriginAllowed 判断来源是否允许跨域访问
+func isOriginAllowed(origin string) bool {
+	origins := configs.GetCORSOrigins()
+	return len(origins) == 0 || slices.Contains(origins, origin)
+}
+
+// bodyLimitMiddleware 限制请求体大小，超出 -max-body-bytes 时读取请求体返回 *http.MaxBytesError
+func bodyLimitMiddleware() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if c.Request.Body != nil {
+			c.Request.Body = http.MaxBytesReader(c.Writer, c.Request.Body, configs.GetMaxBodyBytes())
+		}
+		c.Next()
+	}
+}
+
 // errorHandlingMiddleware 错误处理中间件
 func errorHandlingMiddleware() gin.HandlerFunc {
 	return gin.CustomRecovery(func(c *gin.Context, recovered any) {
-		logrus.Errorf("服务器内部错误: %v, path: %s", recovered, c.Request.URL.Path)
+		logrus.WithContext(c.Request.Context()).Errorf("服务器内部错误: %v, path: %s", recovered, c.Request.URL.Path)
 
 		respondError(c, http.StatusInternalServerError, "INTERNAL_ERROR",
 			"服务器内部错误", recovered)
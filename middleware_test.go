@@ -0,0 +1,41 @@
+package main
+
+import (
+	"encoding/json"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/xpzouying/xiaohongshu-mcp/configs"
+)
+
+func TestBodyLimit(t *testing.T) {
+	configs.SetMaxBodyBytes(1024)
+	defer configs.SetMaxBodyBytes(0)
+
+	router, _ := newTestRouter(t)
+	oversized := `{"feed_id": "` + strings.Repeat("a", 2048) + `", "xsec_token": "t"}`
+
+	t.Run("rest", func(t *testing.T) {
+		response := decodeError(t, serve(t, router, http.MethodPost, "/api/v1/feeds/detail", oversized), http.StatusRequestEntityTooLarge)
+		assert.Equal(t, "REQUEST_TOO_LARGE", response.Code)
+
+		// 未超出上限的请求不受影响
+		var result FeedDetailResponse
+		decodeSuccess(t, serve(t, router, http.MethodPost, "/api/v1/feeds/detail", `{"feed_id": "1", "xsec_token": "t"}`), &result)
+	})
+
+	t.Run("mcp", func(t *testing.T) {
+		body := `{"jsonrpc": "2.0", "id": 1, "method": "tools/call", "params": {"name": "get_feed_detail", "arguments": ` + oversized + `}}`
+		w := serve(t, router, http.MethodPost, "/mcp", body)
+		require.Equal(t, http.StatusRequestEntityTooLarge, w.Code, w.Body.String())
+
+		var response JSONRPCResponse
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+		require.NotNil(t, response.Error)
+		assert.Equal(t, -32700, response.Error.Code)
+		assert.Contains(t, response.Error.Message, "1024")
+	})
+}
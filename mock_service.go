@@ -0,0 +1,554 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"embed"
+	"encoding/json"
+	"fmt"
+	"image"
+	"image/color"
+	"image/draw"
+	"image/png"
+	"net/url"
+	"path"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	"github.com/xpzouying/xiaohongshu-mcp/configs"
+	"github.com/xpzouying/xiaohongshu-mcp/xiaohongshu"
+)
+
+// Mock 模式
+//
+// -mock 启动时不启动浏览器、不需要登录，所有接口返回 mockdata 目录中的固定数据，
+// 供下游在 CI 中对接 REST/MCP 接口。参数校验与真实服务一致，写操作只返回成功结果，不会真正发布。
+
+//go:embed mockdata/*.json
+var mockFixtures embed.FS
+
+// mockService 返回固定数据的 XHSService 实现
+type mockService struct {
+	feeds      []xiaohongshu.Feed
+	feedDetail json.RawMessage
+	profile    UserProfileResponse
+	topics     []xiaohongshu.Topic
+
+	notifications []xiaohongshu.Notification
+	analytics     map[string]string
+	collections   []xiaohongshu.UserCollection
+
+	published atomic.Int64 // 已发布的笔记数，用于生成递增的笔记ID
+}
+
+// newMockService 加载内置的 mock 数据
+func newMockService() (*mockService, error) {
+	s := &mockService{}
+	for name, target := range map[string]any{
+		"feeds.json":       &s.feeds,
+		"feed_detail.json": &s.feedDetail,
+		"profile.json":     &s.profile,
+		"topics.json":      &s.topics,
+
+		"notifications.json":    &s.notifications,
+		"feed_analytics.json":   &s.analytics,
+		"user_collections.json": &s.collections,
+	} {
+		data, err := mockFixtures.ReadFile("mockdata/" + name)
+		if err != nil {
+			return nil, fmt.Errorf("读取 mock 数据 %s 失败: %w", name, err)
+		}
+		if err := json.Unmarshal(data, target); err != nil {
+			return nil, fmt.Errorf("解析 mock 数据 %s 失败: %w", name, err)
+		}
+	}
+
+	return s, nil
+}
+
+// CheckLoginStatus 始终返回已登录，账号为 mock 主页的用户
+func (s *mockService) CheckLoginStatus(_ context.Context) (*LoginStatusResponse, error) {
+	return &LoginStatusResponse{
+		IsLoggedIn: true,
+		Username:   "mock",
+		UserID:     s.profile.UserID,
+		Nickname:   s.profile.UserBasicInfo.Nickname,
+		Avatar:     s.profile.UserBasicInfo.Images,
+	}, nil
+}
+
+// CheckAllLogins mock 模式只有一个 mock 账号
+func (s *mockService) CheckAllLogins(ctx context.Context) (*AllLoginsResponse, error) {
+	return checkLogins(ctx, []string{"mock"}, 1, func(ctx context.Context, _ string) (*LoginStatusResponse, error) {
+		return s.CheckLoginStatus(ctx)
+	}), nil
+}
+
+// Logout 直接返回退出成功，mock 模式没有需要删除的登录会话
+func (s *mockService) Logout(_ context.Context) (*LogoutResponse, error) {
+	return &LogoutResponse{
+		Username:       "mock",
+		SessionCleared: true,
+		Message:        "已退出登录，登录会话已删除",
+	}, nil
+}
+
+// PublishContent 校验发布参数后直接返回成功
+func (s *mockService) PublishContent(_ context.Context, req *PublishRequest) (*PublishResponse, error) {
+	if _, err := validatePublishRequest(req); err != nil {
+		return nil, err
+	}
+	visibility, _ := xiaohongshu.ParseVisibility(req.Visibility)
+
+	response := &PublishResponse{
+		Title:      req.Title,
+		Content:    req.Content,
+		Images:     len(req.Images),
+		Status:     "发布完成",
+		PostID:     fmt.Sprintf("mock%020d", s.published.Add(1)),
+		Warnings:   imageAltsWarnings(req.ImageAlts),
+		Visibility: string(visibility),
+	}
+
+	// mock 发布总是成功，确认结果直接指向刚生成的笔记
+	if configs.IsPublishVerify() {
+		response.Verification = &PublishVerification{
+			Landed:      true,
+			FeedID:      response.PostID,
+			PublishedAt: time.Now().Truncate(time.Minute).Format(time.RFC3339),
+		}
+	}
+
+	return response, nil
+}
+
+// EditFeed 校验编辑参数后返回笔记详情
+func (s *mockService) EditFeed(ctx context.Context, feedID, xsecToken string, updates EditFeedRequest) (*FeedDetailResponse, error) {
+	if _, err := validateEditFeedRequest(&updates); err != nil {
+		return nil, err
+	}
+	return s.GetFeedDetail(ctx, feedID, xsecToken)
+}
+
+// SetFeedVisibility 校验可见范围后直接返回成功，不会真正修改笔记
+func (s *mockService) SetFeedVisibility(_ context.Context, feedID, _, visibility string) (*FeedVisibilityResponse, error) {
+	v, err := parseFeedVisibility(visibility)
+	if err != nil {
+		return nil, err
+	}
+	return newFeedVisibilityResponse(feedID, v), nil
+}
+
+// ListFeeds 返回固定的推荐列表
+func (s *mockService) ListFeeds(_ context.Context) (*FeedsListResponse, error) {
+	return newFeedsListResponse("list_feeds", s.feeds), nil
+}
+
+// ExploreFeeds 返回 mock Feeds 列表的前 limit 篇作为发现页推荐，所有频道相同
+func (s *mockService) ExploreFeeds(_ context.Context, channel string, limit int) (*ExploreFeedsResponse, error) {
+	channelID, err := exploreChannelID(channel)
+	if err != nil {
+		return nil, err
+	}
+	if limit <= 0 {
+		limit = xiaohongshu.DefaultExploreFeedsLimit
+	}
+	limit = min(limit, xiaohongshu.MaxExploreFeedsLimit, len(s.feeds))
+
+	return newExploreFeedsResponse(channelID, s.feeds[:limit]), nil
+}
+
+// ListFeedsPage 分页返回固定的推荐列表
+func (s *mockService) ListFeedsPage(ctx context.Context, query ListFeedsQuery) (*FeedsListResponse, error) {
+	return listFeedsPage(ctx, s.ListFeeds, query, xiaohongshu.FeedsCursorScope(query.NoteType))
+}
+
+// SearchFeeds 返回标题包含关键词的笔记
+func (s *mockService) SearchFeeds(_ context.Context, keyword string) (*FeedsListResponse, error) {
+	feeds := make([]xiaohongshu.Feed, 0, len(s.feeds))
+	for _, feed := range s.feeds {
+		if strings.Contains(feed.NoteCard.DisplayTitle, keyword) {
+			feeds = append(feeds, feed)
+		}
+	}
+
+	return newFeedsListResponse("search_feeds", feeds), nil
+}
+
+// SearchTopics 返回名称包含关键词的话题
+func (s *mockService) SearchTopics(_ context.Context, keyword string) (*TopicsResponse, error) {
+	keyword = strings.TrimPrefix(strings.TrimSpace(keyword), "#")
+
+	topics := make([]xiaohongshu.Topic, 0, len(s.topics))
+	for _, topic := range s.topics {
+		if strings.Contains(topic.Name, keyword) {
+			topics = append(topics, topic)
+		}
+	}
+
+	return &TopicsResponse{
+		Topics:   topics,
+		Count:    len(topics),
+		Warnings: xiaohongshu.TopicWarnings(topics),
+	}, nil
+}
+
+// TrendingTopics 返回固定的热门话题
+func (s *mockService) TrendingTopics(_ context.Context) (*TopicsResponse, error) {
+	return &TopicsResponse{
+		Topics:   s.topics,
+		Count:    len(s.topics),
+		Warnings: xiaohongshu.TopicWarnings(s.topics),
+	}, nil
+}
+
+// GetFeedDetail 任意笔记都返回同一份详情数据
+func (s *mockService) GetFeedDetail(_ context.Context, feedID, _ string) (*FeedDetailResponse, error) {
+	return &FeedDetailResponse{
+		FeedID:   feedID,
+		Data:     s.feedDetail,
+		Warnings: xiaohongshu.FeedDetailWarnings(s.feedDetail),
+	}, nil
+}
+
+// SearchAndDetail 搜索后为前 limit 篇笔记返回固定的详情数据
+func (s *mockService) SearchAndDetail(ctx context.Context, keyword string, limit int) (*SearchDetailResponse, error) {
+	search, err := s.SearchFeeds(ctx, keyword)
+	if err != nil {
+		return nil, err
+	}
+
+	return collectFeedDetails(ctx, keyword, search, limit, func(feed xiaohongshu.Feed) (*FeedDetailResponse, error) {
+		return s.GetFeedDetail(ctx, feed.ID, feed.XsecToken)
+	})
+}
+
+// GetFeedByURL 解析链接后返回笔记详情，短链同样需要联网解析
+func (s *mockService) GetFeedByURL(ctx context.Context, rawURL string) (*FeedDetailResponse, error) {
+	feedID, xsecToken, err := xiaohongshu.ResolveFeedURL(ctx, rawURL)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.GetFeedDetail(ctx, feedID, xsecToken)
+}
+
+// ListComments 分页返回 mock 笔记详情中的评论，任意笔记都返回同一组评论
+func (s *mockService) ListComments(_ context.Context, feedID, _ string, limit int, cursor string) (*CommentsResponse, error) {
+	var detail struct {
+		Comments struct {
+			List []xiaohongshu.FeedComment `json:"list"`
+		} `json:"comments"`
+	}
+	if err := json.Unmarshal(s.feedDetail, &detail); err != nil {
+		return nil, fmt.Errorf("解析 mock 评论失败: %w", err)
+	}
+	comments := detail.Comments.List
+
+	scope := xiaohongshu.CommentsCursorScope(feedID)
+	pageCursor, err := xiaohongshu.DecodeCursor(cursor, scope)
+	if err != nil {
+		return nil, err
+	}
+	ids := make([]string, len(comments))
+	for i, comment := range comments {
+		ids[i] = comment.ID
+	}
+	offset := min(pageCursor.ResumeOffset(ids), len(comments))
+	if limit <= 0 {
+		limit = xiaohongshu.DefaultCommentsLimit
+	}
+
+	end := min(offset+min(limit, xiaohongshu.MaxCommentsLimit), len(comments))
+	next := ""
+	if end < len(comments) {
+		next = xiaohongshu.NextCursor(scope, end, comments[end-1].ID)
+	}
+
+	return &CommentsResponse{
+		FeedID:     feedID,
+		Comments:   comments[offset:end],
+		Count:      end - offset,
+		NextCursor: next,
+	}, nil
+}
+
+// GetCommentTree 与 ListComments 相同方式分页，并把 mock 评论的回复组织成评论树
+func (s *mockService) GetCommentTree(ctx context.Context, feedID, xsecToken string, limit, repliesLimit int, cursor string) (*CommentTreeResponse, error) {
+	page, err := s.ListComments(ctx, feedID, xsecToken, limit, cursor)
+	if err != nil {
+		return nil, err
+	}
+
+	var detail struct {
+		Comments struct {
+			List []xiaohongshu.CommentThread `json:"list"`
+		} `json:"comments"`
+	}
+	if err := json.Unmarshal(s.feedDetail, &detail); err != nil {
+		return nil, fmt.Errorf("解析 mock 评论失败: %w", err)
+	}
+	threads := make(map[string]xiaohongshu.CommentThread, len(detail.Comments.List))
+	for _, thread := range detail.Comments.List {
+		threads[thread.ID] = thread
+	}
+
+	if repliesLimit <= 0 {
+		repliesLimit = xiaohongshu.DefaultCommentRepliesLimit
+	}
+	repliesLimit = min(repliesLimit, xiaohongshu.MaxCommentRepliesLimit)
+
+	comments := make([]xiaohongshu.CommentNode, 0, len(page.Comments))
+	for _, comment := range page.Comments {
+		comments = append(comments, xiaohongshu.NewCommentNode(threads[comment.ID], repliesLimit))
+	}
+
+	return &CommentTreeResponse{
+		FeedID:     feedID,
+		Comments:   comments,
+		Count:      len(comments),
+		NextCursor: page.NextCursor,
+	}, nil
+}
+
+// GetFeedLink 返回笔记链接，short 为 true 时附带固定格式的分享短链
+func (s *mockService) GetFeedLink(_ context.Context, feedID, xsecToken string, short bool) (*FeedLinkResponse, error) {
+	response := newFeedLinkResponse(feedID, xsecToken)
+	if short {
+		response.ShortURL = "http://xhslink.com/m/" + response.FeedID
+	}
+	return response, nil
+}
+
+// mockRelatedFeeds mock 相关推荐返回的笔记数
+const mockRelatedFeeds = 6
+
+// RelatedFeeds 返回 mock Feeds 列表中除该笔记以外的前几篇作为相关推荐
+func (s *mockService) RelatedFeeds(_ context.Context, feedID, _ string) (*RelatedFeedsResponse, error) {
+	var feeds []xiaohongshu.Feed
+	for _, feed := range s.feeds {
+		if feed.ID != feedID && len(feeds) < mockRelatedFeeds {
+			feeds = append(feeds, feed)
+		}
+	}
+
+	return newRelatedFeedsResponse(feedID, feeds), nil
+}
+
+// mockScreenshotHeight mock 截图的高度
+const mockScreenshotHeight = 600
+
+// ScreenshotFeed 返回一张纯色的 PNG 图片作为 mock 截图
+func (s *mockService) ScreenshotFeed(_ context.Context, feedID, _ string, width int, scope string) (*ScreenshotFeedResponse, error) {
+	if width == 0 {
+		width = configs.GetViewport().Width
+	}
+	if scope == "" {
+		scope = xiaohongshu.ScreenshotScopeNote
+	}
+
+	img := image.NewRGBA(image.Rect(0, 0, width, mockScreenshotHeight))
+	draw.Draw(img, img.Bounds(), image.NewUniform(color.RGBA{R: 0xff, G: 0x24, B: 0x42, A: 0xff}), image.Point{}, draw.Src)
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, img); err != nil {
+		return nil, fmt.Errorf("生成 mock 截图失败: %w", err)
+	}
+
+	return newScreenshotFeedResponse(feedID, scope, &xiaohongshu.FeedScreenshot{
+		PNG:    buf.Bytes(),
+		Width:  width,
+		Height: mockScreenshotHeight,
+	}), nil
+}
+
+// GetFeedTags 返回 mock 笔记详情中的话题标签
+func (s *mockService) GetFeedTags(_ context.Context, feedID, _ string) (*FeedTagsResponse, error) {
+	var detail struct {
+		Note struct {
+			TagList []struct {
+				ID   string `json:"id"`
+				Name string `json:"name"`
+			} `json:"tagList"`
+		} `json:"note"`
+	}
+	if err := json.Unmarshal(s.feedDetail, &detail); err != nil {
+		return nil, fmt.Errorf("解析 mock 笔记详情失败: %w", err)
+	}
+
+	tags := make([]xiaohongshu.FeedTag, 0, len(detail.Note.TagList))
+	for _, t := range detail.Note.TagList {
+		tags = append(tags, xiaohongshu.NewFeedTag(t.ID, t.Name))
+	}
+
+	return &FeedTagsResponse{
+		FeedID: feedID,
+		Tags:   tags,
+		Count:  len(tags),
+	}, nil
+}
+
+// FeedAnalytics mock 主页中的笔记返回固定的笔记数据，其他笔记返回 ErrNotNoteOwner
+func (s *mockService) FeedAnalytics(ctx context.Context, feedID string) (*FeedAnalyticsResponse, error) {
+	if owned, _ := s.IsOwnNote(ctx, feedID, ""); !owned {
+		return nil, fmt.Errorf("%w: 创作者中心没有笔记 %s 的数据", xiaohongshu.ErrNotNoteOwner, feedID)
+	}
+
+	return &FeedAnalyticsResponse{
+		FeedID:        feedID,
+		FeedAnalytics: xiaohongshu.NewFeedAnalytics(s.analytics),
+	}, nil
+}
+
+// IsOwnNote mock 主页中的笔记视为当前账号的笔记
+func (s *mockService) IsOwnNote(_ context.Context, feedID, _ string) (bool, error) {
+	for _, feed := range s.profile.Feeds {
+		if feed.ID == feedID {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// UserProfile 任意用户都返回同一份主页数据
+func (s *mockService) UserProfile(_ context.Context, userID, _ string) (*UserProfileResponse, error) {
+	profile := s.profile
+	profile.UserID = userID
+	profile.Warnings = xiaohongshu.ProfileWarnings(s.profileData())
+
+	return &profile, nil
+}
+
+// UserProfileByURL 从链接中取出 user_id 后返回主页数据
+func (s *mockService) UserProfileByURL(ctx context.Context, profileURL string) (*UserProfileResponse, error) {
+	u, err := url.Parse(profileURL)
+	if err != nil || !strings.HasPrefix(u.Path, "/user/profile/") {
+		return nil, fmt.Errorf("无效的用户主页链接: %s", profileURL)
+	}
+
+	return s.UserProfile(ctx, path.Base(u.Path), "")
+}
+
+// UserFeeds 分页返回 mock 主页中的笔记
+func (s *mockService) UserFeeds(ctx context.Context, userID, _ string, limit int, cursor string) (*UserFeedsResponse, error) {
+	if limit <= 0 {
+		limit = xiaohongshu.DefaultUserFeedsLimit
+	}
+
+	listFeeds := func(context.Context) (*FeedsListResponse, error) {
+		return &FeedsListResponse{Feeds: s.profile.Feeds}, nil
+	}
+	page, err := listFeedsPage(ctx, listFeeds, ListFeedsQuery{
+		Limit:  min(limit, xiaohongshu.MaxUserFeedsLimit),
+		Cursor: cursor,
+	}, xiaohongshu.UserFeedsCursorScope(userID))
+	if err != nil {
+		return nil, err
+	}
+
+	return &UserFeedsResponse{
+		UserID:     userID,
+		Feeds:      page.Feeds,
+		Count:      page.Count,
+		NextCursor: page.NextCursor,
+		Warnings:   page.Warnings,
+	}, nil
+}
+
+// UserCollections 任意用户都返回同一份收藏专辑
+func (s *mockService) UserCollections(_ context.Context, userID, _ string) (*UserCollectionsResponse, error) {
+	return &UserCollectionsResponse{
+		UserID:      userID,
+		Collections: s.collections,
+		Count:       len(s.collections),
+	}, nil
+}
+
+// MyProfile 返回 mock 主页及其互动数据汇总
+func (s *mockService) MyProfile(_ context.Context) (*MyProfileResponse, error) {
+	profile := s.profile
+	profile.Warnings = xiaohongshu.ProfileWarnings(s.profileData())
+
+	return &MyProfileResponse{
+		UserProfileResponse: profile,
+		Totals:              xiaohongshu.SummarizeProfile(s.profileData()),
+	}, nil
+}
+
+// ListNotifications 返回固定的通知，可按类别过滤
+func (s *mockService) ListNotifications(_ context.Context, category string) (*NotificationsResponse, error) {
+	if !xiaohongshu.ValidNotificationCategory(category) {
+		return nil, fmt.Errorf("不支持的通知类别: %s，只支持 likes、comments、mentions、follows", category)
+	}
+
+	notifications := make([]xiaohongshu.Notification, 0, len(s.notifications))
+	for _, notification := range s.notifications {
+		if category == "" || notification.Category == category {
+			notifications = append(notifications, notification)
+		}
+	}
+
+	return &NotificationsResponse{
+		Category:      category,
+		Notifications: notifications,
+		Count:         len(notifications),
+	}, nil
+}
+
+// PostCommentToFeed 直接返回评论成功
+func (s *mockService) PostCommentToFeed(_ context.Context, feedID, _, _, _ string) (*PostCommentResponse, error) {
+	return &PostCommentResponse{
+		FeedID:  feedID,
+		Success: true,
+		Message: "评论发表成功",
+	}, nil
+}
+
+// PostCommentsBatch 所有评论都返回成功，不等待评论间隔
+func (s *mockService) PostCommentsBatch(_ context.Context, comments []PostCommentRequest, delay time.Duration) (*PostCommentsResponse, error) {
+	if err := checkBatchComments(comments, delay); err != nil {
+		return nil, err
+	}
+
+	response := &PostCommentsResponse{
+		Results: make([]PostCommentResult, 0, len(comments)),
+	}
+	for _, comment := range comments {
+		response.Results = append(response.Results, PostCommentResult{
+			FeedID:  comment.FeedID,
+			Success: true,
+		})
+		response.Succeeded++
+	}
+
+	return response, nil
+}
+
+// DebugPage mock 模式下没有浏览器，不支持调试页面
+func (s *mockService) DebugPage(_ context.Context, _ string, _ bool) (*DebugPageResponse, error) {
+	return nil, fmt.Errorf("mock 模式不支持 debug_page_html")
+}
+
+// WriteQueueDepth mock 模式下写操作不排队
+func (s *mockService) WriteQueueDepth() map[string]int {
+	return map[string]int{}
+}
+
+// CacheStats mock 模式不缓存
+func (s *mockService) CacheStats() map[string]cacheStats {
+	return map[string]cacheStats{}
+}
+
+// BrowserStats mock 模式不启动浏览器
+func (s *mockService) BrowserStats() browserStats {
+	return browserStats{}
+}
+
+// profileData 转换为 xiaohongshu 包的主页数据，用于复用告警和汇总逻辑
+func (s *mockService) profileData() *xiaohongshu.UserProfileResponse {
+	return &xiaohongshu.UserProfileResponse{
+		UserBasicInfo: s.profile.UserBasicInfo,
+		Interactions:  s.profile.Interactions,
+		Feeds:         s.profile.Feeds,
+	}
+}
@@ -0,0 +1,155 @@
+package main
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/sirupsen/logrus"
+)
+
+// 服务端通知
+//
+// 已打开的 SSE（GET /mcp）和 WebSocket（/mcp/ws）连接会订阅通知，
+// 登录状态变化导致可用工具变化时，推送 notifications/tools/list_changed；
+// 之前已登录、之后检测到未登录时，额外推送 notifications/login_expired，提示用户重新登录。
+// 有连接订阅时，后台按 -login-check-interval 定期检查登录状态；
+// 开启 -session-keepalive 时，无论是否有连接都会定期检查，见 keepSessionAlive。
+
+// notificationBufferSize 每个订阅者的通知缓冲长度，缓冲满时丢弃新通知
+const notificationBufferSize = 8
+
+// notificationHub 向所有订阅的连接广播 JSON-RPC 通知
+type notificationHub struct {
+	mu          sync.Mutex
+	subscribers map[chan *JSONRPCNotification]struct{}
+}
+
+// newNotificationHub 创建通知中心
+func newNotificationHub() *notificationHub {
+	return &notificationHub{
+		subscribers: make(map[chan *JSONRPCNotification]struct{}),
+	}
+}
+
+// Subscribe 订阅通知，连接关闭时需调用 Unsubscribe
+func (h *notificationHub) Subscribe() chan *JSONRPCNotification {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	ch := make(chan *JSONRPCNotification, notificationBufferSize)
+	h.subscribers[ch] = struct{}{}
+
+	return ch
+}
+
+// Unsubscribe 取消订阅并关闭通道
+func (h *notificationHub) Unsubscribe(ch chan *JSONRPCNotification) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	if _, ok := h.subscribers[ch]; ok {
+		delete(h.subscribers, ch)
+		close(ch)
+	}
+}
+
+// Len 返回当前订阅者数量
+func (h *notificationHub) Len() int {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	return len(h.subscribers)
+}
+
+// Broadcast 向所有订阅者发送通知，不会因为慢连接而阻塞
+func (h *notificationHub) Broadcast(method string) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	notification := &JSONRPCNotification{
+		JSONRPC: "2.0",
+		Method:  method,
+	}
+
+	for ch := range h.subscribers {
+		select {
+		case ch <- notification:
+		default:
+			logrus.Warnf("通知缓冲已满，丢弃通知: %s", method)
+		}
+	}
+}
+
+// loginState 缓存最近一次检测到的登录状态，用于过滤工具列表
+type loginState struct {
+	mu       sync.RWMutex
+	known    bool
+	loggedIn bool
+}
+
+// Get 返回登录状态，known 为 false 表示还没有检测过
+func (l *loginState) Get() (loggedIn, known bool) {
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+
+	return l.loggedIn, l.known
+}
+
+// Set 更新登录状态，返回可用工具是否因此发生变化
+func (l *loginState) Set(loggedIn bool) bool {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	// 未检测过时默认展示全部工具，等同于已登录
+	previous := l.loggedIn || !l.known
+	l.loggedIn, l.known = loggedIn, true
+
+	return previous != loggedIn
+}
+
+// updateLoginState 记录登录状态和当前账号，状态变化时通知客户端刷新工具列表，登录过期时通知客户端重新登录
+func (s *AppServer) updateLoginState(status *LoginStatusResponse) {
+	s.account.Update(status)
+
+	loggedIn := status.IsLoggedIn
+	wasLoggedIn, known := s.loginState.Get()
+
+	if s.loginState.Set(loggedIn) {
+		logrus.Infof("登录状态变化: %v，通知客户端刷新工具列表", loggedIn)
+		s.notifications.Broadcast("notifications/tools/list_changed")
+	}
+
+	if known && wasLoggedIn && !loggedIn {
+		logrus.Warn("登录已过期，通知客户端重新登录")
+		s.notifications.Broadcast("notifications/login_expired")
+	}
+}
+
+// monitorLogin 定期检查登录状态，直到 ctx 结束。没有连接订阅通知时跳过检查，避免无意义地启动浏览器
+func (s *AppServer) monitorLogin(ctx context.Context, interval time.Duration) {
+	if interval <= 0 {
+		return
+	}
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if s.notifications.Len() == 0 {
+				continue
+			}
+
+			status, err := s.xiaohongshuService.CheckLoginStatus(ctx)
+			if err != nil {
+				logrus.Warnf("后台检查登录状态失败: %v", err)
+				continue
+			}
+			s.updateLoginState(status)
+		}
+	}
+}
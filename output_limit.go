@@ -0,0 +1,167 @@
+package main
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"unicode/utf8"
+
+	"github.com/sirupsen/logrus"
+	"github.com/xpzouying/xiaohongshu-mcp/configs"
+	"github.com/xpzouying/xiaohongshu-mcp/xiaohongshu"
+)
+
+// 工具结果截断
+//
+// Feeds 列表、用户笔记等结果序列化后可能有几百 KB，直接返回会撑满模型的上下文。
+// 超过 -max-output-bytes（或 -tool-output-limits 中该工具的上限）时：
+// JSON 结果只保留其中最长的列表的前 N 项，并加上 truncated 和说明字段，count 和 next_cursor 随之调整；
+// 无法按列表截断的文本直接按字节截断，并在末尾附加说明。
+
+// limitToolResult 按配置的上限截断工具结果，错误结果不做处理。
+// 文本被截断时结构化结果同步替换为截断后的 JSON，无法按列表截断时去掉结构化结果
+func limitToolResult(tool string, result *MCPToolResult) *MCPToolResult {
+	limit := configs.GetMaxOutputBytes(tool)
+	if result == nil || result.IsError || limit <= 0 {
+		return result
+	}
+
+	for i, content := range result.Content {
+		if content.Type != "text" || len(content.Text) <= limit {
+			continue
+		}
+
+		text, ok := truncateJSONText(content.Text, limit)
+		if !ok {
+			text = truncateText(content.Text, limit)
+		}
+		logrus.Infof("工具 %s 的结果为 %d 字节，超过上限 %d 字节，已截断", tool, len(content.Text), limit)
+		result.Content[i].Text = text
+
+		if result.StructuredContent != nil {
+			if ok {
+				result.StructuredContent = json.RawMessage(text)
+			} else {
+				result.StructuredContent = nil
+			}
+		}
+	}
+
+	return result
+}
+
+// truncateJSONText 将 JSON 对象中最长的列表截短到结果不超过 limit 字节。
+// count 改为保留的项数；有 next_cursor 时退回到保留的最后一项，翻下一页从第一条被截掉的项开始，
+// 游标无法调整时去掉 next_cursor。不是 JSON 对象、没有列表或只保留一项仍超出上限时返回 false
+func truncateJSONText(text string, limit int) (string, bool) {
+	decoder := json.NewDecoder(bytes.NewReader([]byte(text)))
+	decoder.UseNumber()
+
+	var object map[string]any
+	if err := decoder.Decode(&object); err != nil {
+		return "", false
+	}
+
+	key, items := longestList(object)
+	if len(items) < 2 {
+		return "", false
+	}
+
+	cursor, _ := object["next_cursor"].(string)
+	_, hasCount := object["count"]
+
+	render := func(n int) ([]byte, error) {
+		object[key] = items[:n]
+		if hasCount {
+			object["count"] = n
+		}
+		if cursor != "" {
+			if next, ok := rewindCursor(cursor, len(items)-n, items[n-1]); ok {
+				object["next_cursor"] = next
+			} else {
+				delete(object, "next_cursor")
+			}
+		}
+		object["truncated"] = true
+		object["truncated_note"] = fmt.Sprintf("结果超过 %d 字节，%s 只保留了前 %d 项（共 %d 项）", limit, key, n, len(items))
+		return json.MarshalIndent(object, "", "  ")
+	}
+
+	// 二分查找能放下的最多项数
+	lo, hi := 0, len(items)-1
+	var best []byte
+	for lo < hi {
+		mid := (lo + hi + 1) / 2
+		data, err := render(mid)
+		if err != nil {
+			return "", false
+		}
+		if len(data) <= limit {
+			lo, best = mid, data
+		} else {
+			hi = mid - 1
+		}
+	}
+	if lo == 0 || best == nil {
+		return "", false
+	}
+
+	return string(best), true
+}
+
+// rewindCursor 将下一页的游标往回退 dropped 项，指向截断后保留的最后一项 last。
+// 游标无法解析或 last 没有 id 时返回 false
+func rewindCursor(cursor string, dropped int, last any) (string, bool) {
+	data, err := base64.RawURLEncoding.DecodeString(cursor)
+	if err != nil {
+		return "", false
+	}
+	var c xiaohongshu.Cursor
+	if err := json.Unmarshal(data, &c); err != nil || c.Offset < dropped {
+		return "", false
+	}
+
+	item, _ := last.(map[string]any)
+	id, _ := item["id"].(string)
+	if id == "" {
+		return "", false
+	}
+
+	c.Offset -= dropped
+	c.LastID = id
+	return c.Encode(), true
+}
+
+// longestList 找出 JSON 对象顶层元素最多的列表字段
+func longestList(object map[string]any) (string, []any) {
+	var (
+		bestKey   string
+		bestItems []any
+	)
+	for key, value := range object {
+		if items, ok := value.([]any); ok && len(items) > len(bestItems) {
+			bestKey, bestItems = key, items
+		}
+	}
+	return bestKey, bestItems
+}
+
+// truncateText 按字节截断文本，不截断 UTF-8 字符，并附加截断说明。
+// 上限放不下完整的说明时改用简短的标记，结果始终不超过 limit 字节
+func truncateText(text string, limit int) string {
+	note := fmt.Sprintf("\n\n[truncated: true] 结果为 %d 字节，超过上限 %d 字节，已截断", len(text), limit)
+	if len(note) > limit {
+		note = "[truncated]"
+		if len(note) > limit {
+			return note[:limit]
+		}
+	}
+
+	end := max(limit-len(note), 0)
+	for end > 0 && !utf8.RuneStart(text[end]) {
+		end--
+	}
+
+	return text[:end] + note
+}
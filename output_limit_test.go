@@ -0,0 +1,88 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/xpzouying/xiaohongshu-mcp/xiaohongshu"
+)
+
+// feedsPageJSON 构造一页包含 n 条笔记的 list_feeds 结果
+func feedsPageJSON(t *testing.T, n int, nextCursor string) string {
+	t.Helper()
+
+	feeds := make([]map[string]any, n)
+	for i := range feeds {
+		feeds[i] = map[string]any{
+			"id":    fmt.Sprintf("feed-%02d", i),
+			"title": strings.Repeat("标题", 20),
+		}
+	}
+	data, err := json.MarshalIndent(map[string]any{
+		"feeds":       feeds,
+		"count":       n,
+		"next_cursor": nextCursor,
+	}, "", "  ")
+	require.NoError(t, err)
+	return string(data)
+}
+
+func TestTruncateJSONTextCountAndCursor(t *testing.T) {
+	scope := xiaohongshu.FeedsCursorScope("")
+	text := feedsPageJSON(t, 20, xiaohongshu.NextCursor(scope, 40, "feed-19"))
+
+	truncated, ok := truncateJSONText(text, len(text)/2)
+	require.True(t, ok)
+	assert.LessOrEqual(t, len(truncated), len(text)/2)
+
+	var object struct {
+		Feeds      []map[string]any `json:"feeds"`
+		Count      int              `json:"count"`
+		NextCursor string           `json:"next_cursor"`
+		Truncated  bool             `json:"truncated"`
+	}
+	require.NoError(t, json.Unmarshal([]byte(truncated), &object))
+	require.NotEmpty(t, object.Feeds)
+	assert.Less(t, len(object.Feeds), 20)
+	assert.Equal(t, len(object.Feeds), object.Count)
+	assert.True(t, object.Truncated)
+
+	// 下一页从第一条被截掉的笔记开始
+	cursor, err := xiaohongshu.DecodeCursor(object.NextCursor, scope)
+	require.NoError(t, err)
+	last := object.Feeds[len(object.Feeds)-1]["id"]
+	assert.Equal(t, last, cursor.LastID)
+	assert.Equal(t, 40-(20-len(object.Feeds)), cursor.Offset)
+}
+
+func TestTruncateJSONTextDropsInvalidCursor(t *testing.T) {
+	text := feedsPageJSON(t, 20, "not-a-cursor")
+
+	truncated, ok := truncateJSONText(text, len(text)/2)
+	require.True(t, ok)
+
+	var object map[string]any
+	require.NoError(t, json.Unmarshal([]byte(truncated), &object))
+	assert.NotContains(t, object, "next_cursor")
+	assert.Equal(t, true, object["truncated"])
+}
+
+func TestTruncateText(t *testing.T) {
+	text := strings.Repeat("小红书", 100)
+
+	for _, limit := range []int{1, 5, 11, 50, 200} {
+		t.Run(fmt.Sprint(limit), func(t *testing.T) {
+			truncated := truncateText(text, limit)
+			assert.LessOrEqual(t, len(truncated), limit)
+			assert.True(t, strings.HasPrefix("[truncated]", truncated) || strings.Contains(truncated, "truncated"), truncated)
+		})
+	}
+
+	truncated := truncateText(text, 200)
+	assert.Contains(t, truncated, "[truncated: true]")
+	assert.True(t, strings.HasPrefix(text, strings.SplitN(truncated, "\n\n", 2)[0]))
+}
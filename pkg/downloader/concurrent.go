@@ -0,0 +1,70 @@
+package downloader
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+)
+
+// DownloadOptions 并发下载图片的参数
+type DownloadOptions struct {
+	Headers map[string]string // 每个请求都带上的请求头，可为空
+	Workers int               // 同时下载的数量，小于等于 0 时按 1 处理
+	Timeout time.Duration     // 单张图片的下载超时时间，0 表示只受 ctx 限制
+}
+
+// DownloadResult 单张图片的下载结果，Err 不为空时 Path 为空
+type DownloadResult struct {
+	URL  string
+	Path string
+	Err  error
+}
+
+// DownloadImages 并发下载图片，返回的结果与 urls 顺序一致，与完成顺序无关。
+// 单张图片失败或超时不影响其他图片，调用方负责删除下载成功的临时文件。
+func DownloadImages(ctx context.Context, urls []string, opts DownloadOptions) []DownloadResult {
+	results := make([]DownloadResult, len(urls))
+	if len(urls) == 0 {
+		return results
+	}
+
+	workers := min(max(opts.Workers, 1), len(urls))
+	jobs := make(chan int)
+
+	var wg sync.WaitGroup
+	for range workers {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range jobs {
+				results[i] = downloadOne(ctx, urls[i], opts)
+			}
+		}()
+	}
+
+	for i := range urls {
+		jobs <- i
+	}
+	close(jobs)
+	wg.Wait()
+
+	return results
+}
+
+// downloadOne 按单张超时时间下载一张图片
+func downloadOne(ctx context.Context, url string, opts DownloadOptions) DownloadResult {
+	downloadCtx := ctx
+	if opts.Timeout > 0 {
+		var cancel context.CancelFunc
+		downloadCtx, cancel = context.WithTimeout(ctx, opts.Timeout)
+		defer cancel()
+	}
+
+	path, err := DownloadImageWithHeaders(downloadCtx, url, opts.Headers)
+	if err != nil && ctx.Err() == nil && errors.Is(downloadCtx.Err(), context.DeadlineExceeded) {
+		err = fmt.Errorf("下载超时（超过 %s）: %w", opts.Timeout, err)
+	}
+	return DownloadResult{URL: url, Path: path, Err: err}
+}
@@ -0,0 +1,63 @@
+package downloader
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDownloadImages(t *testing.T) {
+	allowLoopback(t)
+
+	data := pngBytes(t)
+	release := make(chan struct{})
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/slow.png" {
+			select {
+			case <-release:
+			case <-r.Context().Done():
+				return
+			}
+		}
+		w.Write(data)
+	}))
+	t.Cleanup(server.Close)
+	t.Cleanup(func() { close(release) })
+
+	urls := []string{
+		server.URL + "/slow.png",
+		server.URL + "/fast-1.png",
+		server.URL + "/fast-2.png",
+	}
+
+	start := time.Now()
+	results := DownloadImages(context.Background(), urls, DownloadOptions{Workers: 3, Timeout: 200 * time.Millisecond})
+	assert.Less(t, time.Since(start), 2*time.Second, "慢图片应在单张超时后放弃")
+
+	require.Len(t, results, len(urls))
+	for i, result := range results {
+		assert.Equal(t, urls[i], result.URL, "结果需与输入顺序一致")
+		if result.Path != "" {
+			t.Cleanup(func() { os.Remove(result.Path) })
+		}
+	}
+
+	require.Error(t, results[0].Err)
+	assert.Contains(t, results[0].Err.Error(), "下载超时")
+	assert.Empty(t, results[0].Path)
+
+	for _, result := range results[1:] {
+		require.NoError(t, result.Err)
+		assert.FileExists(t, result.Path)
+	}
+}
+
+func TestDownloadImagesEmpty(t *testing.T) {
+	assert.Empty(t, DownloadImages(context.Background(), nil, DownloadOptions{}))
+}
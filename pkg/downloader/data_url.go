@@ -0,0 +1,80 @@
+package downloader
+
+import (
+	"encoding/base64"
+	"fmt"
+	"net/http"
+	"os"
+	"strings"
+)
+
+// MaxDataURLImageSize data URL 图片解码后的最大字节数
+const MaxDataURLImageSize = 20 << 20
+
+// dataURLImageTypes 支持的 data URL 图片类型及对应的文件扩展名
+var dataURLImageTypes = map[string]string{
+	"image/jpeg": ".jpg",
+	"image/png":  ".png",
+	"image/webp": ".webp",
+}
+
+// IsDataURL 判断是否为 data URL，如 data:image/png;base64,...
+func IsDataURL(s string) bool {
+	return strings.HasPrefix(s, "data:")
+}
+
+// SaveDataURLImage 解码 base64 编码的 data URL 图片并写入临时文件，返回文件路径。
+// 声明的 MIME 类型需与实际图片内容一致，调用方负责在使用后删除该文件。
+func SaveDataURLImage(dataURL string) (string, error) {
+	header, payload, ok := strings.Cut(strings.TrimPrefix(dataURL, "data:"), ",")
+	if !ok {
+		return "", fmt.Errorf("data URL 格式错误，缺少逗号分隔的数据部分")
+	}
+
+	mimeType, encoding, _ := strings.Cut(header, ";")
+	if encoding != "base64" {
+		return "", fmt.Errorf("data URL 只支持 base64 编码")
+	}
+
+	mimeType = strings.ToLower(mimeType)
+	ext, ok := dataURLImageTypes[mimeType]
+	if !ok {
+		return "", fmt.Errorf("不支持的图片类型: %s，只支持 JPEG、PNG、WebP", mimeType)
+	}
+
+	// 先按编码长度估算，避免解码超大数据
+	payload = strings.Join(strings.Fields(payload), "")
+	if base64.StdEncoding.DecodedLen(len(payload)) > MaxDataURLImageSize+2 {
+		return "", fmt.Errorf("图片超过大小限制 %d MB", MaxDataURLImageSize>>20)
+	}
+
+	data, err := base64.StdEncoding.DecodeString(payload)
+	if err != nil {
+		if data, err = base64.RawStdEncoding.DecodeString(payload); err != nil {
+			return "", fmt.Errorf("data URL base64 解码失败: %w", err)
+		}
+	}
+	if len(data) == 0 {
+		return "", fmt.Errorf("data URL 图片内容为空")
+	}
+	if len(data) > MaxDataURLImageSize {
+		return "", fmt.Errorf("图片超过大小限制 %d MB", MaxDataURLImageSize>>20)
+	}
+
+	if detected := http.DetectContentType(data); detected != mimeType {
+		return "", fmt.Errorf("图片内容与声明的类型不符: 声明为 %s，实际为 %s", mimeType, detected)
+	}
+
+	file, err := createTempFile("xhs-data-url-*" + ext)
+	if err != nil {
+		return "", fmt.Errorf("创建临时图片文件失败: %w", err)
+	}
+	defer file.Close()
+
+	if _, err := file.Write(data); err != nil {
+		os.Remove(file.Name())
+		return "", fmt.Errorf("写入临时图片文件失败: %w", err)
+	}
+
+	return file.Name(), nil
+}
@@ -0,0 +1,65 @@
+package downloader
+
+import (
+	"encoding/base64"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// inlinePNG 1x1 的 PNG 图片
+const inlinePNG = "iVBORw0KGgoAAAANSUhEUgAAAAEAAAABCAYAAAAfFcSJAAAADUlEQVR42mNkYPhfDwAChwGA60e6kgAAAABJRU5ErkJggg=="
+
+func TestSaveDataURLImage(t *testing.T) {
+	SetTempDir(t.TempDir())
+	defer SetTempDir("")
+
+	path, err := SaveDataURLImage("data:image/png;base64," + inlinePNG)
+	require.NoError(t, err)
+	defer os.Remove(path)
+
+	assert.True(t, strings.HasSuffix(path, ".png"), path)
+	data, err := os.ReadFile(path)
+	require.NoError(t, err)
+	want, _ := base64.StdEncoding.DecodeString(inlinePNG)
+	assert.Equal(t, want, data)
+
+	// 大写的 MIME 类型、换行分隔和缺少填充的 base64 也能解码
+	wrapped := inlinePNG[:40] + "\n" + strings.TrimRight(inlinePNG[40:], "=")
+	path, err = SaveDataURLImage("data:IMAGE/PNG;base64," + wrapped)
+	require.NoError(t, err)
+	os.Remove(path)
+}
+
+func TestSaveDataURLImageInvalid(t *testing.T) {
+	SetTempDir(t.TempDir())
+	defer SetTempDir("")
+
+	oversized := base64.StdEncoding.EncodeToString(make([]byte, MaxDataURLImageSize+1))
+
+	tests := []struct {
+		name    string
+		dataURL string
+		wantErr string
+	}{
+		{name: "missing comma", dataURL: "data:image/png;base64", wantErr: "缺少逗号"},
+		{name: "not base64 encoding", dataURL: "data:image/png," + inlinePNG, wantErr: "只支持 base64"},
+		{name: "unsupported type", dataURL: "data:image/gif;base64," + inlinePNG, wantErr: "不支持的图片类型"},
+		{name: "declared type mismatch", dataURL: "data:image/jpeg;base64," + inlinePNG, wantErr: "声明的类型不符"},
+		{name: "invalid base64", dataURL: "data:image/png;base64,!!!", wantErr: "解码失败"},
+		{name: "empty", dataURL: "data:image/png;base64,", wantErr: "内容为空"},
+		{name: "oversized", dataURL: "data:image/png;base64," + oversized, wantErr: "超过大小限制"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path, err := SaveDataURLImage(tt.dataURL)
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), tt.wantErr)
+			assert.Empty(t, path)
+		})
+	}
+}
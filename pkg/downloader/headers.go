@@ -0,0 +1,66 @@
+package downloader
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+)
+
+// MaxDownloadImageSize 下载的图片的最大字节数
+const MaxDownloadImageSize = 20 << 20
+
+// DownloadImageWithHeaders 使用指定的请求头（如 Referer、Authorization）下载图片并写入临时文件，返回文件路径。
+// headers 为空时直接下载，用于有防盗链或需要鉴权的图床。主机不允许下载时返回 ErrHostBlocked，
+// 下载在 ctx 取消或超时时中止，图片内容需为 JPEG、PNG 或 WebP，调用方负责在使用后删除该文件。
+func DownloadImageWithHeaders(ctx context.Context, imageURL string, headers map[string]string) (string, error) {
+	if err := CheckImageURL(ctx, imageURL); err != nil {
+		return "", err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, imageURL, nil)
+	if err != nil {
+		return "", fmt.Errorf("创建图片下载请求失败: %w", err)
+	}
+	for key, value := range headers {
+		req.Header.Set(key, value)
+	}
+
+	resp, err := downloadClient.Do(req)
+	if err != nil {
+		return "", fmt.Errorf("下载图片失败: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("下载图片失败: %s 返回 %s", imageURL, resp.Status)
+	}
+
+	data, err := io.ReadAll(io.LimitReader(resp.Body, MaxDownloadImageSize+1))
+	if err != nil {
+		return "", fmt.Errorf("读取图片内容失败: %w", err)
+	}
+	if len(data) > MaxDownloadImageSize {
+		return "", fmt.Errorf("图片超过大小限制 %d MB", MaxDownloadImageSize>>20)
+	}
+
+	detected := http.DetectContentType(data)
+	ext, ok := dataURLImageTypes[detected]
+	if !ok {
+		return "", fmt.Errorf("不支持的图片类型: %s，只支持 JPEG、PNG、WebP", detected)
+	}
+
+	file, err := createTempFile("xhs-download-*" + ext)
+	if err != nil {
+		return "", fmt.Errorf("创建临时图片文件失败: %w", err)
+	}
+	defer file.Close()
+
+	if _, err := file.Write(data); err != nil {
+		os.Remove(file.Name())
+		return "", fmt.Errorf("写入临时图片文件失败: %w", err)
+	}
+
+	return file.Name(), nil
+}
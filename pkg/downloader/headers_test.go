@@ -0,0 +1,96 @@
+package downloader
+
+import (
+	"bytes"
+	"context"
+	"image"
+	"image/png"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// testReferer 测试图床要求的防盗链 Referer
+const testReferer = "https://www.xiaohongshu.com/"
+
+// pngBytes 返回一张 1x1 的 PNG 图片
+func pngBytes(t *testing.T) []byte {
+	t.Helper()
+
+	var buf bytes.Buffer
+	require.NoError(t, png.Encode(&buf, image.NewRGBA(image.Rect(0, 0, 1, 1))))
+	return buf.Bytes()
+}
+
+// allowLoopback 允许下载本机的测试服务器，并把临时文件写到测试目录，测试结束后恢复默认设置
+func allowLoopback(t *testing.T) {
+	t.Helper()
+
+	require.NoError(t, SetHostPolicy(nil, nil, true))
+	SetTempDir(t.TempDir())
+	t.Cleanup(func() {
+		require.NoError(t, SetHostPolicy(nil, nil, false))
+		SetTempDir("")
+	})
+}
+
+// newRefererServer 只在请求带有 testReferer 时返回图片，否则返回 403，模拟有防盗链的图床
+func newRefererServer(t *testing.T) *httptest.Server {
+	t.Helper()
+
+	data := pngBytes(t)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Referer") != testReferer {
+			w.WriteHeader(http.StatusForbidden)
+			return
+		}
+		w.Write(data)
+	}))
+	t.Cleanup(server.Close)
+	return server
+}
+
+func TestDownloadImageWithHeaders(t *testing.T) {
+	allowLoopback(t)
+	server := newRefererServer(t)
+
+	t.Run("带 Referer 下载成功", func(t *testing.T) {
+		path, err := DownloadImageWithHeaders(context.Background(), server.URL+"/a.png", map[string]string{"Referer": testReferer})
+		require.NoError(t, err)
+		t.Cleanup(func() { os.Remove(path) })
+
+		assert.Regexp(t, `xhs-download-.*\.png$`, path)
+		data, err := os.ReadFile(path)
+		require.NoError(t, err)
+		assert.Equal(t, pngBytes(t), data)
+	})
+
+	t.Run("缺少 Referer 返回状态码错误", func(t *testing.T) {
+		path, err := DownloadImageWithHeaders(context.Background(), server.URL+"/a.png", nil)
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "403")
+		assert.Empty(t, path)
+	})
+
+	t.Run("不是图片时拒绝", func(t *testing.T) {
+		textServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.Write([]byte("<html>login</html>"))
+		}))
+		defer textServer.Close()
+
+		_, err := DownloadImageWithHeaders(context.Background(), textServer.URL, nil)
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "不支持的图片类型")
+	})
+}
+
+func TestDownloadImageWithHeadersBlocksLoopbackByDefault(t *testing.T) {
+	server := newRefererServer(t)
+
+	_, err := DownloadImageWithHeaders(context.Background(), server.URL+"/a.png", map[string]string{"Referer": testReferer})
+	assert.ErrorIs(t, err, ErrHostBlocked)
+}
@@ -0,0 +1,248 @@
+package downloader
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"net/netip"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// 图片下载的主机限制
+//
+// 服务会按请求中的 URL 下载图片，暴露给不受信任的客户端时，可能被用来访问内网服务（SSRF）。
+// 默认拒绝解析到内网、回环、链路本地等地址的 URL；可另外配置允许和拒绝的主机名或 CIDR：
+// 主机名同时匹配其子域名，如 xhscdn.com 匹配 sns-img.xhscdn.com。
+// 规则在下载前按 URL 的主机名和 DNS 解析结果检查；不经过代理时连接的就是检查过的 IP，
+// DNS 在检查之后改变解析结果也不会连到其他地址；重定向的目标同样检查。
+
+// ErrHostBlocked 图片 URL 的主机不允许下载
+var ErrHostBlocked = errors.New("图片地址不允许下载")
+
+// privatePrefixes 默认拒绝的内网地址段，补充 netip.Addr 方法没有覆盖的运营商级 NAT 和 IPv4 映射段
+var privatePrefixes = []netip.Prefix{
+	netip.MustParsePrefix("100.64.0.0/10"),
+	netip.MustParsePrefix("0.0.0.0/8"),
+	netip.MustParsePrefix("198.18.0.0/15"),
+}
+
+// hostPolicy 图片下载允许和拒绝的主机
+type hostPolicy struct {
+	allowHosts   []string
+	allowPrefix  []netip.Prefix
+	denyHosts    []string
+	denyPrefix   []netip.Prefix
+	allowPrivate bool
+}
+
+// policy 当前的下载主机限制，默认只拒绝内网地址
+var policy = &hostPolicy{}
+
+// SetHostPolicy 设置图片下载的主机限制。allow 不为空时只允许匹配的主机名或 CIDR，
+// deny 中的主机名或 CIDR 总是拒绝，allowPrivate 为 false 时拒绝内网地址（allow 中明确列出的 CIDR 除外）。
+// 规则格式错误时返回错误，应在处理请求之前设置
+func SetHostPolicy(allow, deny []string, allowPrivate bool) error {
+	p := &hostPolicy{allowPrivate: allowPrivate}
+
+	var err error
+	if p.allowHosts, p.allowPrefix, err = parseHostRules(allow); err != nil {
+		return err
+	}
+	if p.denyHosts, p.denyPrefix, err = parseHostRules(deny); err != nil {
+		return err
+	}
+
+	policy = p
+	return nil
+}
+
+// parseHostRules 将规则分为主机名和 CIDR，单个 IP 按 /32 或 /128 处理
+func parseHostRules(rules []string) ([]string, []netip.Prefix, error) {
+	var hosts []string
+	var prefixes []netip.Prefix
+	for _, rule := range rules {
+		rule = strings.ToLower(strings.TrimSpace(rule))
+		switch {
+		case rule == "":
+			continue
+		case strings.Contains(rule, "/"):
+			prefix, err := netip.ParsePrefix(rule)
+			if err != nil {
+				return nil, nil, fmt.Errorf("无效的 CIDR %q: %w", rule, err)
+			}
+			prefixes = append(prefixes, prefix.Masked())
+		default:
+			if addr, err := netip.ParseAddr(strings.Trim(rule, "[]")); err == nil {
+				prefixes = append(prefixes, netip.PrefixFrom(addr.Unmap(), addr.Unmap().BitLen()))
+				continue
+			}
+			hosts = append(hosts, strings.TrimPrefix(strings.TrimPrefix(rule, "*"), "."))
+		}
+	}
+	return hosts, prefixes, nil
+}
+
+// matchHost 主机名是否等于 hosts 中的某一项或是其子域名
+func matchHost(host string, hosts []string) bool {
+	for _, h := range hosts {
+		if host == h || strings.HasSuffix(host, "."+h) {
+			return true
+		}
+	}
+	return false
+}
+
+// matchPrefix 地址是否在 prefixes 中的某个地址段内
+func matchPrefix(addr netip.Addr, prefixes []netip.Prefix) bool {
+	for _, prefix := range prefixes {
+		if prefix.Contains(addr) {
+			return true
+		}
+	}
+	return false
+}
+
+// isPrivateAddr 是否为内网、回环、链路本地等不应从外部请求访问的地址
+func isPrivateAddr(addr netip.Addr) bool {
+	return addr.IsPrivate() || addr.IsLoopback() || addr.IsLinkLocalUnicast() ||
+		addr.IsLinkLocalMulticast() || addr.IsInterfaceLocalMulticast() ||
+		addr.IsMulticast() || addr.IsUnspecified() || matchPrefix(addr, privatePrefixes)
+}
+
+// allowlisted 是否配置了允许列表
+func (p *hostPolicy) allowlisted() bool {
+	return len(p.allowHosts) > 0 || len(p.allowPrefix) > 0
+}
+
+// checkHost 按主机名检查规则，允许列表只有 CIDR 时留到检查解析结果时再判断
+func (p *hostPolicy) checkHost(host string) error {
+	if matchHost(host, p.denyHosts) {
+		return fmt.Errorf("%w: %s 在拒绝列表中", ErrHostBlocked, host)
+	}
+	if p.allowlisted() && len(p.allowPrefix) == 0 && !matchHost(host, p.allowHosts) {
+		return fmt.Errorf("%w: %s 不在允许列表中", ErrHostBlocked, host)
+	}
+	return nil
+}
+
+// checkAddr 检查主机名解析到的地址，host 为 IP 时与 addr 相同
+func (p *hostPolicy) checkAddr(host string, addr netip.Addr) error {
+	addr = addr.Unmap()
+	if matchPrefix(addr, p.denyPrefix) {
+		return fmt.Errorf("%w: %s（%s）在拒绝列表中", ErrHostBlocked, host, addr)
+	}
+	if matchPrefix(addr, p.allowPrefix) {
+		return nil
+	}
+	if !p.allowPrivate && isPrivateAddr(addr) {
+		return fmt.Errorf("%w: %s 解析到内网地址 %s", ErrHostBlocked, host, addr)
+	}
+	if p.allowlisted() && !matchHost(host, p.allowHosts) {
+		return fmt.Errorf("%w: %s（%s）不在允许列表中", ErrHostBlocked, host, addr)
+	}
+	return nil
+}
+
+// resolveAllowed 解析主机名并检查规则，返回允许连接的地址；任一地址不允许时返回错误
+func resolveAllowed(ctx context.Context, host string) ([]netip.Addr, error) {
+	p := policy
+	host = strings.ToLower(strings.TrimSuffix(host, "."))
+
+	if addr, err := netip.ParseAddr(host); err == nil {
+		if err := p.checkAddr(host, addr); err != nil {
+			return nil, err
+		}
+		return []netip.Addr{addr}, nil
+	}
+
+	if err := p.checkHost(host); err != nil {
+		return nil, err
+	}
+	addrs, err := net.DefaultResolver.LookupNetIP(ctx, "ip", host)
+	if err != nil {
+		return nil, fmt.Errorf("解析图片地址 %s 失败: %w", host, err)
+	}
+	for _, addr := range addrs {
+		if err := p.checkAddr(host, addr); err != nil {
+			return nil, err
+		}
+	}
+	return addrs, nil
+}
+
+// CheckImageURL 检查图片 URL 是否允许下载：先按主机名检查规则，再检查 DNS 解析到的每个地址。
+// 不允许时返回包装了 ErrHostBlocked 的错误
+func CheckImageURL(ctx context.Context, imageURL string) error {
+	u, err := url.Parse(imageURL)
+	if err != nil {
+		return fmt.Errorf("无效的图片地址: %w", err)
+	}
+	if u.Hostname() == "" {
+		return fmt.Errorf("%w: 缺少主机名", ErrHostBlocked)
+	}
+
+	_, err = resolveAllowed(ctx, u.Hostname())
+	return err
+}
+
+// guardedDial 解析并检查地址后直接连接检查过的 IP，避免 DNS 在检查之后改变解析结果
+func guardedDial(ctx context.Context, network, address string) (net.Conn, error) {
+	host, port, err := net.SplitHostPort(address)
+	if err != nil {
+		return nil, err
+	}
+	addrs, err := resolveAllowed(ctx, host)
+	if err != nil {
+		return nil, err
+	}
+
+	dialer := &net.Dialer{Timeout: 30 * time.Second, KeepAlive: 30 * time.Second}
+	var lastErr error
+	for _, addr := range addrs {
+		conn, err := dialer.DialContext(ctx, network, net.JoinHostPort(addr.String(), port))
+		if err == nil {
+			return conn, nil
+		}
+		lastErr = err
+	}
+	return nil, lastErr
+}
+
+// guardedTransport 不经过代理的下载使用的 Transport，连接时检查实际连接的 IP
+var guardedTransport = &http.Transport{
+	DialContext:           guardedDial,
+	ForceAttemptHTTP2:     true,
+	MaxIdleConns:          100,
+	IdleConnTimeout:       90 * time.Second,
+	TLSHandshakeTimeout:   10 * time.Second,
+	ExpectContinueTimeout: time.Second,
+}
+
+// downloadClient 下载图片使用的客户端，每次重定向都重新检查目标地址。
+// 配置了 HTTP(S)_PROXY 时经过代理下载，DNS 由代理解析，只能在下载前检查
+var downloadClient = &http.Client{
+	Transport: roundTripperFunc(func(req *http.Request) (*http.Response, error) {
+		if proxy, _ := http.ProxyFromEnvironment(req); proxy != nil {
+			return http.DefaultTransport.RoundTrip(req)
+		}
+		return guardedTransport.RoundTrip(req)
+	}),
+	CheckRedirect: func(req *http.Request, via []*http.Request) error {
+		if len(via) >= 10 {
+			return errors.New("重定向次数过多")
+		}
+		return CheckImageURL(req.Context(), req.URL.String())
+	},
+}
+
+// roundTripperFunc 函数形式的 http.RoundTripper
+type roundTripperFunc func(*http.Request) (*http.Response, error)
+
+// RoundTrip 实现 http.RoundTripper
+func (f roundTripperFunc) RoundTrip(req *http.Request) (*http.Response, error) {
+	return f(req)
+}
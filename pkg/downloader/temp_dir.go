@@ -0,0 +1,17 @@
+package downloader
+
+import "os"
+
+// tempDir 下载、解码的图片写入的目录，为空时使用系统临时目录
+var tempDir = ""
+
+// SetTempDir 设置下载、解码的图片写入的目录，为空时使用系统临时目录。
+// 目录需已存在且可写，应在处理请求之前设置
+func SetTempDir(dir string) {
+	tempDir = dir
+}
+
+// createTempFile 在临时目录中创建文件，pattern 与 os.CreateTemp 相同
+func createTempFile(pattern string) (*os.File, error) {
+	return os.CreateTemp(tempDir, pattern)
+}
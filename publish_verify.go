@@ -0,0 +1,94 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/xpzouying/xiaohongshu-mcp/xiaohongshu"
+)
+
+// 发布后确认
+//
+// action.Publish 返回错误时笔记可能已经发出（如点击发布后页面跳转超时），直接重试会重复发布；
+// 返回成功时笔记也可能没有真正出现在账号中。以 -publish-verify 启动时，发布结束后
+// 再打开创作者中心的笔记管理页，查找标题相同、在本次发布开始前后发出的笔记，
+// 据此判断笔记是否真的发布成功。确认需要额外启动一次浏览器，默认关闭。
+
+// publishVerifyWindow 笔记管理页的发布时间只精确到分钟，查找不早于发布开始前这么久发出的笔记
+const publishVerifyWindow = time.Minute
+
+// warnPublishNotFound 发布返回成功，但笔记管理页中没有找到这篇笔记
+const warnPublishNotFound = "发布后没有在创作者中心找到这篇笔记，可能仍在处理中，请稍后在笔记管理中确认"
+
+// PublishVerification 发布后在创作者中心确认的结果
+type PublishVerification struct {
+	Landed      bool   `json:"landed"`                 // 笔记管理页中是否找到了这篇笔记
+	FeedID      string `json:"feed_id,omitempty"`      // 找到的笔记ID
+	PublishedAt string `json:"published_at,omitempty"` // 笔记管理页显示的发布时间
+}
+
+// publishMayHaveLanded 发布失败时笔记是否可能已经发出。
+// 参数错误、排队超时、未登录、被限流等错误发生在提交之前，不需要确认
+func publishMayHaveLanded(err error) bool {
+	if err == nil {
+		return true
+	}
+
+	var rateLimited *RateLimitedError
+	switch {
+	case errors.Is(err, ErrInvalidArgs),
+		errors.Is(err, ErrServerBusy),
+		errors.Is(err, xiaohongshu.ErrNotLoggedIn),
+		errors.Is(err, xiaohongshu.ErrRateLimited),
+		errors.As(err, &rateLimited):
+		return false
+	}
+	return true
+}
+
+// verifyPublished 打开创作者中心的笔记管理页，查找标题为 title、在 start 前后发出的笔记。
+// 发布本身可能已经用完了请求的超时时间，确认使用独立的读操作超时
+func (s *XiaohongshuService) verifyPublished(ctx context.Context, title string, start time.Time) (*PublishVerification, error) {
+	ctx = context.WithoutCancel(ctx)
+	return retryOnBrowserCrash("publish_verify", func() (*PublishVerification, error) {
+		return s.findPublishedNote(ctx, title, start)
+	})
+}
+
+// findPublishedNote verifyPublished 的单次执行，浏览器崩溃时由 verifyPublished 重试
+func (s *XiaohongshuService) findPublishedNote(ctx context.Context, title string, start time.Time) (*PublishVerification, error) {
+	ctx, done := withTimeout(ctx, "publish_verify", false)
+	defer done()
+
+	b, err := s.newBrowser(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer b.Close()
+
+	page := newPage(b)
+	defer page.Close()
+	defer saveCookies(page)
+
+	action := xiaohongshu.NewNoteManagerAction(page.Context(ctx))
+
+	var notes []xiaohongshu.PublishedNote
+	err = retryOnCaptcha(ctx, page, func() (err error) {
+		notes, err = action.RecentNotes(ctx)
+		return err
+	})
+	if err = checkBlockedPage(page, err, false); err != nil {
+		return nil, screenshotOnError(page, err)
+	}
+
+	note, ok := xiaohongshu.FindPublishedNote(notes, title, start.Add(-publishVerifyWindow))
+	if !ok {
+		return &PublishVerification{}, nil
+	}
+	return &PublishVerification{
+		Landed:      true,
+		FeedID:      note.ID,
+		PublishedAt: note.PublishedAt.Format(time.RFC3339),
+	}, nil
+}
@@ -0,0 +1,73 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"github.com/xpzouying/xiaohongshu-mcp/xiaohongshu"
+)
+
+// 发布时设置可见范围
+//
+// 发布流程按公开可见发出笔记，指定了 friends 或 private 时，发布完成后再打开笔记编辑页，
+// 把新笔记改为指定的可见范围，与 hide_feed 使用同一个编辑页操作。发布页没有返回笔记ID时，
+// 先在创作者中心按标题和发布时间查找刚发出的笔记。修改失败时笔记已经公开发出，
+// 不能按发布失败返回，否则调用方重试会重复发布，因此只在结果的 warnings 中说明。
+
+// applyPublishVisibility 把刚发布的笔记改为 visibility，返回笔记ID。
+// postID 为空时先在创作者中心查找标题为 title、在 start 前后发出的笔记
+func (s *XiaohongshuService) applyPublishVisibility(ctx context.Context, postID, title string, start time.Time, visibility xiaohongshu.Visibility) (string, error) {
+	// 笔记已经发出，客户端断开也要完成修改
+	ctx, done := withTimeout(context.WithoutCancel(ctx), "publish_visibility", true)
+	defer done()
+
+	release, err := s.acquireWrite(ctx)
+	if err != nil {
+		return postID, err
+	}
+	defer release()
+
+	b, err := s.newBrowser(ctx)
+	if err != nil {
+		return postID, err
+	}
+	defer b.Close()
+
+	page := newPage(b)
+	defer page.Close()
+	defer saveCookies(page)
+
+	if postID == "" {
+		var notes []xiaohongshu.PublishedNote
+		err = retryOnCaptcha(ctx, page, func() (err error) {
+			notes, err = xiaohongshu.NewNoteManagerAction(page.Context(ctx)).RecentNotes(ctx)
+			return err
+		})
+		if err = checkBlockedPage(page, err, false); err != nil {
+			return "", screenshotOnError(page, err)
+		}
+
+		note, ok := xiaohongshu.FindPublishedNote(notes, title, start.Add(-publishVerifyWindow))
+		if !ok {
+			return "", errors.New("没有在创作者中心找到刚发布的笔记")
+		}
+		postID = note.ID
+	}
+
+	if err := xiaohongshu.NewEditFeedAction(page.Context(ctx)).SetVisibility(ctx, postID, visibility); err != nil {
+		return postID, screenshotOnError(page, s.writeError(page, err))
+	}
+	s.detailCache.Delete(postID)
+
+	logrus.WithContext(ctx).Infof("已将新发布的笔记 %s 设为 %s", postID, visibility)
+
+	return postID, nil
+}
+
+// visibilityNotAppliedWarning 发布成功但可见范围没有改成功时返回的警告
+func visibilityNotAppliedWarning(visibility xiaohongshu.Visibility, err error) string {
+	return fmt.Sprintf("笔记已公开发布，但设为 %s 失败，目前仍为公开可见，请使用 hide_feed 修改: %v", visibility, err)
+}
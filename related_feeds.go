@@ -0,0 +1,82 @@
+package main
+
+import (
+	"context"
+
+	"github.com/xpzouying/xiaohongshu-mcp/xiaohongshu"
+)
+
+// warnNoRelatedFeeds 详情页没有加载出相关推荐
+const warnNoRelatedFeeds = "笔记详情页没有加载出相关推荐，可能是推荐加载超时、未登录或该笔记没有推荐，可稍后重试"
+
+// RelatedFeedsResponse 笔记相关推荐响应
+type RelatedFeedsResponse struct {
+	FeedID    string             `json:"feed_id"`
+	Feeds     []xiaohongshu.Feed `json:"feeds"`
+	Count     int                `json:"count"`
+	Warnings  []string           `json:"warnings,omitempty"`
+	XsecToken string             `json:"xsec_token,omitempty"` // 传入的 xsec_token 已过期时自动刷新得到的新令牌，后续请求应改用它
+}
+
+// RelatedFeeds 获取笔记详情页“相关推荐”中的笔记，每篇笔记带有自己的 xsecToken，可直接用于获取详情。
+// 推荐没有加载出来时返回空列表并附带警告
+func (s *XiaohongshuService) RelatedFeeds(ctx context.Context, feedID, xsecToken string) (*RelatedFeedsResponse, error) {
+	return retryOnTokenExpired(ctx, s, feedID, xsecToken, func(token string) (*RelatedFeedsResponse, error) {
+		result, err := retryOnBrowserCrash("related_feeds", func() (*RelatedFeedsResponse, error) {
+			return s.relatedFeeds(ctx, feedID, token)
+		})
+		if err == nil && token != xsecToken {
+			result.XsecToken = token
+		}
+		return result, err
+	})
+}
+
+// relatedFeeds RelatedFeeds 的单次执行，与笔记详情一样打开详情页，再读取相关推荐
+func (s *XiaohongshuService) relatedFeeds(ctx context.Context, feedID, xsecToken string) (*RelatedFeedsResponse, error) {
+	ctx, done := withTimeout(ctx, "related_feeds", false)
+	defer done()
+
+	b, err := s.newBrowser(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer b.Close()
+
+	page := newPage(b)
+	defer page.Close()
+	defer saveCookies(page)
+
+	action := xiaohongshu.NewFeedDetailAction(page.Context(ctx))
+
+	var detail any
+	err = retryOnCaptcha(ctx, page, func() (err error) {
+		detail, err = action.GetFeedDetail(ctx, feedID, xsecToken)
+		return err
+	})
+	if err = checkBlockedPage(page, err, detail == nil); err != nil {
+		return nil, screenshotOnError(page, err)
+	}
+
+	feeds, err := xiaohongshu.GetRelatedFeeds(page, feedID)
+	if err != nil {
+		return nil, screenshotOnError(page, err)
+	}
+
+	return newRelatedFeedsResponse(feedID, feeds), nil
+}
+
+// newRelatedFeedsResponse 组装相关推荐响应，没有推荐时附带警告
+func newRelatedFeedsResponse(feedID string, feeds []xiaohongshu.Feed) *RelatedFeedsResponse {
+	response := &RelatedFeedsResponse{
+		FeedID:   feedID,
+		Feeds:    feeds,
+		Count:    len(feeds),
+		Warnings: xiaohongshu.FeedWarnings(feeds),
+	}
+	if len(feeds) == 0 {
+		response.Feeds = []xiaohongshu.Feed{}
+		response.Warnings = append(response.Warnings, warnNoRelatedFeeds)
+	}
+	return response
+}
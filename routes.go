@@ -12,29 +12,53 @@ func setupRoutes(appServer *AppServer) *gin.Engine {
 	router := gin.New()
 	router.Use(gin.Logger())
 	router.Use(gin.Recovery())
+	router.Use(opIDMiddleware())
 
 	// 添加中间件
 	router.Use(errorHandlingMiddleware())
 	router.Use(corsMiddleware())
 
 	// 健康检查
-	router.GET("/health", healthHandler)
+	router.GET("/health", appServer.healthHandler)
 
-	// MCP 端点 - 使用 Streamable HTTP 协议
+	// 构建信息
+	router.GET("/version", appServer.versionHandler)
+
+	// MCP 端点 - 使用 Streamable HTTP 协议，JSON 响应按需压缩，SSE 和 WebSocket 不压缩
 	mcpHandler := appServer.StreamableHTTPHandler()
-	router.Any("/mcp", gin.WrapH(mcpHandler))
-	router.Any("/mcp/*path", gin.WrapH(mcpHandler))
+	router.Any("/mcp", gzipMiddleware(), gin.WrapH(mcpHandler))
+	router.Any("/mcp/*path", gzipMiddleware(), gin.WrapH(mcpHandler))
 
 	// API 路由组
-	api := router.Group("/api/v1")
+	api := router.Group("/api/v1", bodyLimitMiddleware(), gzipMiddleware())
 	{
 		api.GET("/login/status", appServer.checkLoginStatusHandler)
-		api.POST("/publish", appServer.publishHandler)
+		api.GET("/login/status/all", appServer.checkAllLoginsHandler)
+		api.POST("/logout", safeModeMiddleware(), appServer.logoutHandler)
+		api.POST("/publish", safeModeMiddleware(), appServer.publishHandler)
 		api.GET("/feeds/list", appServer.listFeedsHandler)
 		api.GET("/feeds/search", appServer.searchFeedsHandler)
+		api.GET("/feeds/explore", appServer.exploreFeedsHandler)
+		api.GET("/feeds/search/details", appServer.searchAndDetailHandler)
+		api.GET("/topics/search", appServer.searchTopicsHandler)
+		api.GET("/topics/trending", appServer.trendingTopicsHandler)
+		api.GET("/notifications", appServer.listNotificationsHandler)
 		api.POST("/feeds/detail", appServer.getFeedDetailHandler)
+		api.POST("/feeds/comments", appServer.listCommentsHandler)
+		api.POST("/feeds/comments/tree", appServer.commentTreeHandler)
+		api.POST("/feeds/edit", safeModeMiddleware(), appServer.editFeedHandler)
+		api.POST("/feeds/visibility", safeModeMiddleware(), appServer.feedVisibilityHandler)
+		api.GET("/feeds/owned", appServer.noteOwnershipHandler)
+		api.GET("/feeds/link", appServer.feedLinkHandler)
+		api.GET("/feeds/tags", appServer.feedTagsHandler)
+		api.GET("/feeds/related", appServer.relatedFeedsHandler)
+		api.GET("/feeds/screenshot", appServer.screenshotFeedHandler)
+		api.GET("/feeds/analytics", appServer.feedAnalyticsHandler)
 		api.POST("/user/profile", appServer.userProfileHandler)
-		api.POST("/feeds/comment", appServer.postCommentHandler)
+		api.POST("/user/collections", appServer.userCollectionsHandler)
+		api.GET("/user/me", appServer.myProfileHandler)
+		api.POST("/feeds/comment", safeModeMiddleware(), appServer.postCommentHandler)
+		api.POST("/feeds/comment/batch", safeModeMiddleware(), appServer.postCommentsHandler)
 	}
 
 	return router
@@ -0,0 +1,40 @@
+package main
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/xpzouying/xiaohongshu-mcp/configs"
+)
+
+// ErrOperationDisabled 安全模式下拒绝会修改账号内容的操作
+var ErrOperationDisabled = errors.New("服务以 -safe-mode 启动，已禁用发布、编辑、评论、退出登录等会修改账号的操作")
+
+// destructiveTools 会修改账号内容或登录状态的工具，安全模式下不出现在工具列表中，调用时返回 OPERATION_DISABLED
+var destructiveTools = map[string]bool{
+	"logout":               true,
+	"publish_content":      true,
+	"edit_feed":            true,
+	"hide_feed":            true,
+	"post_comment_to_feed": true,
+	"post_comments":        true,
+}
+
+// isToolDisabled 判断工具是否因安全模式被禁用
+func isToolDisabled(name string) bool {
+	return configs.IsSafeMode() && destructiveTools[name]
+}
+
+// safeModeMiddleware 安全模式下拒绝挂载了该中间件的 REST 接口，用于与 destructiveTools 对应的写操作路由
+func safeModeMiddleware() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if configs.IsSafeMode() {
+			respondError(c, http.StatusForbidden, "OPERATION_DISABLED",
+				"安全模式下不允许该操作", ErrOperationDisabled.Error())
+			c.Abort()
+			return
+		}
+		c.Next()
+	}
+}
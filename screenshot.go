@@ -0,0 +1,97 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/go-rod/rod"
+	"github.com/go-rod/rod/lib/proto"
+	"github.com/sirupsen/logrus"
+	"github.com/xpzouying/xiaohongshu-mcp/configs"
+)
+
+const (
+	// screenshotPrefix 错误截图文件名前缀，清理旧截图时只处理带该前缀的文件
+	screenshotPrefix = "error-"
+	// screenshotTimeout 截图的超时时间，避免页面卡死时阻塞错误返回
+	screenshotTimeout = 10 * time.Second
+)
+
+// screenshotOnError 开启 -screenshot-on-error 时保存当前页面截图，返回附带截图路径的错误。
+// 截图失败不影响原错误的返回；浏览器已崩溃时无法截图，返回的错误包含 errBrowserCrashed。
+func screenshotOnError(page *rod.Page, err error) error {
+	if err == nil {
+		return nil
+	}
+	if browserCrashed(page) {
+		return errors.Join(errBrowserCrashed, err)
+	}
+	if !configs.IsScreenshotOnError() {
+		return err
+	}
+
+	path, shotErr := saveScreenshot(page)
+	if shotErr != nil {
+		logrus.WithContext(page.GetContext()).Warnf("保存错误截图失败: %v", shotErr)
+		return err
+	}
+
+	logrus.WithContext(page.GetContext()).Infof("操作失败，已保存页面截图: %s", path)
+	return fmt.Errorf("%w（页面截图: %s）", err, path)
+}
+
+// saveScreenshot 截取整个页面保存为 PNG，并清理超出保留数量的旧截图
+func saveScreenshot(page *rod.Page) (string, error) {
+	data, err := page.Timeout(screenshotTimeout).Screenshot(true, &proto.PageCaptureScreenshot{
+		Format: proto.PageCaptureScreenshotFormatPng,
+	})
+	if err != nil {
+		return "", fmt.Errorf("截图失败: %w", err)
+	}
+
+	dir := configs.GetScreenshotDir()
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return "", fmt.Errorf("创建截图目录失败: %w", err)
+	}
+
+	name := screenshotPrefix + time.Now().Format("20060102-150405.000") + ".png"
+	path := filepath.Join(dir, name)
+	if err := os.WriteFile(path, data, 0o644); err != nil {
+		return "", fmt.Errorf("写入截图失败: %w", err)
+	}
+
+	pruneScreenshots(dir, configs.GetMaxScreenshots())
+
+	return path, nil
+}
+
+// pruneScreenshots 只保留最新的 keep 张截图。文件名以时间开头，按名称排序即按时间排序
+func pruneScreenshots(dir string, keep int) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		logrus.Warnf("读取截图目录失败: %v", err)
+		return
+	}
+
+	var names []string
+	for _, entry := range entries {
+		if !entry.IsDir() && strings.HasPrefix(entry.Name(), screenshotPrefix) && strings.HasSuffix(entry.Name(), ".png") {
+			names = append(names, entry.Name())
+		}
+	}
+	if len(names) <= keep {
+		return
+	}
+
+	sort.Strings(names)
+	for _, name := range names[:len(names)-keep] {
+		if err := os.Remove(filepath.Join(dir, name)); err != nil {
+			logrus.Warnf("删除旧截图失败: %v", err)
+		}
+	}
+}
@@ -0,0 +1,101 @@
+package main
+
+import (
+	"context"
+
+	"github.com/go-rod/rod/lib/proto"
+	"github.com/sirupsen/logrus"
+
+	"github.com/xpzouying/xiaohongshu-mcp/configs"
+	"github.com/xpzouying/xiaohongshu-mcp/xiaohongshu"
+)
+
+// warnScreenshotTruncated 笔记内容超过最大截图高度
+const warnScreenshotTruncated = "笔记内容过长，截图只保留了上半部分"
+
+// ScreenshotFeedResponse 笔记截图响应，图片本身不参与 JSON 序列化，由 MCP 以 image 内容、REST 以 image/png 返回
+type ScreenshotFeedResponse struct {
+	FeedID    string   `json:"feed_id"`
+	Scope     string   `json:"scope"`
+	Width     int      `json:"width"`
+	Height    int      `json:"height"`
+	Image     []byte   `json:"-"`
+	Warnings  []string `json:"warnings,omitempty"`
+	XsecToken string   `json:"xsec_token,omitempty"` // 传入的 xsec_token 已过期时自动刷新得到的新令牌，后续请求应改用它
+}
+
+// ScreenshotFeed 打开笔记详情页并截取 PNG 图片。width 为截图时的视口宽度，0 表示使用 -viewport 的宽度；
+// scope 为 note（默认）时只截取笔记区域，为 page 时截取整个页面。图片高度超过上限时只保留上半部分并附带警告
+func (s *XiaohongshuService) ScreenshotFeed(ctx context.Context, feedID, xsecToken string, width int, scope string) (*ScreenshotFeedResponse, error) {
+	if scope == "" {
+		scope = xiaohongshu.ScreenshotScopeNote
+	}
+	return retryOnTokenExpired(ctx, s, feedID, xsecToken, func(token string) (*ScreenshotFeedResponse, error) {
+		result, err := retryOnBrowserCrash("screenshot_feed", func() (*ScreenshotFeedResponse, error) {
+			return s.screenshotFeed(ctx, feedID, token, width, scope)
+		})
+		if err == nil && token != xsecToken {
+			result.XsecToken = token
+		}
+		return result, err
+	})
+}
+
+// screenshotFeed ScreenshotFeed 的单次执行，先按指定宽度设置视口再打开详情页，保证同一宽度下渲染结果一致
+func (s *XiaohongshuService) screenshotFeed(ctx context.Context, feedID, xsecToken string, width int, scope string) (*ScreenshotFeedResponse, error) {
+	ctx, done := withTimeout(ctx, "screenshot_feed", false)
+	defer done()
+
+	b, err := s.newBrowser(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer b.Close()
+
+	page := newPage(b)
+	defer page.Close()
+	defer saveCookies(page)
+
+	if width > 0 {
+		if err := page.SetViewport(&proto.EmulationSetDeviceMetricsOverride{
+			Width:             width,
+			Height:            configs.GetViewport().Height,
+			DeviceScaleFactor: 1,
+		}); err != nil {
+			logrus.WithContext(ctx).Warnf("设置截图视口宽度失败: %v", err)
+		}
+	}
+
+	action := xiaohongshu.NewFeedDetailAction(page.Context(ctx))
+
+	var detail any
+	err = retryOnCaptcha(ctx, page, func() (err error) {
+		detail, err = action.GetFeedDetail(ctx, feedID, xsecToken)
+		return err
+	})
+	if err = checkBlockedPage(page, err, detail == nil); err != nil {
+		return nil, screenshotOnError(page, err)
+	}
+
+	shot, err := xiaohongshu.ScreenshotFeed(page, scope)
+	if err != nil {
+		return nil, screenshotOnError(page, err)
+	}
+
+	return newScreenshotFeedResponse(feedID, scope, shot), nil
+}
+
+// newScreenshotFeedResponse 组装笔记截图响应，截图被截断时附带警告
+func newScreenshotFeedResponse(feedID, scope string, shot *xiaohongshu.FeedScreenshot) *ScreenshotFeedResponse {
+	response := &ScreenshotFeedResponse{
+		FeedID: feedID,
+		Scope:  scope,
+		Width:  shot.Width,
+		Height: shot.Height,
+		Image:  shot.PNG,
+	}
+	if shot.Truncated {
+		response.Warnings = append(response.Warnings, warnScreenshotTruncated)
+	}
+	return response
+}
@@ -0,0 +1,157 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+
+	"github.com/go-rod/rod"
+	"github.com/sirupsen/logrus"
+	"github.com/xpzouying/xiaohongshu-mcp/xiaohongshu"
+)
+
+// 搜索并获取详情
+//
+// 先按关键词搜索，再在同一个浏览器会话中依次打开前 N 篇笔记获取详情，
+// 减少“搜索 + 逐篇获取详情”的往返次数。单篇详情获取失败只记录在该条结果和警告中，不影响其他笔记。
+
+const (
+	// defaultSearchDetailLimit 默认获取详情的笔记数量
+	defaultSearchDetailLimit = 5
+	// maxSearchDetailLimit 单次最多获取详情的笔记数量，避免一次请求打开过多页面触发风控
+	maxSearchDetailLimit = 20
+)
+
+// SearchDetailItem 搜索结果中的一篇笔记及其详情，获取详情失败时 Detail 为空、Error 为失败原因
+type SearchDetailItem struct {
+	Feed   xiaohongshu.Feed    `json:"feed"`
+	Detail *FeedDetailResponse `json:"detail,omitempty"`
+	Error  string              `json:"error,omitempty"`
+}
+
+// SearchDetailResponse 搜索并获取详情的响应
+type SearchDetailResponse struct {
+	Keyword   string             `json:"keyword"`
+	Total     int                `json:"total"` // 搜索结果总数
+	Items     []SearchDetailItem `json:"items"`
+	Count     int                `json:"count"` // 返回的笔记数
+	Succeeded int                `json:"succeeded"`
+	Failed    int                `json:"failed"`
+	Warnings  []string           `json:"warnings,omitempty"`
+}
+
+// SearchAndDetail 搜索关键词，并在同一个浏览器会话中获取前 limit 篇笔记的详情。
+// limit 小于等于 0 时使用默认值，最多 maxSearchDetailLimit 篇
+func (s *XiaohongshuService) SearchAndDetail(ctx context.Context, keyword string, limit int) (*SearchDetailResponse, error) {
+	search, err := s.SearchFeeds(ctx, keyword)
+	if err != nil {
+		return nil, err
+	}
+
+	ctx, done := withTimeout(ctx, "search_and_detail", false)
+	defer done()
+
+	session := &feedDetailSession{service: s, ctx: ctx}
+	defer session.Close()
+
+	return collectFeedDetails(ctx, keyword, search, limit, session.Fetch)
+}
+
+// feedDetailSession 在同一个浏览器页面中依次获取多篇笔记的详情。
+// 第一次需要打开页面时才启动浏览器，全部命中缓存时不启动浏览器
+type feedDetailSession struct {
+	service *XiaohongshuService
+	ctx     context.Context
+
+	browser *serviceBrowser
+	page    *rod.Page
+	action  *xiaohongshu.FeedDetailAction
+}
+
+// Fetch 获取一篇笔记的详情，优先使用缓存
+func (d *feedDetailSession) Fetch(feed xiaohongshu.Feed) (*FeedDetailResponse, error) {
+	if cached, ok := d.service.detailCache.Get(d.ctx, feed.ID); ok {
+		return cached, nil
+	}
+
+	if d.page == nil {
+		b, err := d.service.newBrowser(d.ctx)
+		if err != nil {
+			return nil, err
+		}
+		d.browser = b
+		d.page = newPage(b)
+		d.action = xiaohongshu.NewFeedDetailAction(d.page.Context(d.ctx))
+	}
+
+	detail, err := feedDetailOnPage(d.ctx, d.page, d.action, feed.ID, feed.XsecToken)
+	if err != nil {
+		return nil, screenshotOnError(d.page, err)
+	}
+	d.service.detailCache.Set(feed.ID, detail)
+
+	return detail, nil
+}
+
+// Close 保存登录会话并关闭浏览器，没有启动过浏览器时不做操作
+func (d *feedDetailSession) Close() {
+	if d.page == nil {
+		return
+	}
+	saveCookies(d.page)
+	d.page.Close()
+	d.browser.Close()
+}
+
+// collectFeedDetails 取搜索结果的前 limit 篇笔记，用 fetch 依次获取详情，真实服务和 mock 服务共用。
+// 单篇失败记录在结果中；浏览器崩溃、浏览器名额排队超时、请求超时或被取消时，其余笔记不再尝试
+func collectFeedDetails(ctx context.Context, keyword string, search *FeedsListResponse, limit int, fetch func(xiaohongshu.Feed) (*FeedDetailResponse, error)) (*SearchDetailResponse, error) {
+	if limit <= 0 {
+		limit = defaultSearchDetailLimit
+	}
+	feeds := search.Feeds[:min(limit, maxSearchDetailLimit, len(search.Feeds))]
+
+	response := &SearchDetailResponse{
+		Keyword:  keyword,
+		Total:    search.Count,
+		Items:    make([]SearchDetailItem, 0, len(feeds)),
+		Warnings: slices.Clone(search.Warnings), // 搜索结果可能来自缓存，不能直接追加
+	}
+	if limit > maxSearchDetailLimit {
+		response.Warnings = append(response.Warnings, fmt.Sprintf("limit 超过上限，只获取前 %d 篇笔记的详情", maxSearchDetailLimit))
+	}
+
+	var stopErr error
+	for _, feed := range feeds {
+		item := SearchDetailItem{Feed: feed}
+
+		if stopErr == nil {
+			if err := ctx.Err(); err != nil {
+				stopErr = err
+			}
+		}
+		if stopErr != nil {
+			item.Error = "未获取详情: " + stopErr.Error()
+		} else if detail, err := fetch(feed); err != nil {
+			item.Error = err.Error()
+			if errors.Is(err, errBrowserCrashed) || errors.Is(err, ErrServerBusy) {
+				stopErr = err
+			}
+		} else {
+			item.Detail = detail
+		}
+
+		if item.Error != "" {
+			logrus.WithContext(ctx).Warnf("search_and_detail: 获取笔记 %s 的详情失败: %s", feed.ID, item.Error)
+			response.Failed++
+			response.Warnings = append(response.Warnings, fmt.Sprintf("笔记 %s 获取详情失败: %s", feed.ID, item.Error))
+		} else {
+			response.Succeeded++
+		}
+		response.Items = append(response.Items, item)
+	}
+	response.Count = len(response.Items)
+
+	return response, nil
+}
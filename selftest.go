@@ -0,0 +1,127 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/xpzouying/xiaohongshu-mcp/configs"
+)
+
+// 自检模式
+//
+// -selftest 启动后不监听端口，依次检查浏览器能否启动、登录是否有效、
+// 能否读取首页 Feeds，打印每一步的结果后退出。任一步失败时退出码非 0，
+// 可用于 CI、定时任务或发布前确认部署环境的浏览器和登录会话是否正常。
+// 只执行读操作，不会发布或修改任何内容。
+
+// errSelfTestSkipped 前面的检查失败，跳过依赖它的检查
+var errSelfTestSkipped = errors.New("前置检查失败，已跳过")
+
+// selfTestStep 自检的一个步骤，run 返回成功时附带的说明
+type selfTestStep struct {
+	name string
+	run  func(ctx context.Context) (string, error)
+}
+
+// selfTestSteps 按顺序返回自检步骤。mock 模式不启动浏览器，跳过浏览器检查
+func selfTestSteps(service XHSService) []selfTestStep {
+	var steps []selfTestStep
+
+	if s, ok := service.(*XiaohongshuService); ok {
+		steps = append(steps, selfTestStep{name: "browser", run: s.probeBrowser})
+	}
+
+	steps = append(steps,
+		selfTestStep{name: "login", run: func(ctx context.Context) (string, error) {
+			status, err := service.CheckLoginStatus(ctx)
+			if err != nil {
+				return "", err
+			}
+			if !status.IsLoggedIn {
+				return "", errors.New("未登录，请先运行登录工具")
+			}
+			return fmt.Sprintf("已登录 %s", status.Nickname), nil
+		}},
+		selfTestStep{name: "list_feeds", run: func(ctx context.Context) (string, error) {
+			result, err := service.ListFeeds(withFreshRead(ctx, true))
+			if err != nil {
+				return "", err
+			}
+			if result.Count == 0 {
+				return "", errors.New("首页没有返回任何 Feeds")
+			}
+			return fmt.Sprintf("获取到 %d 条 Feeds", result.Count), nil
+		}},
+	)
+
+	return steps
+}
+
+// runSelfTest 依次执行自检步骤并把结果写入 w，全部通过时返回 true。
+// 某一步失败后，其余步骤都依赖浏览器和登录会话，直接标记为跳过
+func runSelfTest(ctx context.Context, service XHSService, w io.Writer) bool {
+	steps := selfTestSteps(service)
+
+	passed := 0
+	var failed error
+	for _, step := range steps {
+		if failed != nil {
+			fmt.Fprintf(w, "[SKIP] %-10s %s\n", step.name, errSelfTestSkipped)
+			continue
+		}
+
+		start := time.Now()
+		detail, err := runSelfTestStep(ctx, step)
+		elapsed := time.Since(start).Round(time.Millisecond)
+		if err != nil {
+			failed = err
+			fmt.Fprintf(w, "[FAIL] %-10s %8s  %v\n", step.name, elapsed, err)
+			continue
+		}
+
+		passed++
+		fmt.Fprintf(w, "[PASS] %-10s %8s  %s\n", step.name, elapsed, detail)
+	}
+
+	if failed != nil {
+		fmt.Fprintf(w, "selftest: FAIL (%d/%d passed)\n", passed, len(steps))
+		return false
+	}
+	fmt.Fprintf(w, "selftest: PASS (%d/%d passed)\n", passed, len(steps))
+	return true
+}
+
+// runSelfTestStep 按读操作超时时间执行一个步骤，页面操作中的 panic 转换为错误
+func runSelfTestStep(ctx context.Context, step selfTestStep) (detail string, err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("%v", r)
+		}
+	}()
+
+	ctx, cancel := context.WithTimeout(ctx, configs.GetTimeout())
+	defer cancel()
+
+	return step.run(ctx)
+}
+
+// probeBrowser 启动浏览器并打开页面，返回浏览器版本，确认浏览器可以正常启动
+func (s *XiaohongshuService) probeBrowser(ctx context.Context) (string, error) {
+	b, err := s.newBrowser(ctx)
+	if err != nil {
+		return "", err
+	}
+	defer b.Close()
+
+	page := newPage(b)
+	defer page.Close()
+
+	version, err := page.Browser().Context(ctx).Version()
+	if err != nil {
+		return "", fmt.Errorf("读取浏览器版本失败: %w", err)
+	}
+	return version.Product, nil
+}
@@ -2,73 +2,287 @@ package main
 
 import (
 	"context"
+	"encoding/base64"
+	"errors"
 	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+	"slices"
+	"strings"
+	"time"
 
+	"github.com/go-rod/rod"
+	"github.com/go-rod/rod/lib/proto"
 	"github.com/mattn/go-runewidth"
-	"github.com/xpzouying/headless_browser"
-	"github.com/xpzouying/xiaohongshu-mcp/browser"
+	"github.com/sirupsen/logrus"
 	"github.com/xpzouying/xiaohongshu-mcp/configs"
 	"github.com/xpzouying/xiaohongshu-mcp/pkg/downloader"
 	"github.com/xpzouying/xiaohongshu-mcp/xiaohongshu"
 )
 
 // XiaohongshuService 小红书业务服务
-type XiaohongshuService struct{}
+type XiaohongshuService struct {
+	writeGuard  *accountWriteGuard
+	cooldown    *writeCooldown // 被平台限流后暂停写操作
+	audit       *auditLogger
+	browsers    *browserLimiter                // 同时运行的浏览器数量限制
+	detailCache *ttlCache[*FeedDetailResponse] // 按 feed_id 缓存的笔记详情
+	searchCache *ttlCache[*FeedsListResponse]  // 按关键词缓存的搜索结果
+	images      imageProcessor                 // 把下载、解码后的图片整理为可上传的本地路径
+
+	tokenSources *ttlCache[feedTokenSource] // 笔记出现在哪个列表中，用于刷新过期的 xsec_token
+}
 
 // NewXiaohongshuService 创建小红书服务实例
 func NewXiaohongshuService() *XiaohongshuService {
-	return &XiaohongshuService{}
+	return &XiaohongshuService{
+		writeGuard:  newAccountWriteGuard(),
+		cooldown:    newWriteCooldown(configs.GetRateLimitCooldown()),
+		audit:       newAuditLogger(configs.GetAuditLogPath(), configs.GetAuditLogMaxSize()),
+		browsers:    newBrowserLimiter(configs.GetMaxBrowsers(), configs.GetBrowserWait()),
+		detailCache: newTTLCache[*FeedDetailResponse](configs.GetCacheTTL(), configs.GetCacheMaxEntries()),
+		searchCache: newTTLCache[*FeedsListResponse](configs.GetCacheTTL(), configs.GetCacheMaxEntries()),
+		images:      downloader.NewImageProcessor(),
+
+		tokenSources: newTTLCache[feedTokenSource](feedTokenSourceTTL, feedTokenSourceMaxEntries),
+	}
+}
+
+// acquireWrite 等待当前账号的写锁，发布、评论、编辑等写操作需要串行执行。
+// 账号被平台限流后的冷却期内直接返回 *RateLimitedError，不再启动浏览器
+func (s *XiaohongshuService) acquireWrite(ctx context.Context) (func(), error) {
+	if err := s.cooldown.Check(configs.Username); err != nil {
+		return nil, err
+	}
+
+	release, err := s.writeGuard.Acquire(ctx, configs.Username)
+	if err != nil {
+		return nil, fmt.Errorf("等待其他写操作完成时取消: %w", err)
+	}
+
+	// 排队期间前一个写操作可能触发了限流
+	if err := s.cooldown.Check(configs.Username); err != nil {
+		release()
+		return nil, err
+	}
+	return release, nil
+}
+
+// WriteQueueDepth 返回每个账号正在执行和排队等待的写操作数量
+func (s *XiaohongshuService) WriteQueueDepth() map[string]int {
+	return s.writeGuard.QueueDepth()
+}
+
+// withTimeout 请求本身没有截止时间时，按配置的读/写超时时间派生子 context。
+// 返回的 done 需在方法结束时调用，超时时记录操作名称和耗时
+func withTimeout(ctx context.Context, name string, write bool) (context.Context, func()) {
+	start := time.Now()
+
+	cancel := context.CancelFunc(func() {})
+	if _, ok := ctx.Deadline(); !ok {
+		timeout := configs.GetTimeout()
+		if write {
+			timeout = configs.GetWriteTimeout()
+		}
+		ctx, cancel = context.WithTimeout(ctx, timeout)
+	}
+
+	return ctx, func() {
+		if errors.Is(ctx.Err(), context.DeadlineExceeded) {
+			logrus.WithContext(ctx).Warnf("%s 超时，已执行 %s", name, time.Since(start).Round(time.Millisecond))
+		}
+		cancel()
+	}
 }
 
 // PublishRequest 发布请求
 type PublishRequest struct {
-	Title   string   `json:"title" binding:"required"`
-	Content string   `json:"content" binding:"required"`
-	Images  []string `json:"images" binding:"required,min=1"`
-	Tags    []string `json:"tags,omitempty"`
+	Title      string   `json:"title" binding:"required"`
+	Content    string   `json:"content" binding:"required"`
+	Images     []string `json:"images" binding:"required,min=1"`
+	Tags       []string `json:"tags,omitempty"`
+	CoverIndex int      `json:"cover_index,omitempty"` // 封面图片在 Images 中的序号，默认 0 即第一张
+	Visibility string   `json:"visibility,omitempty"`  // 可见范围：public（默认）、friends 或 private
+	ImageAlts  []string `json:"image_alts,omitempty"`  // 每张图片的描述（替代文字），与 Images 按序号对应，可选
+
+	// 下载 URL 图片时附加的请求头，用于有防盗链或需要鉴权的图床；ImageReferer 是设置 Referer 的简写
+	ImageHeaders map[string]string `json:"image_headers,omitempty"`
+	ImageReferer string            `json:"image_referer,omitempty"`
+}
+
+// imageHeaders 合并下载 URL 图片时附加的请求头，ImageReferer 优先于 ImageHeaders 中的 Referer
+func (r *PublishRequest) imageHeaders() map[string]string {
+	if len(r.ImageHeaders) == 0 && r.ImageReferer == "" {
+		return nil
+	}
+
+	headers := make(map[string]string, len(r.ImageHeaders)+1)
+	for key, value := range r.ImageHeaders {
+		headers[http.CanonicalHeaderKey(key)] = value
+	}
+	if r.ImageReferer != "" {
+		headers["Referer"] = r.ImageReferer
+	}
+	return headers
 }
 
 // LoginStatusResponse 登录状态响应
 type LoginStatusResponse struct {
 	IsLoggedIn bool   `json:"is_logged_in"`
-	Username   string `json:"username,omitempty"`
+	Username   string `json:"username,omitempty"` // 配置的账号名称
+	UserID     string `json:"user_id,omitempty"`  // 实际登录的小红书账号，仅在已登录时返回
+	Nickname   string `json:"nickname,omitempty"`
+	Avatar     string `json:"avatar,omitempty"`
 }
 
 // PublishResponse 发布响应
 type PublishResponse struct {
-	Title   string `json:"title"`
-	Content string `json:"content"`
-	Images  int    `json:"images"`
-	Status  string `json:"status"`
-	PostID  string `json:"post_id,omitempty"`
+	Title    string   `json:"title"`
+	Content  string   `json:"content"`
+	Images   int      `json:"images"`
+	Status   string   `json:"status"`
+	PostID   string   `json:"post_id,omitempty"`
+	Warnings []string `json:"warnings,omitempty"`
+
+	// Visibility 笔记最终的可见范围，设置 friends、private 失败时为 public
+	Visibility string `json:"visibility"`
+
+	// Verification 以 -publish-verify 启动时，发布后在创作者中心确认的结果
+	Verification *PublishVerification `json:"verification,omitempty"`
 }
 
 // FeedsListResponse Feeds列表响应
 type FeedsListResponse struct {
-	Feeds []xiaohongshu.Feed `json:"feeds"`
-	Count int                `json:"count"`
+	Feeds      []xiaohongshu.Feed `json:"feeds"`
+	Count      int                `json:"count"`
+	NextCursor string             `json:"next_cursor,omitempty"`        // 下一页的游标，为空表示没有更多
+	Total      int                `json:"total,omitempty"`              // 分页时过滤后的总数
+	Duplicates int                `json:"duplicates_dropped,omitempty"` // 滚动加载时重复读到、已去掉的笔记数
+	Warnings   []string           `json:"warnings,omitempty"`           // 数据不完整但不影响返回的问题
+
+	dropped int // 缺少笔记ID或 xsec_token 被丢弃的条目数，分页时据此重新生成警告
+}
+
+// noFeedsMessage 列表、搜索成功但没有结果时的说明，用于和调用失败区分
+const noFeedsMessage = "没有找到符合条件的笔记"
+
+// UserFeedsResponse 用户笔记列表响应
+type UserFeedsResponse struct {
+	UserID     string             `json:"userId"`
+	Feeds      []xiaohongshu.Feed `json:"feeds"`
+	Count      int                `json:"count"`
+	NextCursor string             `json:"next_cursor,omitempty"` // 下一页的游标，为空表示没有更多
+	Truncated  bool               `json:"truncated,omitempty"`   // 滚动次数达到 -max-scrolls 上限，只返回了已加载的部分
+	Warnings   []string           `json:"warnings,omitempty"`    // 数据不完整但不影响返回的问题
+}
+
+// CommentsResponse 笔记评论列表响应
+type CommentsResponse struct {
+	FeedID     string                    `json:"feed_id"`
+	Comments   []xiaohongshu.FeedComment `json:"comments"`
+	Count      int                       `json:"count"`
+	NextCursor string                    `json:"next_cursor,omitempty"` // 下一页的游标，为空表示没有更多
+	Truncated  bool                      `json:"truncated,omitempty"`   // 滚动次数达到 -max-scrolls 上限，只返回了已加载的部分
+	XsecToken  string                    `json:"xsec_token,omitempty"`  // 传入的 xsec_token 已过期时自动刷新得到的新令牌，后续请求应改用它
+}
+
+// CommentTreeResponse 笔记评论树响应，顶层评论分页，回复嵌套在被回复的评论下
+type CommentTreeResponse struct {
+	FeedID     string                    `json:"feed_id"`
+	Comments   []xiaohongshu.CommentNode `json:"comments"`
+	Count      int                       `json:"count"`                 // 本页顶层评论数量
+	NextCursor string                    `json:"next_cursor,omitempty"` // 下一页顶层评论的游标，为空表示没有更多
+	Truncated  bool                      `json:"truncated,omitempty"`   // 滚动次数达到 -max-scrolls 上限，只返回了已加载的部分
+	XsecToken  string                    `json:"xsec_token,omitempty"`  // 传入的 xsec_token 已过期时自动刷新得到的新令牌，后续请求应改用它
+}
+
+// MyProfileResponse 当前账号主页响应
+type MyProfileResponse struct {
+	UserProfileResponse
+	Totals xiaohongshu.ProfileTotals `json:"totals"`
+}
+
+// NoteOwnershipResponse 笔记归属响应
+type NoteOwnershipResponse struct {
+	FeedID string `json:"feed_id"`
+	Owned  bool   `json:"owned"` // 笔记是否属于当前登录账号
+}
+
+// FeedLinkResponse 笔记链接响应
+type FeedLinkResponse struct {
+	FeedID   string   `json:"feed_id"`
+	URL      string   `json:"url"`                 // 笔记网页链接，带 xsec_token
+	ShortURL string   `json:"short_url,omitempty"` // 通过详情页“复制链接”得到的分享短链
+	Warnings []string `json:"warnings,omitempty"`
+}
+
+// FeedTagsResponse 笔记话题标签响应
+type FeedTagsResponse struct {
+	FeedID    string                `json:"feed_id"`
+	Tags      []xiaohongshu.FeedTag `json:"tags"`
+	Count     int                   `json:"count"`
+	XsecToken string                `json:"xsec_token,omitempty"` // 传入的 xsec_token 已过期时自动刷新得到的新令牌，后续请求应改用它
+}
+
+// TopicsResponse 话题列表响应
+type TopicsResponse struct {
+	Topics   []xiaohongshu.Topic `json:"topics"`
+	Count    int                 `json:"count"`
+	Warnings []string            `json:"warnings,omitempty"` // 数据不完整但不影响返回的问题
+}
+
+// NotificationsResponse 通知列表响应
+type NotificationsResponse struct {
+	Category      string                     `json:"category,omitempty"` // 过滤的类别，为空表示全部
+	Notifications []xiaohongshu.Notification `json:"notifications"`
+	Count         int                        `json:"count"`
+}
+
+// DebugPageResponse 调试页面响应
+type DebugPageResponse struct {
+	URL        string `json:"url"` // 加载完成后的页面地址，重定向时与请求地址不同
+	Title      string `json:"title"`
+	HTML       string `json:"html,omitempty"`
+	Screenshot string `json:"screenshot,omitempty"` // PNG 截图的 data URL
 }
 
 // UserProfileResponse 用户主页响应
 type UserProfileResponse struct {
+	UserID        string                         `json:"userId,omitempty"`
 	UserBasicInfo xiaohongshu.UserBasicInfo      `json:"userBasicInfo"`
 	Interactions  []xiaohongshu.UserInteractions `json:"interactions"`
 	Feeds         []xiaohongshu.Feed             `json:"feeds"`
+	Warnings      []string                       `json:"warnings,omitempty"` // 数据不完整但不影响返回的问题
 }
 
 // CheckLoginStatus 检查登录状态
 func (s *XiaohongshuService) CheckLoginStatus(ctx context.Context) (*LoginStatusResponse, error) {
-	b := newBrowser()
+	return retryOnBrowserCrash("check_login_status", func() (*LoginStatusResponse, error) {
+		return s.checkLoginStatus(ctx)
+	})
+}
+
+// checkLoginStatus CheckLoginStatus 的单次执行，浏览器崩溃时由 CheckLoginStatus 重试
+func (s *XiaohongshuService) checkLoginStatus(ctx context.Context) (*LoginStatusResponse, error) {
+	ctx, done := withTimeout(ctx, "check_login_status", false)
+	defer done()
+
+	b, err := s.newBrowser(ctx)
+	if err != nil {
+		return nil, err
+	}
 	defer b.Close()
 
-	page := b.NewPage()
+	page := newPage(b)
 	defer page.Close()
+	defer saveCookies(page)
 
-	loginAction := xiaohongshu.NewLogin(page)
+	loginAction := xiaohongshu.NewLogin(page.Context(ctx))
 
 	isLoggedIn, err := loginAction.CheckLoginStatus(ctx)
 	if err != nil {
-		return nil, err
+		return nil, screenshotOnError(page, err)
 	}
 
 	response := &LoginStatusResponse{
@@ -76,193 +290,1797 @@ func (s *XiaohongshuService) CheckLoginStatus(ctx context.Context) (*LoginStatus
 		Username:   configs.Username,
 	}
 
+	// 账号信息读取失败不影响登录状态的判断
+	if isLoggedIn {
+		if account, err := xiaohongshu.CurrentAccount(page); err != nil {
+			logrus.WithContext(ctx).Warnf("读取当前登录账号信息失败: %v", err)
+		} else {
+			response.UserID = account.UserID
+			response.Nickname = account.Nickname
+			response.Avatar = account.Avatar
+		}
+	}
+
 	return response, nil
 }
 
-// PublishContent 发布内容
-func (s *XiaohongshuService) PublishContent(ctx context.Context, req *PublishRequest) (*PublishResponse, error) {
+// ErrInvalidArgs 发布、编辑等写操作的参数无效，在启动浏览器之前返回
+var ErrInvalidArgs = errors.New("参数无效")
+
+// validatePublishRequest 校验发布请求，返回规范化后的话题标签。
+// 校验失败的错误包装 ErrInvalidArgs
+func validatePublishRequest(req *PublishRequest) ([]string, error) {
 	// 验证标题长度
 	// 小红书限制：最大40个单位长度
 	// 中文/日文/韩文占2个单位，英文/数字占1个单位
 	if titleWidth := runewidth.StringWidth(req.Title); titleWidth > 40 {
-		return nil, fmt.Errorf("标题长度超过限制")
+		return nil, fmt.Errorf("%w: 标题长度超过限制", ErrInvalidArgs)
+	}
+
+	if err := checkContentWidth(req.Content); err != nil {
+		return nil, err
+	}
+
+	// 规范化话题标签
+	tags, err := normalizeTags(req.Tags)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrInvalidArgs, err)
+	}
+
+	// 小红书不支持纯文字笔记，至少需要一张图片
+	if len(req.Images) == 0 {
+		return nil, fmt.Errorf("%w: 至少需要一张图片", ErrInvalidArgs)
+	}
+	if err := checkImageCount(req.Images); err != nil {
+		return nil, err
+	}
+	for i, image := range req.Images {
+		if err := validateImageSource(image); err != nil {
+			return nil, fmt.Errorf("%w: 第 %d 张图片: %w", ErrInvalidArgs, i+1, err)
+		}
+	}
+
+	if req.CoverIndex < 0 || req.CoverIndex >= len(req.Images) {
+		return nil, fmt.Errorf("%w: cover_index 超出图片范围，共 %d 张图片，序号应为 0 到 %d", ErrInvalidArgs, len(req.Images), len(req.Images)-1)
+	}
+
+	if len(req.ImageAlts) > 0 && len(req.ImageAlts) != len(req.Images) {
+		return nil, fmt.Errorf("%w: image_alts 数量与图片数量不一致，提供了 %d 条描述、%d 张图片", ErrInvalidArgs, len(req.ImageAlts), len(req.Images))
+	}
+
+	if _, err := xiaohongshu.ParseVisibility(req.Visibility); err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrInvalidArgs, err)
+	}
+
+	return tags, nil
+}
+
+// validateEditFeedRequest 校验编辑请求，返回规范化后的话题标签。
+// 校验失败的错误包装 ErrInvalidArgs
+func validateEditFeedRequest(updates *EditFeedRequest) ([]string, error) {
+	if !updates.HasUpdates() {
+		return nil, fmt.Errorf("%w: 至少需要修改标题、正文、话题或图片中的一项", ErrInvalidArgs)
+	}
+
+	if updates.Title != nil {
+		if titleWidth := runewidth.StringWidth(*updates.Title); titleWidth > 40 {
+			return nil, fmt.Errorf("%w: 标题长度超过限制", ErrInvalidArgs)
+		}
+	}
+	if updates.Content != nil {
+		if err := checkContentWidth(*updates.Content); err != nil {
+			return nil, err
+		}
+	}
+
+	tags, err := normalizeTags(updates.Tags)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrInvalidArgs, err)
+	}
+
+	if err := checkImageCount(updates.Images); err != nil {
+		return nil, err
+	}
+
+	return tags, nil
+}
+
+// warnImageAltsIgnored 提供了图片描述时返回的警告，小红书网页版发布页没有为单张图片填写描述的入口
+const warnImageAltsIgnored = "小红书发布页不支持图片描述（替代文字），image_alts 已忽略"
+
+// imageAltsWarnings 提供了非空的图片描述时返回忽略描述的警告
+func imageAltsWarnings(alts []string) []string {
+	for _, alt := range alts {
+		if strings.TrimSpace(alt) != "" {
+			return []string{warnImageAltsIgnored}
+		}
+	}
+	return nil
+}
+
+// checkImageCount 检查图片数量是否超过单篇笔记的上限，超出时返回包装 ErrInvalidArgs 的错误
+func checkImageCount(images []string) error {
+	if maxImages := configs.GetMaxImages(); len(images) > maxImages {
+		return fmt.Errorf("%w: 图片数量超过限制，提供了 %d 张，最多 %d 张", ErrInvalidArgs, len(images), maxImages)
+	}
+	return nil
+}
+
+// checkContentWidth 检查正文宽度是否超过 -max-content-width，超过时返回包装了 ErrInvalidArgs 的错误，并给出实际宽度
+func checkContentWidth(content string) error {
+	if width, maxWidth := runewidth.StringWidth(content), configs.GetMaxContentWidth(); width > maxWidth {
+		return fmt.Errorf("%w: 正文长度超过限制，宽度为 %d，最多 %d（中文字计 2，英文字母、数字计 1）", ErrInvalidArgs, width, maxWidth)
+	}
+	return nil
+}
+
+// validateImageSource 检查图片来源是否可用：链接需为合法的 HTTP/HTTPS 地址，本地路径需为存在的文件。
+// data URL 的内容在解码时校验，链接能否下载在处理图片时校验
+func validateImageSource(image string) error {
+	image = strings.TrimSpace(image)
+	switch {
+	case image == "":
+		return fmt.Errorf("图片路径为空")
+	case downloader.IsDataURL(image):
+		return nil
+	case isImageURL(image):
+		if u, err := url.Parse(image); err != nil || u.Host == "" {
+			return fmt.Errorf("无效的图片链接: %s", image)
+		}
+		return nil
+	}
+
+	info, err := os.Stat(image)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return fmt.Errorf("图片文件不存在: %s", image)
+		}
+		return fmt.Errorf("无法读取图片文件: %s: %w", image, err)
 	}
+	if !info.Mode().IsRegular() {
+		return fmt.Errorf("图片路径不是文件: %s", image)
+	}
+	return nil
+}
+
+// PublishContent 发布内容
+func (s *XiaohongshuService) PublishContent(ctx context.Context, req *PublishRequest) (_ *PublishResponse, err error) {
+	start := time.Now()
+	defer func() {
+		s.audit.Record(configs.Username, "publish_content", map[string]any{
+			"title":       req.Title,
+			"tags":        req.Tags,
+			"images":      len(req.Images),
+			"cover_index": req.CoverIndex,
+			"visibility":  req.Visibility,
+		}, start, err)
+	}()
 
-	// 处理图片：下载URL图片或使用本地路径
-	imagePaths, err := s.processImages(req.Images)
+	ctx, done := withTimeout(ctx, "publish_content", true)
+	defer done()
+
+	tags, err := validatePublishRequest(req)
 	if err != nil {
 		return nil, err
 	}
 
-	// 构建发布内容
+	// 处理图片：下载URL图片或使用本地路径。图片无法解析时不启动浏览器，直接返回参数错误
+	imagePaths, cleanup, err := s.processImages(ctx, req.Images, req.imageHeaders())
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrInvalidArgs, err)
+	}
+	defer cleanup()
+
+	// 构建发布内容，小红书以第一张上传的图片为封面
 	content := xiaohongshu.PublishImageContent{
 		Title:      req.Title,
 		Content:    req.Content,
-		Tags:       req.Tags,
-		ImagePaths: imagePaths,
+		Tags:       tags,
+		ImagePaths: coverFirst(imagePaths, req.CoverIndex),
 	}
 
 	// 执行发布
-	if err := s.publishContent(ctx, content); err != nil {
+	postID, warnings, err := s.publishContent(ctx, content)
+
+	// 发布后确认笔记是否真的发出：报错但已发出时按成功返回，避免调用方重试造成重复发布
+	var verification *PublishVerification
+	if configs.IsPublishVerify() && publishMayHaveLanded(err) {
+		var verifyErr error
+		verification, verifyErr = s.verifyPublished(ctx, req.Title, start)
+		switch {
+		case verifyErr != nil:
+			logrus.WithContext(ctx).Warnf("发布后确认笔记失败: %v", verifyErr)
+			if err == nil {
+				warnings = append(warnings, "发布后确认笔记失败: "+verifyErr.Error())
+			}
+		case err != nil && verification.Landed:
+			logrus.WithContext(ctx).Warnf("发布过程中出错，但已在创作者中心找到笔记 %s: %v", verification.FeedID, err)
+			warnings = append(warnings, "发布过程中出错，但已确认笔记发布成功: "+err.Error())
+			err = nil
+		case err != nil:
+			err = fmt.Errorf("%w（已确认笔记没有发布，可以重试）", err)
+		case !verification.Landed:
+			warnings = append(warnings, warnPublishNotFound)
+		}
+	}
+	if err != nil {
 		return nil, err
 	}
+	if verification != nil && verification.FeedID != "" {
+		postID = verification.FeedID
+	}
+
+	// 笔记按公开可见发出，指定了其他可见范围时再修改，失败时只返回警告
+	visibility, _ := xiaohongshu.ParseVisibility(req.Visibility)
+	if visibility != xiaohongshu.VisibilityPublic {
+		var visibilityErr error
+		postID, visibilityErr = s.applyPublishVisibility(ctx, postID, req.Title, start, visibility)
+		if visibilityErr != nil {
+			logrus.WithContext(ctx).Warnf("发布后设置可见范围 %s 失败: %v", visibility, visibilityErr)
+			warnings = append(warnings, visibilityNotAppliedWarning(visibility, visibilityErr))
+			visibility = xiaohongshu.VisibilityPublic
+		}
+	}
 
 	response := &PublishResponse{
-		Title:   req.Title,
-		Content: req.Content,
-		Images:  len(imagePaths),
-		Status:  "发布完成",
+		Title:        req.Title,
+		Content:      req.Content,
+		Images:       len(imagePaths),
+		Status:       "发布完成",
+		PostID:       postID,
+		Warnings:     append(imageAltsWarnings(req.ImageAlts), warnings...),
+		Visibility:   string(visibility),
+		Verification: verification,
 	}
 
 	return response, nil
 }
 
-// processImages 处理图片列表，支持URL下载和本地路径
-func (s *XiaohongshuService) processImages(images []string) ([]string, error) {
-	processor := downloader.NewImageProcessor()
-	return processor.ProcessImages(images)
+// coverFirst 把序号为 coverIndex 的图片移到第一张，其余图片保持原有顺序
+func coverFirst(paths []string, coverIndex int) []string {
+	if coverIndex <= 0 || coverIndex >= len(paths) {
+		return paths
+	}
+
+	ordered := make([]string, 0, len(paths))
+	ordered = append(ordered, paths[coverIndex])
+	ordered = append(ordered, paths[:coverIndex]...)
+	return append(ordered, paths[coverIndex+1:]...)
+}
+
+// maxTagsPerNote 小红书单篇笔记最多可添加的话题数
+const maxTagsPerNote = 10
+
+// normalizeTags 规范化话题标签：去掉开头的 # 和首尾空白，丢弃空标签，并忽略大小写去重。
+// 超过单篇笔记的话题数上限时返回错误，并列出被拒绝的标签。
+func normalizeTags(tags []string) ([]string, error) {
+	if tags == nil {
+		return nil, nil
+	}
+
+	normalized := make([]string, 0, len(tags))
+	seen := make(map[string]bool, len(tags))
+	for _, tag := range tags {
+		tag = strings.TrimSpace(strings.TrimLeft(strings.TrimSpace(tag), "#＃"))
+		if tag == "" {
+			continue
+		}
+
+		key := strings.ToLower(tag)
+		if seen[key] {
+			continue
+		}
+		seen[key] = true
+
+		normalized = append(normalized, tag)
+	}
+
+	if len(normalized) > maxTagsPerNote {
+		return nil, fmt.Errorf("话题标签最多%d个，以下标签超出限制被拒绝: %s",
+			maxTagsPerNote, strings.Join(normalized[maxTagsPerNote:], ", "))
+	}
+
+	return normalized, nil
 }
 
-// publishContent 执行内容发布
-func (s *XiaohongshuService) publishContent(ctx context.Context, content xiaohongshu.PublishImageContent) error {
-	b := newBrowser()
-	defer b.Close()
+// processImages 处理图片列表，支持URL下载、本地路径和 base64 data URL。
+// URL 图片按 -image-download-workers 并发下载，每张受 -image-download-timeout 限制，
+// headers 不为空时带上这些请求头下载，用于有防盗链或需要鉴权的图床。
+// 返回的 cleanup 删除处理过程中生成的临时文件（下载的图片和解码的 data URL），
+// 需在上传完成后调用；用户提供的本地图片不会被删除
+func (s *XiaohongshuService) processImages(ctx context.Context, images []string, headers map[string]string) ([]string, func(), error) {
+	var tempFiles []string
+	cleanup := func() {
+		for _, path := range tempFiles {
+			if err := os.Remove(path); err != nil && !os.IsNotExist(err) {
+				logrus.WithContext(ctx).Warnf("删除临时图片失败: %s: %v", path, err)
+			}
+		}
+	}
 
-	page := b.NewPage()
-	defer page.Close()
+	resolved := slices.Clone(images)
+
+	// URL 图片并发下载，单张超时或失败不阻塞其他图片，所有失败一起返回
+	var urlIndexes []int
+	var urls []string
+	for i, image := range images {
+		if isImageURL(image) {
+			urlIndexes = append(urlIndexes, i)
+			urls = append(urls, image)
+		}
+	}
+	results := downloader.DownloadImages(ctx, urls, downloader.DownloadOptions{
+		Headers: headers,
+		Workers: configs.GetImageDownloadWorkers(),
+		Timeout: configs.GetImageDownloadTimeout(),
+	})
+	var errs []error
+	for j, result := range results {
+		i := urlIndexes[j]
+		if result.Err != nil {
+			errs = append(errs, fmt.Errorf("第 %d 张图片 %s: %w", i+1, result.URL, result.Err))
+			continue
+		}
+		tempFiles = append(tempFiles, result.Path)
+		resolved[i] = result.Path
+	}
+	if len(errs) > 0 {
+		cleanup()
+		return nil, nil, errors.Join(errs...)
+	}
+
+	// data URL 先保存为临时文件，再与本地图片一起交给 ImageProcessor
+	for i, image := range resolved {
+		if !downloader.IsDataURL(image) {
+			continue
+		}
+		path, err := downloader.SaveDataURLImage(image)
+		if err != nil {
+			cleanup()
+			return nil, nil, fmt.Errorf("第 %d 张图片: %w", i+1, err)
+		}
+		tempFiles = append(tempFiles, path)
+		resolved[i] = path
+	}
 
-	action, err := xiaohongshu.NewPublishImageAction(page)
+	imagePaths, err := s.images.ProcessImages(resolved)
 	if err != nil {
-		return err
+		cleanup()
+		return nil, nil, err
 	}
 
-	// 执行发布
-	return action.Publish(ctx, content)
+	return imagePaths, cleanup, nil
 }
 
-// ListFeeds 获取Feeds列表
-func (s *XiaohongshuService) ListFeeds(ctx context.Context) (*FeedsListResponse, error) {
-	b := newBrowser()
+// imageProcessor 发布前处理图片的接口，由 downloader.ImageProcessor 实现
+type imageProcessor interface {
+	ProcessImages(images []string) ([]string, error)
+}
+
+// isImageURL 判断图片是否为需要下载的 HTTP/HTTPS 链接
+func isImageURL(image string) bool {
+	return strings.HasPrefix(image, "http://") || strings.HasPrefix(image, "https://")
+}
+
+// publishContent 执行内容发布，返回确认发布成功时取到的笔记ID（可能为空）和发布过程中的非致命警告
+func (s *XiaohongshuService) publishContent(ctx context.Context, content xiaohongshu.PublishImageContent) (string, []string, error) {
+	release, err := s.acquireWrite(ctx)
+	if err != nil {
+		return "", nil, err
+	}
+	defer release()
+
+	b, err := s.newBrowser(ctx)
+	if err != nil {
+		return "", nil, err
+	}
 	defer b.Close()
 
-	page := b.NewPage()
+	page := newPage(b)
 	defer page.Close()
+	defer saveCookies(page)
 
-	// 创建 Feeds 列表 action
-	action := xiaohongshu.NewFeedsListAction(page)
+	// 第一张图片为封面，小红书封面必须为静态图片，封面为动图时换成第一帧
+	var warnings []string
+	coverPath, warning, tempCover, err := xiaohongshu.PrepareCover(page.Browser(), content.ImagePaths[0])
+	if err != nil {
+		return "", nil, err
+	}
+	if tempCover {
+		defer os.Remove(coverPath)
+		content.ImagePaths = slices.Clone(content.ImagePaths)
+		content.ImagePaths[0] = coverPath
+		warnings = append(warnings, warning)
+	}
 
-	// 获取 Feeds 列表
-	feeds, err := action.GetFeedsList(ctx)
+	action, err := xiaohongshu.NewPublishImageAction(page.Context(ctx))
 	if err != nil {
-		return nil, err
+		return "", nil, screenshotOnError(page, s.writeError(page, xiaohongshu.ClassifyPublishError(page, err)))
 	}
 
-	response := &FeedsListResponse{
-		Feeds: feeds,
-		Count: len(feeds),
+	// 执行发布，失败时根据页面提示区分未登录、上传失败、内容被拦截等原因
+	if err := action.Publish(ctx, content); err != nil {
+		return "", nil, screenshotOnError(page, s.writeError(page, xiaohongshu.ClassifyPublishError(page, err)))
 	}
 
-	return response, nil
-}
+	// 提交后页面仍可能提示内容被拦截
+	if err := xiaohongshu.CheckPublishResult(page); err != nil {
+		return "", nil, screenshotOnError(page, err)
+	}
 
-func (s *XiaohongshuService) SearchFeeds(ctx context.Context, keyword string) (*FeedsListResponse, error) {
-	b := newBrowser()
-	defer b.Close()
+	// 以 -publish-confirm-timeout 启动时，等到页面确认发布成功才返回
+	var postID string
+	if timeout := configs.GetPublishConfirmTimeout(); timeout > 0 {
+		if postID, err = xiaohongshu.WaitPublishConfirmed(ctx, page, timeout); err != nil {
+			return "", nil, screenshotOnError(page, err)
+		}
+	}
 
-	page := b.NewPage()
-	defer page.Close()
+	return postID, warnings, nil
+}
+
+// EditFeed 编辑已发布的笔记，只修改提供了的字段，返回修改后的笔记详情
+func (s *XiaohongshuService) EditFeed(ctx context.Context, feedID, xsecToken string, updates EditFeedRequest) (_ *FeedDetailResponse, err error) {
+	start := time.Now()
+	defer func() {
+		args := map[string]any{"feed_id": feedID}
+		if updates.Title != nil {
+			args["title"] = *updates.Title
+		}
+		if updates.Content != nil {
+			args["content_length"] = len([]rune(*updates.Content))
+		}
+		if updates.Tags != nil {
+			args["tags"] = updates.Tags
+		}
+		if updates.Images != nil {
+			args["images"] = len(updates.Images)
+		}
+		s.audit.Record(configs.Username, "edit_feed", args, start, err)
+	}()
 
-	action := xiaohongshu.NewSearchAction(page)
+	ctx, done := withTimeout(ctx, "edit_feed", true)
+	defer done()
 
-	feeds, err := action.Search(ctx, keyword)
+	tags, err := validateEditFeedRequest(&updates)
 	if err != nil {
 		return nil, err
 	}
 
-	response := &FeedsListResponse{
-		Feeds: feeds,
-		Count: len(feeds),
+	content := xiaohongshu.EditFeedContent{
+		Title:   updates.Title,
+		Content: updates.Content,
+		Tags:    tags,
 	}
 
-	return response, nil
-}
+	if updates.Images != nil {
+		// 图片无法解析时不启动浏览器，与发布一样按参数错误返回
+		imagePaths, cleanup, err := s.processImages(ctx, updates.Images, nil)
+		if err != nil {
+			return nil, fmt.Errorf("%w: %w", ErrInvalidArgs, err)
+		}
+		defer cleanup()
+		content.ImagePaths = imagePaths
+	}
 
-// GetFeedDetail 获取Feed详情
-func (s *XiaohongshuService) GetFeedDetail(ctx context.Context, feedID, xsecToken string) (*FeedDetailResponse, error) {
-	b := newBrowser()
+	release, err := s.acquireWrite(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer release()
+
+	b, err := s.newBrowser(ctx)
+	if err != nil {
+		return nil, err
+	}
 	defer b.Close()
 
-	page := b.NewPage()
+	page := newPage(b)
 	defer page.Close()
+	defer saveCookies(page)
 
-	// 创建 Feed 详情 action
-	action := xiaohongshu.NewFeedDetailAction(page)
+	if err := xiaohongshu.NewEditFeedAction(page.Context(ctx)).EditFeed(ctx, feedID, content); err != nil {
+		return nil, screenshotOnError(page, s.writeError(page, err))
+	}
+	s.detailCache.Delete(feedID)
 
-	// 获取 Feed 详情
-	result, err := action.GetFeedDetail(ctx, feedID, xsecToken)
+	// 重新获取笔记详情，便于调用方确认修改结果
+	result, err := xiaohongshu.NewFeedDetailAction(page.Context(ctx)).GetFeedDetail(ctx, feedID, xsecToken)
 	if err != nil {
-		return nil, err
+		return nil, screenshotOnError(page, err)
 	}
 
 	response := &FeedDetailResponse{
-		FeedID: feedID,
-		Data:   result,
+		FeedID:   feedID,
+		Data:     result,
+		Warnings: xiaohongshu.FeedDetailWarnings(result),
 	}
+	s.detailCache.Set(feedID, response)
 
 	return response, nil
 }
 
-// UserProfile 获取用户信息
-func (s *XiaohongshuService) UserProfile(ctx context.Context, userID, xsecToken string) (*UserProfileResponse, error) {
-	b := newBrowser()
+// ListFeeds 获取Feeds列表
+func (s *XiaohongshuService) ListFeeds(ctx context.Context) (*FeedsListResponse, error) {
+	result, err := retryOnBrowserCrash("list_feeds", func() (*FeedsListResponse, error) {
+		return s.listFeeds(ctx)
+	})
+	if err == nil {
+		s.recordFeedSources(result.Feeds, feedTokenSource{Tool: "list_feeds"})
+	}
+	return result, err
+}
+
+// listFeeds ListFeeds 的单次执行，浏览器崩溃时由 ListFeeds 重试
+func (s *XiaohongshuService) listFeeds(ctx context.Context) (*FeedsListResponse, error) {
+	ctx, done := withTimeout(ctx, "list_feeds", false)
+	defer done()
+
+	b, err := s.newBrowser(ctx)
+	if err != nil {
+		return nil, err
+	}
 	defer b.Close()
 
-	page := b.NewPage()
+	page := newPage(b)
 	defer page.Close()
+	defer saveCookies(page)
+
+	// 创建 Feeds 列表 action
+	action := xiaohongshu.NewFeedsListAction(page.Context(ctx))
+
+	// 获取 Feeds 列表
+	var feeds []xiaohongshu.Feed
+	err = retryOnCaptcha(ctx, page, func() (err error) {
+		feeds, err = action.GetFeedsList(ctx)
+		return err
+	})
+	if err = checkBlockedPage(page, err, len(feeds) == 0); err != nil {
+		return nil, screenshotOnError(page, err)
+	}
+
+	return newFeedsListResponse("list_feeds", feeds), nil
+}
 
-	action := xiaohongshu.NewUserProfileAction(page)
+// ListFeedsPage 分页获取Feeds列表，可按笔记类型过滤。
+// 首页推荐每次加载都会变化，游标记录上一页最后一条笔记，能找到时从它之后继续，否则按偏移量分页
+func (s *XiaohongshuService) ListFeedsPage(ctx context.Context, query ListFeedsQuery) (*FeedsListResponse, error) {
+	return listFeedsPage(ctx, s.ListFeeds, query, xiaohongshu.FeedsCursorScope(query.NoteType))
+}
 
-	result, err := action.UserProfile(ctx, userID, xsecToken)
+// listFeedsPage 对 listFeeds 返回的完整列表做过滤和分页，真实服务和 mock 服务共用。
+// scope 为游标所属的列表，见 xiaohongshu.Cursor
+func listFeedsPage(ctx context.Context, listFeeds func(context.Context) (*FeedsListResponse, error), query ListFeedsQuery, scope string) (*FeedsListResponse, error) {
+	cursor, err := xiaohongshu.DecodeCursor(query.Cursor, scope)
 	if err != nil {
 		return nil, err
 	}
-	response := &UserProfileResponse{
-		UserBasicInfo: result.UserBasicInfo,
-		Interactions:  result.Interactions,
-		Feeds:         result.Feeds,
+
+	result, err := listFeeds(ctx)
+	if err != nil {
+		return nil, err
 	}
 
-	return response, nil
+	// 没有结果时返回空列表而不是 null，调用方可以直接按 count 为 0 处理
+	feeds := result.Feeds
+	if feeds == nil {
+		feeds = []xiaohongshu.Feed{}
+	}
+	if query.NoteType != "" {
+		filtered := make([]xiaohongshu.Feed, 0, len(feeds))
+		for _, feed := range feeds {
+			if feed.NoteCard.Type == query.NoteType {
+				filtered = append(filtered, feed)
+			}
+		}
+		feeds = filtered
+	}
+
+	total := len(feeds)
+	offset := 0
+	if query.Cursor != "" {
+		offset = cursor.ResumeOffset(xiaohongshu.FeedIDs(feeds))
+	}
+	if query.Limit == 0 && offset == 0 {
+		return &FeedsListResponse{
+			Feeds:      feeds,
+			Count:      total,
+			Total:      total,
+			Duplicates: result.Duplicates,
+			Warnings:   feedsListWarnings(feeds, result.dropped),
+			dropped:    result.dropped,
+		}, nil
+	}
+
+	end := total
+	if query.Limit > 0 && offset+query.Limit < total {
+		end = offset + query.Limit
+	}
+	offset = min(offset, total)
+
+	response := &FeedsListResponse{
+		Feeds:      feeds[offset:end],
+		Count:      end - offset,
+		Total:      total,
+		Duplicates: result.Duplicates,
+		Warnings:   feedsListWarnings(feeds[offset:end], result.dropped),
+		dropped:    result.dropped,
+	}
+	if end < total {
+		response.NextCursor = xiaohongshu.NextCursor(scope, end, feeds[end-1].ID)
+	}
 
+	return response, nil
 }
 
-// PostCommentToFeed 发表评论到Feed
-func (s *XiaohongshuService) PostCommentToFeed(ctx context.Context, feedID, xsecToken, content string) (*PostCommentResponse, error) {
-	// 使用非无头模式以便查看操作过程
-	b := newBrowser()
-	defer b.Close()
+// SearchFeeds 搜索Feeds
+func (s *XiaohongshuService) SearchFeeds(ctx context.Context, keyword string) (*FeedsListResponse, error) {
+	if cached, ok := s.searchCache.Get(ctx, keyword); ok {
+		return cached, nil
+	}
 
-	page := b.NewPage()
-	defer page.Close()
+	result, err := retryOnBrowserCrash("search_feeds", func() (*FeedsListResponse, error) {
+		return s.searchFeeds(ctx, keyword)
+	})
+	if err == nil {
+		s.searchCache.Set(keyword, result)
+		s.recordFeedSources(result.Feeds, feedTokenSource{Tool: "search_feeds", Keyword: keyword})
+	}
+	return result, err
+}
 
-	// 创建 Feed 评论 action
-	action := xiaohongshu.NewCommentFeedAction(page)
+// searchFeeds SearchFeeds 的单次执行，浏览器崩溃时由 SearchFeeds 重试
+func (s *XiaohongshuService) searchFeeds(ctx context.Context, keyword string) (*FeedsListResponse, error) {
+	ctx, done := withTimeout(ctx, "search_feeds", false)
+	defer done()
 
-	// 发表评论
-	err := action.PostComment(ctx, feedID, xsecToken, content)
+	b, err := s.newBrowser(ctx)
 	if err != nil {
 		return nil, err
 	}
+	defer b.Close()
 
-	response := &PostCommentResponse{
-		FeedID:  feedID,
-		Success: true,
-		Message: "评论发表成功",
+	page := newPage(b)
+	defer page.Close()
+	defer saveCookies(page)
+
+	action := xiaohongshu.NewSearchAction(page.Context(ctx))
+
+	var feeds []xiaohongshu.Feed
+	err = retryOnCaptcha(ctx, page, func() (err error) {
+		feeds, err = action.Search(ctx, keyword)
+		return err
+	})
+	if err = checkBlockedPage(page, err, len(feeds) == 0); err != nil {
+		return nil, screenshotOnError(page, err)
 	}
 
-	return response, nil
+	return newFeedsListResponse("search_feeds", feeds), nil
 }
 
-func newBrowser() *headless_browser.Browser {
-	return browser.NewBrowser(configs.IsHeadless(), browser.WithBinPath(configs.GetBinPath()))
+// newFeedsListResponse 构建列表、搜索结果响应。缺少笔记ID或 xsec_token 的条目无法用于获取详情和评论，
+// 直接丢弃，并在警告中说明丢弃的数量
+func newFeedsListResponse(name string, feeds []xiaohongshu.Feed) *FeedsListResponse {
+	feeds, dropped := xiaohongshu.SanitizeFeeds(feeds)
+	// 规范化笔记ID之后再去重，同一篇笔记的不同写法（如详情页路径）也能合并
+	feeds, duplicates := xiaohongshu.DedupeFeeds(feeds)
+
+	if dropped > 0 {
+		logrus.Warnf("%s: 丢弃 %d 条缺少笔记ID或 xsec_token 的结果", name, dropped)
+	}
+	if duplicates > 0 {
+		logrus.Infof("%s: 去掉 %d 条重复的笔记", name, duplicates)
+	}
+
+	return &FeedsListResponse{
+		Feeds:      feeds,
+		Count:      len(feeds),
+		Duplicates: duplicates,
+		Warnings:   feedsListWarnings(feeds, dropped),
+		dropped:    dropped,
+	}
+}
+
+// feedsListWarnings 生成列表响应的警告：feeds 中数据不完整的笔记，以及丢弃的条目数
+func feedsListWarnings(feeds []xiaohongshu.Feed, dropped int) []string {
+	warnings := xiaohongshu.FeedWarnings(feeds)
+	if dropped > 0 {
+		warnings = append(warnings, fmt.Sprintf("已丢弃 %d 条缺少笔记ID或 xsec_token 的结果", dropped))
+	}
+	return warnings
+}
+
+// SearchTopics 搜索话题，返回话题名称及浏览量
+func (s *XiaohongshuService) SearchTopics(ctx context.Context, keyword string) (*TopicsResponse, error) {
+	return retryOnBrowserCrash("search_topics", func() (*TopicsResponse, error) {
+		return s.searchTopics(ctx, keyword)
+	})
+}
+
+// searchTopics SearchTopics 的单次执行，浏览器崩溃时由 SearchTopics 重试
+func (s *XiaohongshuService) searchTopics(ctx context.Context, keyword string) (*TopicsResponse, error) {
+	ctx, done := withTimeout(ctx, "search_topics", false)
+	defer done()
+
+	b, err := s.newBrowser(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer b.Close()
+
+	page := newPage(b)
+	defer page.Close()
+	defer saveCookies(page)
+
+	action := xiaohongshu.NewTopicSearchAction(page.Context(ctx))
+
+	topics, err := action.Search(ctx, keyword)
+	if err != nil {
+		return nil, screenshotOnError(page, err)
+	}
+
+	response := &TopicsResponse{
+		Topics:   topics,
+		Count:    len(topics),
+		Warnings: xiaohongshu.TopicWarnings(topics),
+	}
+
+	return response, nil
+}
+
+// TrendingTopics 获取当前热门话题
+func (s *XiaohongshuService) TrendingTopics(ctx context.Context) (*TopicsResponse, error) {
+	return retryOnBrowserCrash("trending_topics", func() (*TopicsResponse, error) {
+		return s.trendingTopics(ctx)
+	})
+}
+
+// trendingTopics TrendingTopics 的单次执行，浏览器崩溃时由 TrendingTopics 重试
+func (s *XiaohongshuService) trendingTopics(ctx context.Context) (*TopicsResponse, error) {
+	ctx, done := withTimeout(ctx, "trending_topics", false)
+	defer done()
+
+	b, err := s.newBrowser(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer b.Close()
+
+	page := newPage(b)
+	defer page.Close()
+	defer saveCookies(page)
+
+	action := xiaohongshu.NewTopicSearchAction(page.Context(ctx))
+
+	topics, err := action.Trending(ctx)
+	if err != nil {
+		return nil, screenshotOnError(page, err)
+	}
+
+	response := &TopicsResponse{
+		Topics:   topics,
+		Count:    len(topics),
+		Warnings: xiaohongshu.TopicWarnings(topics),
+	}
+
+	return response, nil
+}
+
+// ListNotifications 获取通知中心最近的通知，可按类别过滤
+func (s *XiaohongshuService) ListNotifications(ctx context.Context, category string) (*NotificationsResponse, error) {
+	if !xiaohongshu.ValidNotificationCategory(category) {
+		return nil, fmt.Errorf("不支持的通知类别: %s，只支持 likes、comments、mentions、follows", category)
+	}
+
+	return retryOnBrowserCrash("list_notifications", func() (*NotificationsResponse, error) {
+		return s.listNotifications(ctx, category)
+	})
+}
+
+// listNotifications ListNotifications 的单次执行，浏览器崩溃时由 ListNotifications 重试
+func (s *XiaohongshuService) listNotifications(ctx context.Context, category string) (*NotificationsResponse, error) {
+	ctx, done := withTimeout(ctx, "list_notifications", false)
+	defer done()
+
+	b, err := s.newBrowser(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer b.Close()
+
+	page := newPage(b)
+	defer page.Close()
+	defer saveCookies(page)
+
+	action := xiaohongshu.NewNotificationsAction(page.Context(ctx))
+
+	var notifications []xiaohongshu.Notification
+	err = retryOnCaptcha(ctx, page, func() (err error) {
+		notifications, err = action.ListNotifications(ctx, category)
+		return err
+	})
+	if err = checkBlockedPage(page, err, false); err != nil {
+		if errors.Is(err, xiaohongshu.ErrNotLoggedIn) {
+			return nil, err
+		}
+		return nil, screenshotOnError(page, err)
+	}
+
+	response := &NotificationsResponse{
+		Category:      category,
+		Notifications: notifications,
+		Count:         len(notifications),
+	}
+
+	return response, nil
+}
+
+// GetFeedDetail 获取Feed详情
+func (s *XiaohongshuService) GetFeedDetail(ctx context.Context, feedID, xsecToken string) (*FeedDetailResponse, error) {
+	if cached, ok := s.detailCache.Get(ctx, feedID); ok {
+		return cached, nil
+	}
+
+	result, err := retryOnTokenExpired(ctx, s, feedID, xsecToken, func(token string) (*FeedDetailResponse, error) {
+		result, err := retryOnBrowserCrash("get_feed_detail", func() (*FeedDetailResponse, error) {
+			return s.getFeedDetail(ctx, feedID, token)
+		})
+		if err == nil && token != xsecToken {
+			result.XsecToken = token
+		}
+		return result, err
+	})
+	if err == nil {
+		// 刷新得到的令牌只返回给本次调用方，缓存中不保留
+		cached := *result
+		cached.XsecToken = ""
+		s.detailCache.Set(feedID, &cached)
+	}
+	return result, err
+}
+
+// getFeedDetail GetFeedDetail 的单次执行，浏览器崩溃时由 GetFeedDetail 重试
+func (s *XiaohongshuService) getFeedDetail(ctx context.Context, feedID, xsecToken string) (*FeedDetailResponse, error) {
+	ctx, done := withTimeout(ctx, "get_feed_detail", false)
+	defer done()
+
+	b, err := s.newBrowser(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer b.Close()
+
+	page := newPage(b)
+	defer page.Close()
+	defer saveCookies(page)
+
+	// 创建 Feed 详情 action
+	action := xiaohongshu.NewFeedDetailAction(page.Context(ctx))
+
+	response, err := feedDetailOnPage(ctx, page, action, feedID, xsecToken)
+	if err != nil {
+		return nil, screenshotOnError(page, err)
+	}
+
+	return response, nil
+}
+
+// feedDetailOnPage 在已打开的浏览器页面中获取笔记详情和视频地址，单篇详情和 SearchAndDetail 共用
+func feedDetailOnPage(ctx context.Context, page *rod.Page, action *xiaohongshu.FeedDetailAction, feedID, xsecToken string) (*FeedDetailResponse, error) {
+	// 获取 Feed 详情
+	var result any
+	err := retryOnCaptcha(ctx, page, func() (err error) {
+		result, err = action.GetFeedDetail(ctx, feedID, xsecToken)
+		return err
+	})
+	if err = checkBlockedPage(page, err, result == nil); err != nil {
+		return nil, err
+	}
+
+	response := &FeedDetailResponse{
+		FeedID:   feedID,
+		Data:     result,
+		Warnings: xiaohongshu.FeedDetailWarnings(result),
+	}
+
+	// 视频地址读取失败不影响笔记详情
+	video, err := xiaohongshu.GetFeedVideo(page, feedID)
+	if err != nil {
+		logrus.WithContext(ctx).Warnf("获取笔记 %s 的视频地址失败: %v", feedID, err)
+	} else if video != nil {
+		response.VideoURL = video.URL
+		response.VideoBackupURLs = video.BackupURLs
+		response.VideoManifestURL = video.ManifestURL
+	}
+
+	return response, nil
+}
+
+// ListComments 分页获取笔记评论
+func (s *XiaohongshuService) ListComments(ctx context.Context, feedID, xsecToken string, limit int, cursor string) (*CommentsResponse, error) {
+	return retryOnTokenExpired(ctx, s, feedID, xsecToken, func(token string) (*CommentsResponse, error) {
+		result, err := retryOnBrowserCrash("list_comments", func() (*CommentsResponse, error) {
+			return s.listComments(ctx, feedID, token, limit, cursor)
+		})
+		if err == nil && token != xsecToken {
+			result.XsecToken = token
+		}
+		return result, err
+	})
+}
+
+// listComments ListComments 的单次执行，浏览器崩溃时由 ListComments 重试
+func (s *XiaohongshuService) listComments(ctx context.Context, feedID, xsecToken string, limit int, cursor string) (*CommentsResponse, error) {
+	ctx, done := withTimeout(ctx, "list_comments", false)
+	defer done()
+
+	// 游标无效时不启动浏览器
+	pageCursor, err := xiaohongshu.DecodeCursor(cursor, xiaohongshu.CommentsCursorScope(feedID))
+	if err != nil {
+		return nil, err
+	}
+
+	b, err := s.newBrowser(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer b.Close()
+
+	page := newPage(b)
+	defer page.Close()
+	defer saveCookies(page)
+
+	action := xiaohongshu.NewCommentsAction(page.Context(ctx))
+
+	var comments []xiaohongshu.FeedComment
+	var next string
+	var truncated bool
+	err = retryOnCaptcha(ctx, page, func() (err error) {
+		comments, next, truncated, err = action.ListComments(ctx, feedID, xsecToken, limit, pageCursor)
+		return err
+	})
+	if err = checkBlockedPage(page, err, false); err != nil {
+		return nil, screenshotOnError(page, err)
+	}
+
+	response := &CommentsResponse{
+		FeedID:     feedID,
+		Comments:   comments,
+		Count:      len(comments),
+		NextCursor: next,
+		Truncated:  truncated,
+	}
+
+	return response, nil
+}
+
+// GetCommentTree 分页获取笔记的顶层评论，并展开每条评论的回复组织成评论树
+func (s *XiaohongshuService) GetCommentTree(ctx context.Context, feedID, xsecToken string, limit, repliesLimit int, cursor string) (*CommentTreeResponse, error) {
+	return retryOnTokenExpired(ctx, s, feedID, xsecToken, func(token string) (*CommentTreeResponse, error) {
+		result, err := retryOnBrowserCrash("get_comment_tree", func() (*CommentTreeResponse, error) {
+			return s.getCommentTree(ctx, feedID, token, limit, repliesLimit, cursor)
+		})
+		if err == nil && token != xsecToken {
+			result.XsecToken = token
+		}
+		return result, err
+	})
+}
+
+// getCommentTree GetCommentTree 的单次执行，浏览器崩溃时由 GetCommentTree 重试
+func (s *XiaohongshuService) getCommentTree(ctx context.Context, feedID, xsecToken string, limit, repliesLimit int, cursor string) (*CommentTreeResponse, error) {
+	ctx, done := withTimeout(ctx, "get_comment_tree", false)
+	defer done()
+
+	// 游标无效时不启动浏览器
+	pageCursor, err := xiaohongshu.DecodeCursor(cursor, xiaohongshu.CommentsCursorScope(feedID))
+	if err != nil {
+		return nil, err
+	}
+
+	b, err := s.newBrowser(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer b.Close()
+
+	page := newPage(b)
+	defer page.Close()
+	defer saveCookies(page)
+
+	action := xiaohongshu.NewCommentsAction(page.Context(ctx))
+
+	var comments []xiaohongshu.CommentNode
+	var next string
+	var truncated bool
+	err = retryOnCaptcha(ctx, page, func() (err error) {
+		comments, next, truncated, err = action.CommentTree(ctx, feedID, xsecToken, limit, repliesLimit, pageCursor)
+		return err
+	})
+	if err = checkBlockedPage(page, err, false); err != nil {
+		return nil, screenshotOnError(page, err)
+	}
+
+	response := &CommentTreeResponse{
+		FeedID:     feedID,
+		Comments:   comments,
+		Count:      len(comments),
+		NextCursor: next,
+		Truncated:  truncated,
+	}
+
+	return response, nil
+}
+
+// IsOwnNote 判断笔记是否属于当前登录账号，用于在编辑、删除前确认权限
+func (s *XiaohongshuService) IsOwnNote(ctx context.Context, feedID, xsecToken string) (bool, error) {
+	return retryOnBrowserCrash("is_own_note", func() (bool, error) {
+		return s.isOwnNote(ctx, feedID, xsecToken)
+	})
+}
+
+// isOwnNote IsOwnNote 的单次执行，浏览器崩溃时由 IsOwnNote 重试
+func (s *XiaohongshuService) isOwnNote(ctx context.Context, feedID, xsecToken string) (bool, error) {
+	ctx, done := withTimeout(ctx, "is_own_note", false)
+	defer done()
+
+	b, err := s.newBrowser(ctx)
+	if err != nil {
+		return false, err
+	}
+	defer b.Close()
+
+	page := newPage(b)
+	defer page.Close()
+	defer saveCookies(page)
+
+	action := xiaohongshu.NewFeedDetailAction(page.Context(ctx))
+
+	owned, err := action.IsOwnNote(ctx, feedID, xsecToken)
+	if err != nil {
+		return false, screenshotOnError(page, err)
+	}
+
+	return owned, nil
+}
+
+// GetFeedByURL 通过分享链接获取Feed详情，支持完整链接和 xhslink 短链
+func (s *XiaohongshuService) GetFeedByURL(ctx context.Context, url string) (*FeedDetailResponse, error) {
+	ctx, done := withTimeout(ctx, "get_feed_by_url", false)
+	defer done()
+
+	feedID, xsecToken, err := xiaohongshu.ResolveFeedURL(ctx, url)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.GetFeedDetail(ctx, feedID, xsecToken)
+}
+
+// GetFeedLink 返回笔记的网页链接，不需要打开浏览器。
+// short 为 true 时再打开详情页，通过“复制链接”获取分享短链；详情页没有该入口时只返回网页链接并附带警告
+func (s *XiaohongshuService) GetFeedLink(ctx context.Context, feedID, xsecToken string, short bool) (*FeedLinkResponse, error) {
+	response := newFeedLinkResponse(feedID, xsecToken)
+	if !short {
+		return response, nil
+	}
+
+	shortURL, err := retryOnBrowserCrash("get_feed_link", func() (string, error) {
+		return s.feedShareLink(ctx, response.FeedID, xsecToken)
+	})
+	if errors.Is(err, xiaohongshu.ErrShareLinkUnavailable) {
+		response.Warnings = append(response.Warnings, err.Error()+"，只返回网页链接")
+		return response, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	response.ShortURL = shortURL
+	return response, nil
+}
+
+// feedShareLink GetFeedLink 获取分享短链的单次执行，浏览器崩溃时由 GetFeedLink 重试
+func (s *XiaohongshuService) feedShareLink(ctx context.Context, feedID, xsecToken string) (string, error) {
+	ctx, done := withTimeout(ctx, "get_feed_link", false)
+	defer done()
+
+	b, err := s.newBrowser(ctx)
+	if err != nil {
+		return "", err
+	}
+	defer b.Close()
+
+	page := newPage(b)
+	defer page.Close()
+	defer saveCookies(page)
+
+	action := xiaohongshu.NewFeedDetailAction(page.Context(ctx))
+
+	var shortURL string
+	err = retryOnCaptcha(ctx, page, func() (err error) {
+		shortURL, err = action.ShareLink(ctx, feedID, xsecToken)
+		return err
+	})
+	if err = checkBlockedPage(page, err, false); err != nil {
+		return "", screenshotOnError(page, err)
+	}
+
+	return shortURL, nil
+}
+
+// newFeedLinkResponse 根据笔记ID和 xsec_token 构造笔记链接，真实服务和 mock 服务共用
+func newFeedLinkResponse(feedID, xsecToken string) *FeedLinkResponse {
+	feedID = xiaohongshu.CanonicalFeedID(feedID)
+	return &FeedLinkResponse{
+		FeedID: feedID,
+		URL:    xiaohongshu.FeedURL(feedID, xsecToken),
+	}
+}
+
+// GetFeedTags 获取笔记的话题标签，只返回话题名称、ID和链接，比完整的笔记详情小得多
+func (s *XiaohongshuService) GetFeedTags(ctx context.Context, feedID, xsecToken string) (*FeedTagsResponse, error) {
+	return retryOnTokenExpired(ctx, s, feedID, xsecToken, func(token string) (*FeedTagsResponse, error) {
+		result, err := retryOnBrowserCrash("get_feed_tags", func() (*FeedTagsResponse, error) {
+			return s.getFeedTags(ctx, feedID, token)
+		})
+		if err == nil && token != xsecToken {
+			result.XsecToken = token
+		}
+		return result, err
+	})
+}
+
+// getFeedTags GetFeedTags 的单次执行，与笔记详情一样打开详情页，只读取话题标签
+func (s *XiaohongshuService) getFeedTags(ctx context.Context, feedID, xsecToken string) (*FeedTagsResponse, error) {
+	ctx, done := withTimeout(ctx, "get_feed_tags", false)
+	defer done()
+
+	b, err := s.newBrowser(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer b.Close()
+
+	page := newPage(b)
+	defer page.Close()
+	defer saveCookies(page)
+
+	action := xiaohongshu.NewFeedDetailAction(page.Context(ctx))
+
+	var detail any
+	err = retryOnCaptcha(ctx, page, func() (err error) {
+		detail, err = action.GetFeedDetail(ctx, feedID, xsecToken)
+		return err
+	})
+	if err = checkBlockedPage(page, err, detail == nil); err != nil {
+		return nil, screenshotOnError(page, err)
+	}
+
+	tags, err := xiaohongshu.GetFeedTags(page, feedID)
+	if err != nil {
+		return nil, screenshotOnError(page, err)
+	}
+
+	return &FeedTagsResponse{
+		FeedID: feedID,
+		Tags:   tags,
+		Count:  len(tags),
+	}, nil
+}
+
+// UserProfile 获取用户信息
+func (s *XiaohongshuService) UserProfile(ctx context.Context, userID, xsecToken string) (*UserProfileResponse, error) {
+	return retryOnBrowserCrash("user_profile", func() (*UserProfileResponse, error) {
+		return s.userProfile(ctx, userID, xsecToken)
+	})
+}
+
+// userProfile UserProfile 的单次执行，浏览器崩溃时由 UserProfile 重试
+func (s *XiaohongshuService) userProfile(ctx context.Context, userID, xsecToken string) (*UserProfileResponse, error) {
+	ctx, done := withTimeout(ctx, "user_profile", false)
+	defer done()
+
+	b, err := s.newBrowser(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer b.Close()
+
+	page := newPage(b)
+	defer page.Close()
+	defer saveCookies(page)
+
+	action := xiaohongshu.NewUserProfileAction(page.Context(ctx))
+
+	var result *xiaohongshu.UserProfileResponse
+	err = retryOnCaptcha(ctx, page, func() (err error) {
+		result, err = action.UserProfile(ctx, userID, xsecToken)
+		return err
+	})
+	if err = checkBlockedPage(page, err, result == nil); err != nil {
+		return nil, screenshotOnError(page, err)
+	}
+	response := &UserProfileResponse{
+		UserID:        userID,
+		UserBasicInfo: result.UserBasicInfo,
+		Interactions:  result.Interactions,
+		Feeds:         result.Feeds,
+		Warnings:      xiaohongshu.ProfileWarnings(result),
+	}
+
+	return response, nil
+
+}
+
+// UserProfileByURL 通过用户主页链接获取用户信息，user_id 从页面中解析
+func (s *XiaohongshuService) UserProfileByURL(ctx context.Context, profileURL string) (*UserProfileResponse, error) {
+	return retryOnBrowserCrash("user_profile_by_url", func() (*UserProfileResponse, error) {
+		return s.userProfileByURL(ctx, profileURL)
+	})
+}
+
+// userProfileByURL UserProfileByURL 的单次执行，浏览器崩溃时由 UserProfileByURL 重试
+func (s *XiaohongshuService) userProfileByURL(ctx context.Context, profileURL string) (*UserProfileResponse, error) {
+	ctx, done := withTimeout(ctx, "user_profile", false)
+	defer done()
+
+	b, err := s.newBrowser(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer b.Close()
+
+	page := newPage(b)
+	defer page.Close()
+	defer saveCookies(page)
+
+	action := xiaohongshu.NewUserProfileAction(page.Context(ctx))
+
+	var result *xiaohongshu.UserProfileResponse
+	var userID string
+	err = retryOnCaptcha(ctx, page, func() (err error) {
+		result, userID, err = action.UserProfileByURL(ctx, profileURL)
+		return err
+	})
+	if err = checkBlockedPage(page, err, result == nil); err != nil {
+		return nil, screenshotOnError(page, err)
+	}
+
+	response := &UserProfileResponse{
+		UserID:        userID,
+		UserBasicInfo: result.UserBasicInfo,
+		Interactions:  result.Interactions,
+		Feeds:         result.Feeds,
+		Warnings:      xiaohongshu.ProfileWarnings(result),
+	}
+
+	return response, nil
+}
+
+// UserFeeds 分页获取用户主页的全部笔记，私密账号返回 xiaohongshu.ErrProfilePrivate
+func (s *XiaohongshuService) UserFeeds(ctx context.Context, userID, xsecToken string, limit int, cursor string) (*UserFeedsResponse, error) {
+	result, err := retryOnBrowserCrash("user_feeds", func() (*UserFeedsResponse, error) {
+		return s.userFeeds(ctx, userID, xsecToken, limit, cursor)
+	})
+	if err == nil {
+		s.recordFeedSources(result.Feeds, feedTokenSource{Tool: "user_feeds", UserID: userID, UserXsecToken: xsecToken})
+	}
+	return result, err
+}
+
+// userFeeds UserFeeds 的单次执行，浏览器崩溃时由 UserFeeds 重试
+func (s *XiaohongshuService) userFeeds(ctx context.Context, userID, xsecToken string, limit int, cursor string) (*UserFeedsResponse, error) {
+	ctx, done := withTimeout(ctx, "user_feeds", false)
+	defer done()
+
+	// 游标无效时不启动浏览器
+	pageCursor, err := xiaohongshu.DecodeCursor(cursor, xiaohongshu.UserFeedsCursorScope(userID))
+	if err != nil {
+		return nil, err
+	}
+
+	b, err := s.newBrowser(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer b.Close()
+
+	page := newPage(b)
+	defer page.Close()
+	defer saveCookies(page)
+
+	action := xiaohongshu.NewUserProfileAction(page.Context(ctx))
+
+	var feeds []xiaohongshu.Feed
+	var next string
+	var truncated bool
+	err = retryOnCaptcha(ctx, page, func() (err error) {
+		feeds, next, truncated, err = action.UserFeeds(ctx, userID, xsecToken, limit, pageCursor)
+		return err
+	})
+	if err = checkBlockedPage(page, err, len(feeds) == 0 && cursor == ""); err != nil {
+		if errors.Is(err, xiaohongshu.ErrProfilePrivate) {
+			return nil, err
+		}
+		return nil, screenshotOnError(page, err)
+	}
+
+	response := &UserFeedsResponse{
+		UserID:     userID,
+		Feeds:      feeds,
+		Count:      len(feeds),
+		NextCursor: next,
+		Truncated:  truncated,
+		Warnings:   xiaohongshu.FeedWarnings(feeds),
+	}
+
+	return response, nil
+}
+
+// MyProfile 获取当前登录账号的主页信息及互动数据汇总
+func (s *XiaohongshuService) MyProfile(ctx context.Context) (*MyProfileResponse, error) {
+	return retryOnBrowserCrash("my_profile", func() (*MyProfileResponse, error) {
+		return s.myProfile(ctx)
+	})
+}
+
+// myProfile MyProfile 的单次执行，浏览器崩溃时由 MyProfile 重试
+func (s *XiaohongshuService) myProfile(ctx context.Context) (*MyProfileResponse, error) {
+	ctx, done := withTimeout(ctx, "my_profile", false)
+	defer done()
+
+	b, err := s.newBrowser(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer b.Close()
+
+	page := newPage(b)
+	defer page.Close()
+	defer saveCookies(page)
+
+	action := xiaohongshu.NewUserProfileAction(page.Context(ctx))
+
+	var result *xiaohongshu.UserProfileResponse
+	var userID string
+	err = retryOnCaptcha(ctx, page, func() (err error) {
+		result, userID, err = action.MyProfile(ctx)
+		return err
+	})
+	if err = checkBlockedPage(page, err, result == nil); err != nil {
+		return nil, screenshotOnError(page, err)
+	}
+
+	response := &MyProfileResponse{
+		UserProfileResponse: UserProfileResponse{
+			UserID:        userID,
+			UserBasicInfo: result.UserBasicInfo,
+			Interactions:  result.Interactions,
+			Feeds:         result.Feeds,
+			Warnings:      xiaohongshu.ProfileWarnings(result),
+		},
+		Totals: xiaohongshu.SummarizeProfile(result),
+	}
+
+	return response, nil
+}
+
+// SetFeedVisibility 修改已发布笔记的可见范围，如设为仅自己可见，用于临时下架笔记而不删除。
+// 修改前确认笔记属于当前账号，不属于时返回 xiaohongshu.ErrNotNoteOwner；
+// 笔记类型不支持修改可见范围时返回 xiaohongshu.ErrVisibilityUnsupported
+func (s *XiaohongshuService) SetFeedVisibility(ctx context.Context, feedID, xsecToken, visibility string) (_ *FeedVisibilityResponse, err error) {
+	start := time.Now()
+	defer func() {
+		s.audit.Record(configs.Username, "set_feed_visibility", map[string]any{
+			"feed_id":    feedID,
+			"visibility": visibility,
+		}, start, err)
+	}()
+
+	ctx, done := withTimeout(ctx, "set_feed_visibility", true)
+	defer done()
+
+	v, err := parseFeedVisibility(visibility)
+	if err != nil {
+		return nil, err
+	}
+
+	release, err := s.acquireWrite(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer release()
+
+	b, err := s.newBrowser(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer b.Close()
+
+	page := newPage(b)
+	defer page.Close()
+	defer saveCookies(page)
+
+	owned, err := xiaohongshu.NewFeedDetailAction(page.Context(ctx)).IsOwnNote(ctx, feedID, xsecToken)
+	if err != nil {
+		return nil, screenshotOnError(page, err)
+	}
+	if !owned {
+		return nil, xiaohongshu.ErrNotNoteOwner
+	}
+
+	if err := xiaohongshu.NewEditFeedAction(page.Context(ctx)).SetVisibility(ctx, feedID, v); err != nil {
+		return nil, screenshotOnError(page, s.writeError(page, err))
+	}
+	s.detailCache.Delete(feedID)
+
+	return newFeedVisibilityResponse(feedID, v), nil
+}
+
+// parseFeedVisibility 解析要修改成的可见范围，为空时设为仅自己可见
+func parseFeedVisibility(visibility string) (xiaohongshu.Visibility, error) {
+	if strings.TrimSpace(visibility) == "" {
+		return xiaohongshu.VisibilityPrivate, nil
+	}
+	v, err := xiaohongshu.ParseVisibility(visibility)
+	if err != nil {
+		return "", fmt.Errorf("%w: %w", ErrInvalidArgs, err)
+	}
+	return v, nil
+}
+
+// newFeedVisibilityResponse 构造修改可见范围的响应
+func newFeedVisibilityResponse(feedID string, v xiaohongshu.Visibility) *FeedVisibilityResponse {
+	message := "笔记已设为公开可见"
+	switch v {
+	case xiaohongshu.VisibilityFriends:
+		message = "笔记已设为仅互关好友可见"
+	case xiaohongshu.VisibilityPrivate:
+		message = "笔记已设为仅自己可见"
+	}
+	return &FeedVisibilityResponse{FeedID: feedID, Visibility: string(v), Message: message}
+}
+
+// PostCommentToFeed 发表评论到Feed。image 不为空时作为评论图片上传（本地路径、URL 或 data URL），
+// 笔记不支持图片评论时只发表文字，并在响应的 warnings 中说明
+func (s *XiaohongshuService) PostCommentToFeed(ctx context.Context, feedID, xsecToken, content, image string) (_ *PostCommentResponse, err error) {
+	start := time.Now()
+	defer func() {
+		args := map[string]any{
+			"feed_id": feedID,
+			"content": content,
+		}
+		if image != "" {
+			args["image"] = true
+		}
+		s.audit.Record(configs.Username, "post_comment", args, start, err)
+	}()
+
+	ctx, done := withTimeout(ctx, "post_comment_to_feed", true)
+	defer done()
+
+	var imagePath string
+	if image != "" {
+		imagePaths, cleanup, err := s.processImages(ctx, []string{image}, nil)
+		if errors.Is(err, downloader.ErrHostBlocked) {
+			return nil, fmt.Errorf("%w: %w", ErrInvalidArgs, err)
+		}
+		if err != nil {
+			return nil, err
+		}
+		defer cleanup()
+		imagePath = imagePaths[0]
+	}
+
+	release, err := s.acquireWrite(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer release()
+
+	// 请求带 visible 时使用有界面的浏览器，便于查看操作过程
+	b, err := s.newBrowser(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer b.Close()
+
+	page := newPage(b)
+	defer page.Close()
+	defer saveCookies(page)
+
+	// 创建 Feed 评论 action
+	action := xiaohongshu.NewCommentFeedAction(page.Context(ctx))
+
+	// 发表评论，笔记不支持图片评论时改为只发表文字
+	var warnings []string
+	if imagePath != "" {
+		err = action.PostImageComment(ctx, feedID, xsecToken, content, imagePath)
+		if errors.Is(err, xiaohongshu.ErrCommentImageUnsupported) {
+			logrus.WithContext(ctx).Warnf("笔记 %s 不支持图片评论，只发表文字", feedID)
+			warnings = append(warnings, "该笔记不支持图片评论，已只发表文字评论")
+			err = action.PostComment(ctx, feedID, xsecToken, content)
+		}
+	} else {
+		err = action.PostComment(ctx, feedID, xsecToken, content)
+	}
+	if err != nil {
+		return nil, screenshotOnError(page, s.writeError(page, err))
+	}
+	s.detailCache.Delete(feedID)
+
+	response := &PostCommentResponse{
+		FeedID:   feedID,
+		Success:  true,
+		Message:  "评论发表成功",
+		Warnings: warnings,
+	}
+
+	return response, nil
+}
+
+// defaultCommentDelay 批量评论时两条评论之间的默认间隔，模拟人工操作节奏
+const defaultCommentDelay = 5 * time.Second
+
+// checkBatchComments 检查批量评论的参数：评论间隔不能为负数；
+// 批量评论不支持图片，带图片的评论需要通过 post_comment_to_feed 单独发表
+func checkBatchComments(comments []PostCommentRequest, delay time.Duration) error {
+	if delay < 0 {
+		return fmt.Errorf("%w: delay_seconds 不能为负数", ErrInvalidArgs)
+	}
+	for i, comment := range comments {
+		if comment.Image != "" {
+			return fmt.Errorf("%w: 第%d条评论带有图片，批量评论不支持图片，请使用 post_comment_to_feed", ErrInvalidArgs, i+1)
+		}
+	}
+	return nil
+}
+
+// PostCommentsBatch 批量发表评论。复用同一个浏览器依次发表，单条失败不会中断其余评论。
+// 等待评论间隔时被取消或超时，返回已有的结果和 ctx 的错误，剩余的评论标记为未尝试
+func (s *XiaohongshuService) PostCommentsBatch(ctx context.Context, comments []PostCommentRequest, delay time.Duration) (*PostCommentsResponse, error) {
+	if err := checkBatchComments(comments, delay); err != nil {
+		return nil, err
+	}
+
+	ctx, done := withTimeout(ctx, "post_comments", true)
+	defer done()
+
+	release, err := s.acquireWrite(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer release()
+
+	b, err := s.newBrowser(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer b.Close()
+
+	page := newPage(b)
+	defer page.Close()
+	defer saveCookies(page)
+
+	action := xiaohongshu.NewCommentFeedAction(page.Context(ctx))
+
+	response := &PostCommentsResponse{
+		Results: make([]PostCommentResult, 0, len(comments)),
+	}
+
+	for i, comment := range comments {
+		if i > 0 && delay > 0 {
+			select {
+			case <-ctx.Done():
+				response.markNotAttempted(comments[i:], ctx.Err())
+				return response, ctx.Err()
+			case <-time.After(delay):
+			}
+		}
+
+		result := PostCommentResult{FeedID: comment.FeedID}
+
+		// 前面的评论触发了平台限流时，其余评论不再尝试，避免限流升级
+		if err := s.cooldown.Check(configs.Username); err != nil {
+			result.Error = err.Error()
+			response.Failed++
+			response.Results = append(response.Results, result)
+			continue
+		}
+
+		start := time.Now()
+		err := action.PostComment(ctx, comment.FeedID, comment.XsecToken, comment.Content)
+		s.audit.Record(configs.Username, "post_comment", map[string]any{
+			"feed_id": comment.FeedID,
+			"content": comment.Content,
+			"batch":   true,
+		}, start, err)
+		if err != nil {
+			result.Error = screenshotOnError(page, s.writeError(page, err)).Error()
+			response.Failed++
+		} else {
+			result.Success = true
+			response.Succeeded++
+			s.detailCache.Delete(comment.FeedID)
+		}
+		response.Results = append(response.Results, result)
+	}
+
+	return response, nil
+}
+
+// markNotAttempted 将批量评论中剩余的评论标记为未尝试，err 为中断的原因
+func (r *PostCommentsResponse) markNotAttempted(comments []PostCommentRequest, err error) {
+	for _, comment := range comments {
+		r.Results = append(r.Results, PostCommentResult{
+			FeedID:       comment.FeedID,
+			NotAttempted: true,
+			Error:        "未尝试: " + err.Error(),
+		})
+		r.NotAttempted++
+	}
+}
+
+// CacheStats 返回笔记详情和搜索结果缓存的命中统计
+func (s *XiaohongshuService) CacheStats() map[string]cacheStats {
+	return map[string]cacheStats{
+		"feed_detail": s.detailCache.Stats(),
+		"search":      s.searchCache.Stats(),
+	}
+}
+
+// DebugPage 使用当前登录会话加载小红书页面，返回渲染后的 HTML 或整页截图，用于排查选择器失效
+func (s *XiaohongshuService) DebugPage(ctx context.Context, pageURL string, screenshot bool) (*DebugPageResponse, error) {
+	ctx, done := withTimeout(ctx, "debug_page_html", false)
+	defer done()
+
+	if err := validateDebugURL(pageURL); err != nil {
+		return nil, err
+	}
+
+	b, err := s.newBrowser(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer b.Close()
+
+	page := newPage(b)
+	defer page.Close()
+	defer saveCookies(page)
+
+	p := page.Context(ctx)
+	if err := xiaohongshu.Navigate(p, pageURL); err != nil {
+		return nil, fmt.Errorf("打开页面失败: %w", err)
+	}
+
+	info, err := p.Info()
+	if err != nil {
+		return nil, fmt.Errorf("获取页面信息失败: %w", err)
+	}
+
+	response := &DebugPageResponse{
+		URL:   info.URL,
+		Title: info.Title,
+	}
+
+	if screenshot {
+		data, err := p.Screenshot(true, &proto.PageCaptureScreenshot{
+			Format: proto.PageCaptureScreenshotFormatPng,
+		})
+		if err != nil {
+			return nil, fmt.Errorf("截图失败: %w", err)
+		}
+		response.Screenshot = "data:image/png;base64," + base64.StdEncoding.EncodeToString(data)
+	} else {
+		html, err := p.HTML()
+		if err != nil {
+			return nil, fmt.Errorf("获取页面 HTML 失败: %w", err)
+		}
+		response.HTML = html
+	}
+
+	return response, nil
+}
+
+// validateDebugURL 调试工具只允许打开小红书的页面
+func validateDebugURL(pageURL string) error {
+	u, err := url.Parse(pageURL)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") {
+		return fmt.Errorf("无效的链接: %s", pageURL)
+	}
+
+	host := strings.ToLower(u.Hostname())
+	if configs.IsSiteHost(host) || host == "xhslink.com" || strings.HasSuffix(host, ".xhslink.com") {
+		return nil
+	}
+
+	return fmt.Errorf("只支持小红书的链接: %s", pageURL)
+}
+
+// checkBlockedPage 读操作失败或没有结果时，检查页面是否遇到登录墙、系统繁忙提示页、xsec_token 失效或笔记不存在的错误页，
+// 以便调用方区分“登录已过期”“平台暂时不可用”“令牌过期”“笔记已删除”和“确实没有数据”、选择器失效
+func checkBlockedPage(page *rod.Page, err error, empty bool) error {
+	if err == nil && !empty {
+		return nil
+	}
+	if wallErr := xiaohongshu.CheckLoginWall(page); wallErr != nil {
+		return wallErr
+	}
+	if busyErr := xiaohongshu.CheckServiceBusy(page); busyErr != nil {
+		return busyErr
+	}
+	if tokenErr := xiaohongshu.CheckTokenExpired(page); tokenErr != nil {
+		return tokenErr
+	}
+	if noteErr := xiaohongshu.CheckNoteUnavailable(page); noteErr != nil {
+		return noteErr
+	}
+	return err
+}
+
+// retryOnCaptcha 执行读操作，失败且页面上有验证码时等待人工完成验证后重试一次。
+// 读操作成功时即使页面上有验证码也直接返回结果；无头模式或等待超时返回 xiaohongshu.ErrCaptchaRequired。
+// 写操作不使用该函数，避免重试导致重复发布
+func retryOnCaptcha(ctx context.Context, page *rod.Page, fn func() error) error {
+	err := fn()
+	if err == nil || !xiaohongshu.HasCaptcha(page) {
+		return err
+	}
+
+	if waitErr := xiaohongshu.WaitForCaptcha(ctx, page, isHeadless(ctx), configs.GetCaptchaWait()); waitErr != nil {
+		return waitErr
+	}
+
+	return fn()
+}
+
+// writeError 写操作失败时，页面上有验证码则返回 xiaohongshu.ErrCaptchaRequired；
+// 提示操作过于频繁则开始写操作冷却并返回 *RateLimitedError；
+// 页面为系统繁忙提示页则返回 xiaohongshu.ErrServiceBusy
+func (s *XiaohongshuService) writeError(page *rod.Page, err error) error {
+	if err == nil {
+		return nil
+	}
+	if xiaohongshu.HasCaptcha(page) {
+		return errors.Join(xiaohongshu.ErrCaptchaRequired, err)
+	}
+	// 限流提示常带有“请稍后再试”，需在繁忙提示页之前判断
+	if limitErr := xiaohongshu.CheckRateLimited(page); limitErr != nil {
+		cooldown := s.cooldown.Trip(configs.Username)
+		logrus.WithContext(page.GetContext()).Warnf("账号 %s 被平台限流，暂停写操作 %s: %v", configs.Username, cooldown, limitErr)
+		return &RateLimitedError{RetryAfter: cooldown, Err: errors.Join(limitErr, err)}
+	}
+	if busyErr := xiaohongshu.CheckServiceBusy(page); busyErr != nil {
+		return errors.Join(busyErr, err)
+	}
+	return err
+}
+
+// newPage 创建页面，恢复持久化的登录会话，并应用配置的 UA 和视口大小
+func newPage(b *serviceBrowser) *rod.Page {
+	page := b.NewPage()
+	restoreCookies(page)
+
+	// AcceptLanguage 同时决定请求头和 navigator.language，区域设置决定页面中数字、日期的格式
+	if err := page.SetUserAgent(&proto.NetworkSetUserAgentOverride{
+		UserAgent:      configs.GetUserAgent(),
+		AcceptLanguage: configs.GetAcceptLanguage(),
+	}); err != nil {
+		logrus.Warnf("设置 UA 失败: %v", err)
+	}
+	if err := (proto.EmulationSetLocaleOverride{Locale: configs.GetLocale()}).Call(page); err != nil {
+		logrus.Warnf("设置浏览器语言失败: %v", err)
+	}
+
+	viewport := configs.GetViewport()
+	if err := page.SetViewport(&proto.EmulationSetDeviceMetricsOverride{
+		Width:             viewport.Width,
+		Height:            viewport.Height,
+		DeviceScaleFactor: 1,
+	}); err != nil {
+		logrus.Warnf("设置视口大小失败: %v", err)
+	}
+
+	return page
 }
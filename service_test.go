@@ -0,0 +1,364 @@
+package main
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/xpzouying/xiaohongshu-mcp/configs"
+	"github.com/xpzouying/xiaohongshu-mcp/pkg/downloader"
+	"github.com/xpzouying/xiaohongshu-mcp/xiaohongshu"
+)
+
+// testPNG 1x1 的 PNG 图片
+const testPNG = "iVBORw0KGgoAAAANSUhEUgAAAAEAAAABCAYAAAAfFcSJAAAADUlEQVR42mNkYPhfDwAChwGA60e6kgAAAABJRU5ErkJggg=="
+
+// newTestPublishRequest 构造一个可以通过校验的发布请求
+func newTestPublishRequest() *PublishRequest {
+	return &PublishRequest{
+		Title:   "标题",
+		Content: "正文",
+		Images:  []string{"https://example.com/1.jpg"},
+	}
+}
+
+func TestValidatePublishRequestVisibility(t *testing.T) {
+	tests := []struct {
+		visibility string
+		wantErr    bool
+	}{
+		{"", false},
+		{"public", false},
+		{"PUBLIC", false},
+		{"friends", false},
+		{"private", false},
+		{"unknown", true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.visibility, func(t *testing.T) {
+			req := newTestPublishRequest()
+			req.Visibility = tt.visibility
+
+			_, err := validatePublishRequest(req)
+			if tt.wantErr {
+				require.Error(t, err)
+				assert.ErrorIs(t, err, ErrInvalidArgs)
+				return
+			}
+			assert.NoError(t, err)
+		})
+	}
+}
+
+func TestValidatePublishRequestCoverIndex(t *testing.T) {
+	req := newTestPublishRequest()
+	req.Images = append(req.Images, "https://example.com/2.jpg")
+
+	_, err := validatePublishRequest(req)
+	assert.NoError(t, err)
+
+	req.CoverIndex = 1
+	_, err = validatePublishRequest(req)
+	assert.NoError(t, err)
+
+	for _, index := range []int{-1, 2} {
+		req.CoverIndex = index
+		_, err = validatePublishRequest(req)
+		assert.ErrorIs(t, err, ErrInvalidArgs, index)
+	}
+}
+
+func TestCoverFirst(t *testing.T) {
+	paths := []string{"a.jpg", "b.jpg", "c.jpg"}
+
+	assert.Equal(t, []string{"a.jpg", "b.jpg", "c.jpg"}, coverFirst(paths, 0))
+	assert.Equal(t, []string{"b.jpg", "a.jpg", "c.jpg"}, coverFirst(paths, 1))
+	assert.Equal(t, []string{"c.jpg", "a.jpg", "b.jpg"}, coverFirst(paths, 2))
+	assert.Equal(t, []string{"a.jpg", "b.jpg", "c.jpg"}, paths, "不修改原来的切片")
+}
+
+func TestValidateEditFeedRequest(t *testing.T) {
+	longTitle := strings.Repeat("标题", 21)
+	title := "新标题"
+	tooManyTags := make([]string, maxTagsPerNote+1)
+	for i := range tooManyTags {
+		tooManyTags[i] = fmt.Sprintf("话题%d", i)
+	}
+
+	tests := []struct {
+		name    string
+		updates EditFeedRequest
+		wantErr bool
+	}{
+		{name: "no updates", wantErr: true},
+		{name: "title too long", updates: EditFeedRequest{Title: &longTitle}, wantErr: true},
+		{name: "too many tags", updates: EditFeedRequest{Tags: tooManyTags}, wantErr: true},
+		{name: "title only", updates: EditFeedRequest{Title: &title}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := validateEditFeedRequest(&tt.updates)
+			if tt.wantErr {
+				assert.ErrorIs(t, err, ErrInvalidArgs)
+				return
+			}
+			assert.NoError(t, err)
+		})
+	}
+}
+
+func TestCheckBatchCommentsDelay(t *testing.T) {
+	comments := []PostCommentRequest{{FeedID: "1", XsecToken: "t", Content: "c"}}
+
+	assert.NoError(t, checkBatchComments(comments, 0))
+	assert.NoError(t, checkBatchComments(comments, defaultCommentDelay))
+	assert.ErrorIs(t, checkBatchComments(comments, -time.Second), ErrInvalidArgs)
+}
+
+func TestPostCommentsResponseMarkNotAttempted(t *testing.T) {
+	response := &PostCommentsResponse{
+		Results:   []PostCommentResult{{FeedID: "1", Success: true}},
+		Succeeded: 1,
+	}
+
+	response.markNotAttempted([]PostCommentRequest{{FeedID: "2"}, {FeedID: "3"}}, context.Canceled)
+
+	require.Len(t, response.Results, 3)
+	assert.Equal(t, 1, response.Succeeded)
+	assert.Equal(t, 2, response.NotAttempted)
+	for _, result := range response.Results[1:] {
+		assert.True(t, result.NotAttempted)
+		assert.False(t, result.Success)
+		assert.Contains(t, result.Error, context.Canceled.Error())
+	}
+}
+
+func TestCheckImageCount(t *testing.T) {
+	images := func(n int) []string {
+		return make([]string, n)
+	}
+
+	tests := []struct {
+		name      string
+		maxImages int
+		count     int
+		wantErr   bool
+	}{
+		{"one image", 0, 1, false},
+		{"at default limit", 0, configs.DefaultMaxImages, false},
+		{"over default limit", 0, configs.DefaultMaxImages + 1, true},
+		{"at custom limit", 9, 9, false},
+		{"over custom limit", 9, 10, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			configs.SetMaxImages(tt.maxImages)
+			defer configs.SetMaxImages(0)
+
+			err := checkImageCount(images(tt.count))
+			if tt.wantErr {
+				assert.ErrorIs(t, err, ErrInvalidArgs)
+				return
+			}
+			assert.NoError(t, err)
+		})
+	}
+}
+
+func TestNormalizeTags(t *testing.T) {
+	tests := []struct {
+		name string
+		tags []string
+		want []string
+	}{
+		{"nil", nil, nil},
+		{"leading hash", []string{"#foo", "＃全角"}, []string{"foo", "全角"}},
+		{"surrounding spaces", []string{"foo ", "  # bar  "}, []string{"foo", "bar"}},
+		{"duplicates ignore case", []string{"Foo", "foo", "#FOO", "bar"}, []string{"Foo", "bar"}},
+		{"empties dropped", []string{"", "  ", "#", "##"}, []string{}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := normalizeTags(tt.tags)
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestNormalizeTagsLimit(t *testing.T) {
+	tags := make([]string, 0, maxTagsPerNote+2)
+	for i := range maxTagsPerNote + 2 {
+		tags = append(tags, fmt.Sprintf("tag%d", i))
+	}
+
+	// 重复的标签去掉后不计入上限
+	withDuplicates := append([]string{"TAG0", "#tag1"}, tags[:maxTagsPerNote]...)
+	got, err := normalizeTags(withDuplicates)
+	require.NoError(t, err)
+	assert.Len(t, got, maxTagsPerNote)
+
+	_, err = normalizeTags(tags)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "tag10, tag11")
+
+	req := newTestPublishRequest()
+	req.Tags = tags
+	_, err = validatePublishRequest(req)
+	assert.ErrorIs(t, err, ErrInvalidArgs)
+}
+
+func TestCheckContentWidth(t *testing.T) {
+	tests := []struct {
+		name     string
+		maxWidth int
+		content  string
+		wantErr  bool
+	}{
+		{"empty", 0, "", false},
+		{"ascii at default limit", 0, strings.Repeat("a", configs.DefaultMaxContentWidth), false},
+		{"ascii over default limit", 0, strings.Repeat("a", configs.DefaultMaxContentWidth+1), true},
+		{"cjk at default limit", 0, strings.Repeat("字", configs.DefaultMaxContentWidth/2), false},
+		{"cjk over default limit", 0, strings.Repeat("字", configs.DefaultMaxContentWidth/2+1), true},
+		{"mixed over by one", 0, strings.Repeat("字", configs.DefaultMaxContentWidth/2-1) + "abc", true},
+		{"at custom limit", 10, "中文abcdef", false},
+		{"over custom limit", 10, "中文abcdefg", true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			configs.SetMaxContentWidth(tt.maxWidth)
+			defer configs.SetMaxContentWidth(0)
+
+			err := checkContentWidth(tt.content)
+			if tt.wantErr {
+				assert.ErrorIs(t, err, ErrInvalidArgs)
+				assert.Contains(t, err.Error(), "宽度为")
+				return
+			}
+			assert.NoError(t, err)
+		})
+	}
+}
+
+func TestNewFeedsListResponseDuplicates(t *testing.T) {
+	data, err := os.ReadFile(filepath.Join("xiaohongshu", "testdata", "feeds_scroll_frames.json"))
+	require.NoError(t, err)
+	var frames [][]xiaohongshu.Feed
+	require.NoError(t, json.Unmarshal(data, &frames))
+
+	var feeds []xiaohongshu.Feed
+	for _, frame := range frames {
+		feeds = append(feeds, frame...)
+	}
+
+	result := newFeedsListResponse("list_feeds", feeds)
+	assert.Equal(t, 5, result.Count)
+	assert.Equal(t, "64f0a1b2c3d4e5f6a7b8c903", result.Feeds[2].ID)
+
+	encoded, err := json.Marshal(result)
+	require.NoError(t, err)
+	var fields map[string]any
+	require.NoError(t, json.Unmarshal(encoded, &fields))
+	assert.EqualValues(t, 2, fields["duplicates_dropped"])
+}
+
+// fakeImageProcessor 原样返回图片路径，err 不为空时返回该错误
+type fakeImageProcessor struct {
+	err error
+}
+
+func (p fakeImageProcessor) ProcessImages(images []string) ([]string, error) {
+	if p.err != nil {
+		return nil, p.err
+	}
+	return images, nil
+}
+
+func TestProcessImagesCleanup(t *testing.T) {
+	tempDir := t.TempDir()
+	downloader.SetTempDir(tempDir)
+	require.NoError(t, downloader.SetHostPolicy(nil, nil, true))
+	t.Cleanup(func() {
+		downloader.SetTempDir("")
+		require.NoError(t, downloader.SetHostPolicy(nil, nil, false))
+	})
+
+	pngData, err := base64.StdEncoding.DecodeString(testPNG)
+	require.NoError(t, err)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/ok.png" {
+			http.NotFound(w, r)
+			return
+		}
+		w.Write(pngData)
+	}))
+	defer server.Close()
+
+	localImage := filepath.Join(t.TempDir(), "local.png")
+	require.NoError(t, os.WriteFile(localImage, pngData, 0o644))
+
+	tempFiles := func() []string {
+		entries, err := os.ReadDir(tempDir)
+		require.NoError(t, err)
+		names := make([]string, len(entries))
+		for i, entry := range entries {
+			names[i] = entry.Name()
+		}
+		return names
+	}
+
+	t.Run("cleanup after publish", func(t *testing.T) {
+		s := &XiaohongshuService{images: fakeImageProcessor{}}
+		paths, cleanup, err := s.processImages(context.Background(),
+			[]string{server.URL + "/ok.png", localImage, "data:image/png;base64," + testPNG}, nil)
+		require.NoError(t, err)
+		require.Len(t, paths, 3)
+		assert.Equal(t, localImage, paths[1])
+		assert.Len(t, tempFiles(), 2, "下载的图片和解码的 data URL 写入临时目录")
+
+		cleanup()
+		assert.Empty(t, tempFiles())
+		assert.FileExists(t, localImage, "用户提供的本地图片不能删除")
+	})
+
+	t.Run("download fails", func(t *testing.T) {
+		s := &XiaohongshuService{images: fakeImageProcessor{}}
+		_, _, err := s.processImages(context.Background(),
+			[]string{server.URL + "/ok.png", server.URL + "/missing.png", localImage}, nil)
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "第 2 张图片")
+		assert.Empty(t, tempFiles())
+		assert.FileExists(t, localImage)
+	})
+
+	t.Run("process fails", func(t *testing.T) {
+		s := &XiaohongshuService{images: fakeImageProcessor{err: errors.New("处理失败")}}
+		_, _, err := s.processImages(context.Background(),
+			[]string{server.URL + "/ok.png", localImage, "data:image/png;base64," + testPNG}, nil)
+		require.Error(t, err)
+		assert.Empty(t, tempFiles())
+		assert.FileExists(t, localImage)
+	})
+}
+
+func TestRetryOnCaptchaSuccessSkipsCheck(t *testing.T) {
+	// 读操作成功时直接返回，不检查验证码，page 为 nil 也不会被访问
+	calls := 0
+	err := retryOnCaptcha(context.Background(), nil, func() error {
+		calls++
+		return nil
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, 1, calls)
+}
@@ -0,0 +1,170 @@
+package main
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"sync"
+	"time"
+)
+
+// MCP 会话管理
+//
+// initialize 成功后服务端分配会话ID，通过 Mcp-Session-Id 响应头返回；
+// 之后的请求都需要带上该请求头，DELETE /mcp 可主动结束会话。
+// 客户端发送 notifications/initialized 之前只能调用 ping，其他方法返回错误。
+// 会话长时间没有请求时会被自动清理；WebSocket 会话随连接存在，连接断开时结束。
+
+const (
+	// mcpSessionHeader MCP 会话ID请求/响应头
+	mcpSessionHeader = "Mcp-Session-Id"
+	// mcpSessionTTL 会话空闲超时时间
+	mcpSessionTTL = 30 * time.Minute
+)
+
+// mcpSession 单个 MCP 会话的状态
+type mcpSession struct {
+	ID          string
+	Account     string // 会话选择的账号，为空表示默认账号
+	Initialized bool   // 客户端是否已发送 initialized 通知
+	lastSeen    time.Time
+	connected   bool // 绑定在 WebSocket 连接上，连接断开时删除，不按空闲时间淘汰
+}
+
+// expired 会话是否已空闲超时
+func (s *mcpSession) expired(now time.Time, ttl time.Duration) bool {
+	return !s.connected && now.Sub(s.lastSeen) > ttl
+}
+
+// sessionStore 并发安全的会话存储，按空闲时间淘汰过期会话
+type sessionStore struct {
+	mu       sync.Mutex
+	sessions map[string]*mcpSession
+	ttl      time.Duration
+}
+
+// newSessionStore 创建会话存储
+func newSessionStore(ttl time.Duration) *sessionStore {
+	return &sessionStore{
+		sessions: make(map[string]*mcpSession),
+		ttl:      ttl,
+	}
+}
+
+// Create 创建新会话，同时清理已过期的会话
+func (s *sessionStore) Create() mcpSession {
+	return s.create(false)
+}
+
+// CreateForConnection 为 WebSocket 连接创建会话，连接存在期间不会因空闲被清理，需在连接断开时 Delete
+func (s *sessionStore) CreateForConnection() mcpSession {
+	return s.create(true)
+}
+
+// create 创建新会话，同时清理已过期的会话
+func (s *sessionStore) create(connected bool) mcpSession {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := time.Now()
+	for id, session := range s.sessions {
+		if session.expired(now, s.ttl) {
+			delete(s.sessions, id)
+		}
+	}
+
+	session := &mcpSession{
+		ID:        newSessionID(),
+		lastSeen:  now,
+		connected: connected,
+	}
+	s.sessions[session.ID] = session
+
+	return *session
+}
+
+// Get 获取会话的副本并刷新其活跃时间，会话不存在或已过期时返回 false
+func (s *sessionStore) Get(id string) (mcpSession, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	session, ok := s.sessions[id]
+	if !ok {
+		return mcpSession{}, false
+	}
+
+	now := time.Now()
+	if session.expired(now, s.ttl) {
+		delete(s.sessions, id)
+		return mcpSession{}, false
+	}
+
+	session.lastSeen = now
+	return *session, true
+}
+
+// MarkInitialized 标记会话已完成初始化握手
+func (s *sessionStore) MarkInitialized(id string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if session, ok := s.sessions[id]; ok {
+		session.Initialized = true
+	}
+}
+
+// Delete 删除会话，会话不存在时返回 false
+func (s *sessionStore) Delete(id string) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, ok := s.sessions[id]; !ok {
+		return false
+	}
+	delete(s.sessions, id)
+
+	return true
+}
+
+// newSessionID 生成随机会话ID
+func newSessionID() string {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		panic(err)
+	}
+	return hex.EncodeToString(b)
+}
+
+// requiresSession 判断方法是否需要在 initialize 之后才能调用
+func requiresSession(method string) bool {
+	switch method {
+	case "initialize", "ping":
+		return false
+	default:
+		return true
+	}
+}
+
+// requiresInitialized 判断方法是否需要在客户端发送 initialized 通知之后才能调用
+func requiresInitialized(method string) bool {
+	return requiresSession(method) && !isInitializedNotification(method)
+}
+
+// isInitializedNotification 判断是否为客户端的 initialized 通知
+func isInitializedNotification(method string) bool {
+	return method == "initialized" || method == "notifications/initialized"
+}
+
+// notInitializedMessage 发送 initialized 通知之前调用其他方法时的错误信息
+const notInitializedMessage = "Bad Request: send notifications/initialized before calling other methods"
+
+// sessionRequiredError 在 initialize 之前调用其他方法时返回的错误
+func sessionRequiredError(id any, message string) *JSONRPCResponse {
+	return &JSONRPCResponse{
+		JSONRPC: "2.0",
+		Error: &JSONRPCError{
+			Code:    -32000,
+			Message: message,
+		},
+		ID: id,
+	}
+}
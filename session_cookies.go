@@ -0,0 +1,147 @@
+package main
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/go-rod/rod"
+	"github.com/go-rod/rod/lib/proto"
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+	"github.com/xpzouying/xiaohongshu-mcp/configs"
+)
+
+// 登录会话持久化
+//
+// 每次创建页面时从 -session-dir 下的 cookies 文件恢复登录状态，
+// 操作结束后把浏览器中最新的 cookies 写回文件，服务重启后无需重新登录。
+
+// sessionCookieName 小红书登录态 cookie
+const sessionCookieName = "web_session"
+
+// cookiesMu 多个浏览器实例共享同一个 cookies 文件，读写需要串行
+var cookiesMu sync.Mutex
+
+// loadCookies 读取持久化的 cookies，文件不存在时返回空
+func loadCookies() ([]*proto.NetworkCookie, error) {
+	cookiesMu.Lock()
+	defer cookiesMu.Unlock()
+
+	data, err := os.ReadFile(configs.GetCookiesPath())
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, errors.Wrap(err, "读取 cookies 文件失败")
+	}
+
+	var cookies []*proto.NetworkCookie
+	if err := json.Unmarshal(data, &cookies); err != nil {
+		return nil, errors.Wrap(err, "解析 cookies 文件失败")
+	}
+
+	return cookies, nil
+}
+
+// storeCookies 原子写入 cookies 文件，避免并发读到写了一半的文件
+func storeCookies(cookies []*proto.NetworkCookie) error {
+	data, err := json.Marshal(cookies)
+	if err != nil {
+		return errors.Wrap(err, "序列化 cookies 失败")
+	}
+
+	cookiesMu.Lock()
+	defer cookiesMu.Unlock()
+
+	path := configs.GetCookiesPath()
+	if err := os.MkdirAll(filepath.Dir(path), 0o700); err != nil {
+		return errors.Wrap(err, "创建会话目录失败")
+	}
+
+	tmp, err := os.CreateTemp(filepath.Dir(path), cookiesTempPattern)
+	if err != nil {
+		return errors.Wrap(err, "创建临时 cookies 文件失败")
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return errors.Wrap(err, "写入 cookies 失败")
+	}
+	if err := tmp.Close(); err != nil {
+		return errors.Wrap(err, "写入 cookies 失败")
+	}
+
+	return os.Rename(tmp.Name(), path)
+}
+
+// cookiesTempPattern 写 cookies 时使用的临时文件名
+const cookiesTempPattern = ".cookies-*.json"
+
+// restoreCookies 把持久化的 cookies 加载到页面所属的浏览器
+func restoreCookies(page *rod.Page) {
+	cookies, err := loadCookies()
+	if err != nil {
+		logrus.Warnf("恢复登录会话失败: %v", err)
+		return
+	}
+	if len(cookies) == 0 {
+		return
+	}
+
+	if err := page.Browser().SetCookies(proto.CookiesToParams(cookies)); err != nil {
+		logrus.Warnf("恢复登录会话失败: %v", err)
+	}
+}
+
+// saveCookies 把浏览器当前的 cookies 写回会话文件
+func saveCookies(page *rod.Page) {
+	cookies, err := page.Browser().GetCookies()
+	if err != nil {
+		logrus.Warnf("保存登录会话失败: %v", err)
+		return
+	}
+	if len(cookies) == 0 {
+		return
+	}
+
+	if err := storeCookies(cookies); err != nil {
+		logrus.Warnf("保存登录会话失败: %v", err)
+	}
+}
+
+// clearCookies 删除持久化的 cookies 文件，之后启动的浏览器不再恢复登录态
+func clearCookies() error {
+	cookiesMu.Lock()
+	defer cookiesMu.Unlock()
+
+	if err := os.Remove(configs.GetCookiesPath()); err != nil && !os.IsNotExist(err) {
+		return errors.Wrap(err, "删除 cookies 文件失败")
+	}
+	return nil
+}
+
+// logRestoredSession 启动时检查会话目录中是否有未过期的登录态
+func logRestoredSession() {
+	path := configs.GetCookiesPath()
+
+	cookies, err := loadCookies()
+	if err != nil {
+		logrus.Warnf("登录会话文件不可用: %s, %v", path, err)
+		return
+	}
+
+	now := float64(time.Now().Unix())
+	for _, cookie := range cookies {
+		// Expires 为 -1 表示会话 cookie，没有过期时间
+		if cookie.Name == sessionCookieName && (cookie.Expires <= 0 || float64(cookie.Expires) > now) {
+			logrus.Infof("已恢复登录会话: %s", path)
+			return
+		}
+	}
+
+	logrus.Infof("没有可恢复的登录会话，需要重新登录: %s", path)
+}
@@ -0,0 +1,42 @@
+package main
+
+import (
+	"context"
+	"time"
+
+	"github.com/sirupsen/logrus"
+)
+
+// keepSessionAlive 按 -session-keepalive-interval 定期检查登录状态，直到 ctx 结束。
+// 检查会打开需要登录的首页并重新保存 cookies，避免长时间空闲后会话过期，
+// 空闲后的第一个请求才发现未登录；检测到登录过期时与 monitorLogin 一样推送 notifications/login_expired。
+// 与 monitorLogin 不同，没有连接订阅通知时也会执行；已知未登录时跳过，等待重新登录
+func (s *AppServer) keepSessionAlive(ctx context.Context, interval time.Duration) {
+	if interval <= 0 {
+		return
+	}
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if loggedIn, known := s.loginState.Get(); known && !loggedIn {
+				continue
+			}
+
+			status, err := s.xiaohongshuService.CheckLoginStatus(ctx)
+			if err != nil {
+				logrus.Warnf("登录会话保活失败: %v", err)
+				continue
+			}
+			if status.IsLoggedIn {
+				logrus.Debug("登录会话保活完成，cookies 已更新")
+			}
+			s.updateLoginState(status)
+		}
+	}
+}
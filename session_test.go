@@ -0,0 +1,58 @@
+package main
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSessionStoreExpiry(t *testing.T) {
+	store := newSessionStore(20 * time.Millisecond)
+	httpSession := store.Create()
+	wsSession := store.CreateForConnection()
+
+	time.Sleep(40 * time.Millisecond)
+
+	_, ok := store.Get(httpSession.ID)
+	assert.False(t, ok, "空闲超时的会话应被清理")
+	_, ok = store.Get(wsSession.ID)
+	assert.True(t, ok, "WebSocket 会话在连接断开前不应被清理")
+
+	assert.True(t, store.Delete(wsSession.ID))
+	_, ok = store.Get(wsSession.ID)
+	assert.False(t, ok)
+}
+
+func TestSessionStoreGetRefreshes(t *testing.T) {
+	store := newSessionStore(50 * time.Millisecond)
+	session := store.Create()
+
+	for range 4 {
+		time.Sleep(20 * time.Millisecond)
+		_, ok := store.Get(session.ID)
+		require.True(t, ok, "有请求的会话不应过期")
+	}
+}
+
+func TestSessionStoreMarkInitialized(t *testing.T) {
+	store := newSessionStore(time.Minute)
+	session := store.Create()
+	assert.False(t, session.Initialized)
+
+	store.MarkInitialized(session.ID)
+	got, ok := store.Get(session.ID)
+	require.True(t, ok)
+	assert.True(t, got.Initialized)
+	assert.False(t, session.Initialized, "Get 返回副本，不影响之前取到的会话")
+}
+
+func TestRequiresInitialized(t *testing.T) {
+	for _, method := range []string{"initialize", "ping", "initialized", "notifications/initialized"} {
+		assert.False(t, requiresInitialized(method), method)
+	}
+	for _, method := range []string{"tools/list", "tools/call"} {
+		assert.True(t, requiresInitialized(method), method)
+	}
+}
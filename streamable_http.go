@@ -3,21 +3,31 @@ package main
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
 	"net/http"
 	"strings"
 
 	"github.com/sirupsen/logrus"
+	"github.com/xpzouying/xiaohongshu-mcp/configs"
+	"github.com/xpzouying/xiaohongshu-mcp/xiaohongshu"
 )
 
 // StreamableHTTPHandler 处理 Streamable HTTP 协议的 MCP 请求
 func (s *AppServer) StreamableHTTPHandler() http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
+		// WebSocket 传输，与 Streamable HTTP 共用同一套请求分发逻辑
+		if strings.TrimSuffix(r.URL.Path, "/") == "/mcp/ws" {
+			s.handleWebSocket(w, r)
+			return
+		}
+
 		// 设置 CORS 头
-		w.Header().Set("Access-Control-Allow-Origin", "*")
-		w.Header().Set("Access-Control-Allow-Methods", "GET, POST, OPTIONS")
+		setAllowOrigin(w.Header(), r.Header.Get("Origin"))
+		w.Header().Set("Access-Control-Allow-Methods", "GET, POST, DELETE, OPTIONS")
 		w.Header().Set("Access-Control-Allow-Headers", "Content-Type, Accept, Mcp-Session-Id")
+		w.Header().Set("Access-Control-Expose-Headers", "Mcp-Session-Id")
 
 		// 处理 OPTIONS 请求
 		if r.Method == "OPTIONS" {
@@ -33,6 +43,9 @@ func (s *AppServer) StreamableHTTPHandler() http.HandlerFunc {
 		case "POST":
 			// POST 请求处理 JSON-RPC
 			s.handleJSONRPCRequest(w, r)
+		case "DELETE":
+			// DELETE 请求结束会话
+			s.handleSessionDelete(w, r)
 		default:
 			http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
 		}
@@ -47,6 +60,17 @@ func (s *AppServer) handleSSEConnection(w http.ResponseWriter, r *http.Request)
 		return
 	}
 
+	// SSE 连接需要在 initialize 和 initialized 通知之后建立
+	if !s.checkSession(w, r, nil, "") {
+		return
+	}
+
+	release, ok := s.acquireConnection(w, r, "sse")
+	if !ok {
+		return
+	}
+	defer release()
+
 	// 设置 SSE 响应头
 	w.Header().Set("Content-Type", "text/event-stream")
 	w.Header().Set("Cache-Control", "no-cache")
@@ -56,19 +80,50 @@ func (s *AppServer) handleSSEConnection(w http.ResponseWriter, r *http.Request)
 	fmt.Fprintf(w, "event: open\n")
 	fmt.Fprintf(w, "data: {\"type\":\"connection\",\"status\":\"connected\"}\n\n")
 
-	if f, ok := w.(http.Flusher); ok {
-		f.Flush()
+	flusher, _ := w.(http.Flusher)
+	if flusher != nil {
+		flusher.Flush()
 	}
 
-	// 保持连接打开（实际使用中可以在这里推送通知）
-	<-r.Context().Done()
+	// 保持连接打开，推送服务端通知
+	notifications := s.notifications.Subscribe()
+	defer s.notifications.Unsubscribe(notifications)
+
+	for {
+		select {
+		case <-r.Context().Done():
+			return
+		case notification := <-notifications:
+			data, err := json.Marshal(notification)
+			if err != nil {
+				logrus.WithError(err).Error("Failed to marshal SSE notification")
+				continue
+			}
+
+			fmt.Fprintf(w, "event: message\ndata: %s\n\n", string(data))
+			if flusher != nil {
+				flusher.Flush()
+			}
+		}
+	}
 }
 
 // handleJSONRPCRequest 处理 JSON-RPC 请求
 func (s *AppServer) handleJSONRPCRequest(w http.ResponseWriter, r *http.Request) {
-	// 读取请求体
-	body, err := io.ReadAll(r.Body)
+	// 读取请求体，超过 -max-body-bytes 时返回 413，避免超大请求耗尽内存
+	body, err := io.ReadAll(http.MaxBytesReader(w, r.Body, configs.GetMaxBodyBytes()))
 	if err != nil {
+		var maxBytesErr *http.MaxBytesError
+		if errors.As(err, &maxBytesErr) {
+			s.sendJSONResponseWithStatus(w, http.StatusRequestEntityTooLarge, &JSONRPCResponse{
+				JSONRPC: "2.0",
+				Error: &JSONRPCError{
+					Code:    -32700,
+					Message: fmt.Sprintf("Parse error: request body exceeds %d bytes", maxBytesErr.Limit),
+				},
+			})
+			return
+		}
 		s.sendStreamableError(w, nil, -32700, "Parse error")
 		return
 	}
@@ -81,7 +136,17 @@ func (s *AppServer) handleJSONRPCRequest(w http.ResponseWriter, r *http.Request)
 		return
 	}
 
-	logrus.WithField("method", request.Method).Info("Received Streamable HTTP request")
+	logrus.WithContext(r.Context()).WithField("method", request.Method).Info("Received Streamable HTTP request")
+
+	// 除 initialize 外的方法都需要携带有效的会话ID
+	if requiresSession(request.Method) {
+		if !s.checkSession(w, r, request.ID, request.Method) {
+			return
+		}
+		if isInitializedNotification(request.Method) {
+			s.sessions.MarkInitialized(r.Header.Get(mcpSessionHeader))
+		}
+	}
 
 	// 检查 Accept 头，判断客户端是否支持 SSE
 	acceptSSE := strings.Contains(r.Header.Get("Accept"), "text/event-stream")
@@ -89,6 +154,19 @@ func (s *AppServer) handleJSONRPCRequest(w http.ResponseWriter, r *http.Request)
 	// 处理请求
 	response := s.processJSONRPCRequest(&request, r.Context())
 
+	// initialize 成功后分配会话ID
+	if request.Method == "initialize" && response.Error == nil {
+		session := s.sessions.Create()
+		w.Header().Set(mcpSessionHeader, session.ID)
+		logrus.WithField("session", session.ID).Info("MCP session created")
+	}
+
+	// 通知不返回响应，只确认已接收
+	if request.IsNotification() {
+		w.WriteHeader(http.StatusAccepted)
+		return
+	}
+
 	// 如果需要 SSE 且是支持流式的方法，使用 SSE 响应
 	if acceptSSE && s.isStreamableMethod(request.Method) {
 		s.sendSSEResponse(w, response)
@@ -98,13 +176,57 @@ func (s *AppServer) handleJSONRPCRequest(w http.ResponseWriter, r *http.Request)
 	}
 }
 
+// checkSession 校验请求携带的会话ID，并确认客户端已发送 initialized 通知，
+// 校验失败时直接写入错误响应并返回 false。id 和 method 为 JSON-RPC 请求的 ID 和方法，
+// SSE 的 GET 请求不是 JSON-RPC 请求，id 为 nil、method 为空，同样需要完成初始化
+func (s *AppServer) checkSession(w http.ResponseWriter, r *http.Request, id any, method string) bool {
+	sessionID := r.Header.Get(mcpSessionHeader)
+	if sessionID == "" {
+		s.sendJSONResponseWithStatus(w, http.StatusBadRequest,
+			sessionRequiredError(id, "Bad Request: Mcp-Session-Id header is required, call initialize first"))
+		return false
+	}
+
+	session, ok := s.sessions.Get(sessionID)
+	if !ok {
+		s.sendJSONResponseWithStatus(w, http.StatusNotFound,
+			sessionRequiredError(id, "Session not found, call initialize to start a new session"))
+		return false
+	}
+
+	if !session.Initialized && requiresInitialized(method) {
+		s.sendJSONResponseWithStatus(w, http.StatusBadRequest,
+			sessionRequiredError(id, notInitializedMessage))
+		return false
+	}
+
+	return true
+}
+
+// handleSessionDelete 处理 DELETE 请求，结束会话
+func (s *AppServer) handleSessionDelete(w http.ResponseWriter, r *http.Request) {
+	sessionID := r.Header.Get(mcpSessionHeader)
+	if sessionID == "" {
+		http.Error(w, "Mcp-Session-Id header is required", http.StatusBadRequest)
+		return
+	}
+
+	if !s.sessions.Delete(sessionID) {
+		http.Error(w, "Session not found", http.StatusNotFound)
+		return
+	}
+
+	logrus.WithField("session", sessionID).Info("MCP session terminated")
+	w.WriteHeader(http.StatusOK)
+}
+
 // processJSONRPCRequest 处理 JSON-RPC 请求并返回响应
 func (s *AppServer) processJSONRPCRequest(request *JSONRPCRequest, ctx context.Context) *JSONRPCResponse {
 	switch request.Method {
 	case "initialize":
 		return s.processInitialize(request)
-	case "initialized":
-		// 客户端确认初始化完成
+	case "initialized", "notifications/initialized":
+		// 客户端确认初始化完成，以通知发送时响应会被丢弃
 		return &JSONRPCResponse{
 			JSONRPC: "2.0",
 			Result:  map[string]interface{}{},
@@ -138,11 +260,14 @@ func (s *AppServer) processInitialize(request *JSONRPCRequest) *JSONRPCResponse
 	result := map[string]interface{}{
 		"protocolVersion": "2025-03-26", // 使用新的协议版本
 		"capabilities": map[string]interface{}{
-			"tools": map[string]interface{}{},
+			"tools": map[string]interface{}{
+				// 登录状态变化时会推送 notifications/tools/list_changed
+				"listChanged": true,
+			},
 		},
 		"serverInfo": map[string]interface{}{
 			"name":    "xiaohongshu-mcp",
-			"version": "2.0.0",
+			"version": version,
 		},
 	}
 
@@ -153,12 +278,28 @@ func (s *AppServer) processInitialize(request *JSONRPCRequest) *JSONRPCResponse
 	}
 }
 
-// processToolsList 处理工具列表请求
-func (s *AppServer) processToolsList(request *JSONRPCRequest) *JSONRPCResponse {
+// toolDefinitions 返回所有工具的定义，调试工具只在 -debug 模式下提供
+func toolDefinitions() []map[string]interface{} {
 	tools := []map[string]interface{}{
 		{
 			"name":        "check_login_status",
-			"description": "检查小红书登录状态",
+			"description": "检查小红书登录状态，已登录时同时返回当前账号的 user_id、昵称和头像，用于确认正在操作的是哪个账号",
+			"inputSchema": map[string]interface{}{
+				"type":       "object",
+				"properties": map[string]interface{}{},
+			},
+		},
+		{
+			"name":        "check_all_logins",
+			"description": "批量检查所有已配置账号的登录状态，返回每个账号的登录状态以及已登录、需要重新登录、检查失败的账号数，用于找出需要重新登录的账号",
+			"inputSchema": map[string]interface{}{
+				"type":       "object",
+				"properties": map[string]interface{}{},
+			},
+		},
+		{
+			"name":        "logout",
+			"description": "退出当前小红书账号并删除服务保存的登录会话，用于切换账号或清理失效的会话；退出后需要重新登录",
 			"inputSchema": map[string]interface{}{
 				"type":       "object",
 				"properties": map[string]interface{}{},
@@ -176,35 +317,82 @@ func (s *AppServer) processToolsList(request *JSONRPCRequest) *JSONRPCResponse {
 					},
 					"content": map[string]interface{}{
 						"type":        "string",
-						"description": "正文内容，不包含以#开头的标签内容，所有话题标签都用tags参数来生成和提供即可",
+						"description": fmt.Sprintf("正文内容，不包含以#开头的标签内容，所有话题标签都用tags参数来生成和提供即可。最多约%d个中文字（按宽度计算，中文字计2、英文字母和数字计1，总宽度不超过%d）", configs.GetMaxContentWidth()/2, configs.GetMaxContentWidth()),
 					},
 					"images": map[string]interface{}{
 						"type":        "array",
-						"description": "图片路径列表（至少需要1张图片）。支持两种方式：1. HTTP/HTTPS图片链接（自动下载）；2. 本地图片绝对路径（推荐，如:/Users/user/image.jpg）",
+						"description": fmt.Sprintf("图片路径列表（至少需要1张图片，最多%d张）。支持三种方式：1. HTTP/HTTPS图片链接（自动下载）；2. 本地图片绝对路径（推荐，如:/Users/user/image.jpg）；3. base64 编码的 data URL（如:data:image/png;base64,...，支持 JPEG、PNG、WebP，解码后不超过20MB）", configs.GetMaxImages()),
 						"items": map[string]interface{}{
 							"type": "string",
 						},
 						"minItems": 1,
+						"maxItems": configs.GetMaxImages(),
+					},
+					"image_alts": map[string]interface{}{
+						"type":        "array",
+						"description": "每张图片的描述（替代文字，可选），按序号与images对应，数量必须与images一致。小红书发布页目前不支持图片描述，提供时会被忽略并在结果中返回警告",
+						"items": map[string]interface{}{
+							"type": "string",
+						},
 					},
 					"tags": map[string]interface{}{
 						"type":        "array",
-						"description": "话题标签列表（可选），如 [\"美食\", \"旅行\", \"生活\"]",
+						"description": "话题标签列表（可选），如 [\"美食\", \"旅行\", \"生活\"]。开头的#会被去掉，重复标签自动合并，最多10个",
 						"items": map[string]interface{}{
 							"type": "string",
 						},
 					},
+					"cover_index": map[string]interface{}{
+						"type":        "integer",
+						"description": "封面图片在images中的序号（可选，默认0即第一张）。选中的图片会第一个上传作为封面，其余图片保持原有顺序，序号超出images范围时返回参数错误",
+						"minimum":     0,
+						"maximum":     configs.GetMaxImages() - 1,
+					},
+					"visibility": map[string]interface{}{
+						"type":        "string",
+						"description": "可见范围（可选）：public 公开可见（默认）、friends 仅互关好友可见、private 仅自己可见。笔记发出后立即改为指定范围，修改失败时笔记保持公开，并在结果的warnings中说明",
+						"enum":        []string{"public", "friends", "private"},
+					},
+					"image_referer": map[string]interface{}{
+						"type":        "string",
+						"description": "下载HTTP/HTTPS图片时使用的 Referer（可选），用于有防盗链的图床",
+					},
+					"image_headers": map[string]interface{}{
+						"type":        "object",
+						"description": "下载HTTP/HTTPS图片时附加的请求头（可选），如 {\"Authorization\": \"Bearer ...\"}，用于需要鉴权的图床",
+					},
+					"visible": visibleBrowserProperty,
 				},
 				"required": []string{"title", "content", "images"},
 			},
 		},
 		{
 			"name":        "list_feeds",
-			"description": "获取用户发布的内容列表",
+			"description": "获取用户发布的内容列表，每条内容包含点赞/收藏/评论/分享数（原始文本及解析后的数值）",
 			"inputSchema": map[string]interface{}{
 				"type":       "object",
 				"properties": map[string]interface{}{},
 			},
 		},
+		{
+			"name":        "explore_feeds",
+			"description": "获取小红书发现页的推荐笔记，不需要关键词，可选择频道（如美食、穿搭），用于观察当前的热门内容。推荐内容每次获取都不同，不支持游标分页",
+			"inputSchema": map[string]interface{}{
+				"type": "object",
+				"properties": map[string]interface{}{
+					"channel": map[string]interface{}{
+						"type":        "string",
+						"description": "频道名称：" + strings.Join(xiaohongshu.ExploreChannelNames(), "、") + "；也可以直接传 homefeed 开头的频道ID。默认为推荐",
+					},
+					"limit": map[string]interface{}{
+						"type":        "integer",
+						"description": "返回的笔记数量（可选，默认30）",
+						"minimum":     1,
+						"maximum":     xiaohongshu.MaxExploreFeedsLimit,
+					},
+				},
+			},
+		},
 		{
 			"name":        "search_feeds",
 			"description": "搜索小红书内容（需要已登录）",
@@ -215,13 +403,63 @@ func (s *AppServer) processToolsList(request *JSONRPCRequest) *JSONRPCResponse {
 						"type":        "string",
 						"description": "搜索关键词",
 					},
+					"fresh": map[string]interface{}{
+						"type":        "boolean",
+						"description": "跳过缓存重新搜索（可选，仅在服务开启 -cache-ttl 时有意义）",
+					},
+				},
+				"required": []string{"keyword"},
+			},
+		},
+		{
+			"name":        "search_and_detail",
+			"description": "搜索小红书内容并获取前 N 篇笔记的详情（需要已登录），在同一个浏览器会话中完成，减少“搜索 + 逐篇获取详情”的调用次数；单篇详情获取失败时在该条结果的 error 和 warnings 中说明，不影响其他笔记",
+			"inputSchema": map[string]interface{}{
+				"type": "object",
+				"properties": map[string]interface{}{
+					"keyword": map[string]interface{}{
+						"type":        "string",
+						"description": "搜索关键词",
+					},
+					"limit": map[string]interface{}{
+						"type":        "integer",
+						"description": "获取详情的笔记数量（可选，默认5，最多20）",
+						"minimum":     1,
+						"maximum":     maxSearchDetailLimit,
+					},
+					"fresh": map[string]interface{}{
+						"type":        "boolean",
+						"description": "跳过缓存重新搜索和获取详情（可选，仅在服务开启 -cache-ttl 时有意义）",
+					},
 				},
 				"required": []string{"keyword"},
 			},
 		},
+		{
+			"name":        "search_topics",
+			"description": "搜索小红书话题（#标签），返回话题名称及浏览量，可用于发布前挑选高流量话题",
+			"inputSchema": map[string]interface{}{
+				"type": "object",
+				"properties": map[string]interface{}{
+					"keyword": map[string]interface{}{
+						"type":        "string",
+						"description": "话题关键词，不需要带#",
+					},
+				},
+				"required": []string{"keyword"},
+			},
+		},
+		{
+			"name":        "trending_topics",
+			"description": "获取小红书当前热门话题，返回话题名称及浏览量、参与量",
+			"inputSchema": map[string]interface{}{
+				"type":       "object",
+				"properties": map[string]interface{}{},
+			},
+		},
 		{
 			"name":        "get_feed_detail",
-			"description": "获取小红书笔记详情，返回笔记内容、图片、作者信息、互动数据（点赞/收藏/分享数）及评论列表",
+			"description": "获取小红书笔记详情，返回笔记内容、图片、作者信息、互动数据（点赞/收藏/分享数）及评论列表；视频笔记另外返回 video_url（含音频的视频地址）或分段播放的 video_manifest_url",
 			"inputSchema": map[string]interface{}{
 				"type": "object",
 				"properties": map[string]interface{}{
@@ -233,13 +471,231 @@ func (s *AppServer) processToolsList(request *JSONRPCRequest) *JSONRPCResponse {
 						"type":        "string",
 						"description": "访问令牌，从Feed列表的xsecToken字段获取",
 					},
+					"fresh": map[string]interface{}{
+						"type":        "boolean",
+						"description": "跳过缓存重新获取（可选，仅在服务开启 -cache-ttl 时有意义）",
+					},
+				},
+				"required": []string{"feed_id", "xsec_token"},
+			},
+		},
+		{
+			"name":        "get_feed_by_url",
+			"description": "通过分享链接获取小红书笔记详情，支持 xiaohongshu.com/explore/... 完整链接和 xhslink.com 短链",
+			"inputSchema": map[string]interface{}{
+				"type": "object",
+				"properties": map[string]interface{}{
+					"url": map[string]interface{}{
+						"type":        "string",
+						"description": "笔记分享链接，需包含 xsec_token（App/网页“分享”复制的链接均可）",
+					},
+				},
+				"required": []string{"url"},
+			},
+		},
+		{
+			"name":        "get_feed_link",
+			"description": "获取小红书笔记的可点击链接，默认直接根据笔记ID和 xsec_token 构造网页链接，不打开浏览器；short 为 true 时打开详情页通过“复制链接”获取 xhslink 分享短链",
+			"inputSchema": map[string]interface{}{
+				"type": "object",
+				"properties": map[string]interface{}{
+					"feed_id": map[string]interface{}{
+						"type":        "string",
+						"description": "小红书笔记ID，从Feed列表获取",
+					},
+					"xsec_token": map[string]interface{}{
+						"type":        "string",
+						"description": "访问令牌，从Feed列表的xsecToken字段获取",
+					},
+					"short": map[string]interface{}{
+						"type":        "boolean",
+						"description": "是否获取分享短链（可选，默认 false）；需要打开浏览器，详情页没有复制链接入口时只返回网页链接",
+					},
+				},
+				"required": []string{"feed_id", "xsec_token"},
+			},
+		},
+		{
+			"name":        "get_feed_tags",
+			"description": "获取小红书笔记的话题标签（#话题），只返回话题名称、话题ID和话题页链接，比完整的笔记详情小得多，适合做话题趋势统计",
+			"inputSchema": map[string]interface{}{
+				"type": "object",
+				"properties": map[string]interface{}{
+					"feed_id": map[string]interface{}{
+						"type":        "string",
+						"description": "小红书笔记ID，从Feed列表获取",
+					},
+					"xsec_token": map[string]interface{}{
+						"type":        "string",
+						"description": "访问令牌，从Feed列表的xsecToken字段获取",
+					},
+				},
+				"required": []string{"feed_id", "xsec_token"},
+			},
+		},
+		{
+			"name":        "related_feeds",
+			"description": "获取小红书笔记详情页“相关推荐”中的笔记列表，每篇笔记带有自己的 xsecToken，可直接用于获取详情。推荐没有加载出来时返回空列表，并在 warnings 中说明",
+			"inputSchema": map[string]interface{}{
+				"type": "object",
+				"properties": map[string]interface{}{
+					"feed_id": map[string]interface{}{
+						"type":        "string",
+						"description": "小红书笔记ID，从Feed列表获取",
+					},
+					"xsec_token": map[string]interface{}{
+						"type":        "string",
+						"description": "访问令牌，从Feed列表的xsecToken字段获取",
+					},
+				},
+				"required": []string{"feed_id", "xsec_token"},
+			},
+		},
+		{
+			"name":        "screenshot_feed",
+			"description": "打开小红书笔记详情页并截取 PNG 图片，以 image 内容返回。可指定视口宽度以获得一致的渲染效果；图片高度超过 8000 像素时只保留上半部分，并在 warnings 中说明",
+			"inputSchema": map[string]interface{}{
+				"type": "object",
+				"properties": map[string]interface{}{
+					"feed_id": map[string]interface{}{
+						"type":        "string",
+						"description": "小红书笔记ID，从Feed列表获取",
+					},
+					"xsec_token": map[string]interface{}{
+						"type":        "string",
+						"description": "访问令牌，从Feed列表的xsecToken字段获取",
+					},
+					"width": map[string]interface{}{
+						"type":        "integer",
+						"description": "截图时的视口宽度（像素），320 到 1920，默认使用服务的 -viewport 宽度",
+						"minimum":     320,
+						"maximum":     1920,
+					},
+					"scope": map[string]interface{}{
+						"type":        "string",
+						"description": "截图范围：note 只截取笔记区域（默认），page 截取整个页面",
+						"enum":        []string{"note", "page"},
+					},
+				},
+				"required": []string{"feed_id", "xsec_token"},
+			},
+		},
+		{
+			"name":        "edit_feed",
+			"description": "编辑已发布的小红书笔记，只修改提供了的字段（标题、正文、话题、图片），返回修改后的笔记详情。部分笔记不支持修改图片",
+			"inputSchema": map[string]interface{}{
+				"type": "object",
+				"properties": map[string]interface{}{
+					"feed_id": map[string]interface{}{
+						"type":        "string",
+						"description": "小红书笔记ID",
+					},
+					"xsec_token": map[string]interface{}{
+						"type":        "string",
+						"description": "访问令牌，从Feed列表的xsecToken字段获取",
+					},
+					"title": map[string]interface{}{
+						"type":        "string",
+						"description": "新标题（可选）",
+					},
+					"content": map[string]interface{}{
+						"type":        "string",
+						"description": fmt.Sprintf("新正文（可选），替换正文会一并替换原有话题。长度限制与发布相同，总宽度不超过%d", configs.GetMaxContentWidth()),
+					},
+					"tags": map[string]interface{}{
+						"type":        "array",
+						"description": "新话题标签列表（可选），会替换原有话题",
+						"items": map[string]interface{}{
+							"type": "string",
+						},
+					},
+					"images": map[string]interface{}{
+						"type":        "array",
+						"description": "新图片列表（可选），会替换原有图片，支持HTTP链接、本地绝对路径或 base64 data URL",
+						"items": map[string]interface{}{
+							"type": "string",
+						},
+						"maxItems": configs.GetMaxImages(),
+					},
+				},
+				"required": []string{"feed_id", "xsec_token"},
+			},
+		},
+		{
+			"name":        "hide_feed",
+			"description": "修改已发布笔记的可见范围，默认设为仅自己可见，用于临时下架笔记而不删除（保留点赞、评论等数据），之后可改回 public 重新公开。只能修改当前账号自己的笔记",
+			"inputSchema": map[string]interface{}{
+				"type": "object",
+				"properties": map[string]interface{}{
+					"feed_id": map[string]interface{}{
+						"type":        "string",
+						"description": "小红书笔记ID",
+					},
+					"xsec_token": map[string]interface{}{
+						"type":        "string",
+						"description": "访问令牌，从Feed列表的xsecToken字段获取",
+					},
+					"visibility": map[string]interface{}{
+						"type":        "string",
+						"description": "可见范围（可选）：private 仅自己可见（默认）、friends 仅互关好友可见、public 公开可见",
+						"enum":        []string{"private", "friends", "public"},
+					},
 				},
 				"required": []string{"feed_id", "xsec_token"},
 			},
 		},
 		{
 			"name":        "user_profile",
-			"description": "获取小红书用户主页，返回用户基本信息，关注、粉丝、获赞量及其笔记内容",
+			"description": "获取小红书用户主页，返回用户基本信息，关注、粉丝、获赞量及其笔记内容。需要提供 user_id+xsec_token，或者只提供 profile_url",
+			"inputSchema": map[string]interface{}{
+				"type": "object",
+				"properties": map[string]interface{}{
+					"user_id": map[string]interface{}{
+						"type":        "string",
+						"description": "小红书用户ID，从Feed列表获取",
+					},
+					"xsec_token": map[string]interface{}{
+						"type":        "string",
+						"description": "访问令牌，从Feed列表的xsecToken字段获取",
+					},
+					"profile_url": map[string]interface{}{
+						"type":        "string",
+						"description": "用户主页链接（可选），如 https://www.xiaohongshu.com/user/profile/<user_id> 或 xhslink.com 分享短链，提供时无需 user_id 和 xsec_token。不支持只提供小红书号",
+					},
+				},
+			},
+		},
+		{
+			"name":        "user_feeds",
+			"description": "分页获取小红书用户主页的全部笔记（user_profile 只返回第一页），通过滚动主页加载更多。私密账号返回 PROFILE_PRIVATE 错误",
+			"inputSchema": map[string]interface{}{
+				"type": "object",
+				"properties": map[string]interface{}{
+					"user_id": map[string]interface{}{
+						"type":        "string",
+						"description": "小红书用户ID，从Feed列表获取",
+					},
+					"xsec_token": map[string]interface{}{
+						"type":        "string",
+						"description": "访问令牌，从Feed列表的xsecToken字段获取",
+					},
+					"limit": map[string]interface{}{
+						"type":        "integer",
+						"description": "本次返回的笔记数量（可选，默认30，最多200）",
+						"minimum":     1,
+						"maximum":     200,
+					},
+					"cursor": map[string]interface{}{
+						"type":        "string",
+						"description": "上一次返回的 next_cursor（可选），为空时从第一条开始；返回结果中没有 next_cursor 表示没有更多",
+					},
+				},
+				"required": []string{"user_id", "xsec_token"},
+			},
+		},
+		{
+			"name":        "user_collections",
+			"description": "获取小红书用户主页“收藏”页签中公开的收藏专辑，返回每个专辑的名称、笔记数量和专辑页链接。私密账号或用户隐藏了收藏时返回空列表，并在 warnings 中说明原因",
 			"inputSchema": map[string]interface{}{
 				"type": "object",
 				"properties": map[string]interface{}{
@@ -255,6 +711,76 @@ func (s *AppServer) processToolsList(request *JSONRPCRequest) *JSONRPCResponse {
 				"required": []string{"user_id", "xsec_token"},
 			},
 		},
+		{
+			"name":        "my_profile",
+			"description": "获取当前登录账号的主页信息，返回用户基本信息、笔记列表，以及关注、粉丝、获赞与收藏等互动数据汇总，无需参数",
+			"inputSchema": map[string]interface{}{
+				"type":       "object",
+				"properties": map[string]interface{}{},
+			},
+		},
+		{
+			"name":        "feed_analytics",
+			"description": "从创作者中心获取当前账号某篇笔记的数据：观看数、曝光数、观看人数、点赞/评论/收藏/分享、涨粉数、封面点击率和互动率。这些数据只有作者能看到，笔记不属于当前账号时返回 NOT_OWNER",
+			"inputSchema": map[string]interface{}{
+				"type": "object",
+				"properties": map[string]interface{}{
+					"feed_id": map[string]interface{}{
+						"type":        "string",
+						"description": "当前账号的笔记ID，可从 my_profile 的笔记列表获取",
+					},
+				},
+				"required": []string{"feed_id"},
+			},
+		},
+		{
+			"name":        "get_comment_tree",
+			"description": "获取笔记的评论树：顶层评论分页返回，每条评论自动点击“展开更多回复”加载回复，回复嵌套在被回复的评论下；每个节点包含作者、内容、点赞数和发表时间（毫秒时间戳）。more_replies 为 true 表示还有回复未加载，可增大 replies_limit 重新获取",
+			"inputSchema": map[string]interface{}{
+				"type": "object",
+				"properties": map[string]interface{}{
+					"feed_id": map[string]interface{}{
+						"type":        "string",
+						"description": "小红书笔记ID，从Feed列表获取",
+					},
+					"xsec_token": map[string]interface{}{
+						"type":        "string",
+						"description": "访问令牌，从Feed列表的xsecToken字段获取",
+					},
+					"limit": map[string]interface{}{
+						"type":        "integer",
+						"description": "本页顶层评论数量（可选，默认20，最多100）",
+						"minimum":     1,
+						"maximum":     100,
+					},
+					"replies_limit": map[string]interface{}{
+						"type":        "integer",
+						"description": "每条顶层评论最多加载的回复数量（可选，默认10，最多100）",
+						"minimum":     1,
+						"maximum":     100,
+					},
+					"cursor": map[string]interface{}{
+						"type":        "string",
+						"description": "上一次返回的 next_cursor（可选），为空时从第一条评论开始；返回结果中没有 next_cursor 表示没有更多",
+					},
+				},
+				"required": []string{"feed_id", "xsec_token"},
+			},
+		},
+		{
+			"name":        "list_notifications",
+			"description": "获取当前账号通知中心最近的通知（赞和收藏、评论和@、新增关注），返回类别、触发用户、评论内容、相关笔记和时间，可用于自动回复评论",
+			"inputSchema": map[string]interface{}{
+				"type": "object",
+				"properties": map[string]interface{}{
+					"category": map[string]interface{}{
+						"type":        "string",
+						"description": "通知类别（可选）：likes 赞和收藏，comments 评论和回复，mentions @我，follows 新增关注；不传时返回全部",
+						"enum":        []string{"likes", "comments", "mentions", "follows"},
+					},
+				},
+			},
+		},
 		{
 			"name":        "post_comment_to_feed",
 			"description": "发表评论到小红书笔记",
@@ -273,12 +799,92 @@ func (s *AppServer) processToolsList(request *JSONRPCRequest) *JSONRPCResponse {
 						"type":        "string",
 						"description": "评论内容",
 					},
+					"image": map[string]interface{}{
+						"type":        "string",
+						"description": "评论图片（可选），支持本地绝对路径、HTTP/HTTPS 链接和 base64 data URL；笔记不支持图片评论时只发表文字并返回警告",
+					},
+					"visible": visibleBrowserProperty,
 				},
 				"required": []string{"feed_id", "xsec_token", "content"},
 			},
 		},
+		{
+			"name":        "post_comments",
+			"description": "批量发表评论到多篇小红书笔记，按顺序逐条发表，返回每条评论的成功/失败结果，单条失败不影响其余评论",
+			"inputSchema": map[string]interface{}{
+				"type": "object",
+				"properties": map[string]interface{}{
+					"comments": map[string]interface{}{
+						"type":        "array",
+						"description": "评论列表",
+						"items": map[string]interface{}{
+							"type": "object",
+							"properties": map[string]interface{}{
+								"feed_id": map[string]interface{}{
+									"type":        "string",
+									"description": "小红书笔记ID，从Feed列表获取",
+								},
+								"xsec_token": map[string]interface{}{
+									"type":        "string",
+									"description": "访问令牌，从Feed列表的xsecToken字段获取",
+								},
+								"content": map[string]interface{}{
+									"type":        "string",
+									"description": "评论内容",
+								},
+							},
+							"required": []string{"feed_id", "xsec_token", "content"},
+						},
+						"minItems": 1,
+					},
+					"delay_seconds": map[string]interface{}{
+						"type":        "number",
+						"description": "两条评论之间的间隔秒数（可选，默认5秒），用于模拟人工操作节奏",
+						"minimum":     0,
+					},
+					"visible": visibleBrowserProperty,
+				},
+				"required": []string{"comments"},
+			},
+		},
 	}
 
+	// 调试工具只在 -debug 模式下提供
+	if configs.IsDebug() {
+		tools = append(tools, debugPageHTMLTool)
+	}
+
+	return tools
+}
+
+// toolInputSchema 返回工具声明的 inputSchema，工具不存在时返回 nil
+func toolInputSchema(name string) map[string]interface{} {
+	for _, tool := range toolDefinitions() {
+		if tool["name"] == name {
+			schema, _ := tool["inputSchema"].(map[string]interface{})
+			return schema
+		}
+	}
+	return nil
+}
+
+// processToolsList 处理工具列表请求
+func (s *AppServer) processToolsList(request *JSONRPCRequest) *JSONRPCResponse {
+	tools := toolDefinitions()
+
+	// 已确认未登录时，隐藏需要登录的工具，避免客户端调用后立即失败；安全模式下隐藏写操作工具
+	loggedIn, known := s.loginState.Get()
+	hideLoginRequired := known && !loggedIn
+	available := tools[:0]
+	for _, tool := range tools {
+		name, _ := tool["name"].(string)
+		if (hideLoginRequired && loginRequiredTools[name]) || isToolDisabled(name) {
+			continue
+		}
+		available = append(available, tool)
+	}
+	tools = available
+
 	return &JSONRPCResponse{
 		JSONRPC: "2.0",
 		Result: map[string]interface{}{
@@ -288,59 +894,171 @@ func (s *AppServer) processToolsList(request *JSONRPCRequest) *JSONRPCResponse {
 	}
 }
 
+// visibleBrowserProperty 写操作工具的 visible 参数，需要以 -allow-visible-browser 启动服务
+var visibleBrowserProperty = map[string]interface{}{
+	"type":        "boolean",
+	"description": "本次操作使用有界面的浏览器（可选，默认跟随服务的 -headless 设置），便于观察操作过程排查问题，需要服务以 -allow-visible-browser 启动",
+}
+
+// loginRequiredTools 需要登录才能使用的工具，未登录时不出现在工具列表中
+var loginRequiredTools = map[string]bool{
+	"publish_content":      true,
+	"edit_feed":            true,
+	"hide_feed":            true,
+	"my_profile":           true,
+	"feed_analytics":       true,
+	"search_feeds":         true,
+	"search_and_detail":    true,
+	"search_topics":        true,
+	"trending_topics":      true,
+	"list_notifications":   true,
+	"post_comment_to_feed": true,
+	"post_comments":        true,
+}
+
+// debugPageHTMLTool 调试工具，用于在选择器失效时查看页面的实际内容
+var debugPageHTMLTool = map[string]interface{}{
+	"name":        "debug_page_html",
+	"description": "【调试】使用当前登录会话加载小红书页面，返回渲染后的 HTML 或整页截图（data URL），用于排查页面改版导致的操作失败",
+	"inputSchema": map[string]interface{}{
+		"type": "object",
+		"properties": map[string]interface{}{
+			"url": map[string]interface{}{
+				"type":        "string",
+				"description": "小红书页面链接，只支持 xiaohongshu.com 和 xhslink.com 域名",
+			},
+			"format": map[string]interface{}{
+				"type":        "string",
+				"description": "返回格式：html（默认）返回渲染后的 HTML，screenshot 返回 PNG 截图的 data URL",
+				"enum":        []string{"html", "screenshot"},
+			},
+		},
+		"required": []string{"url"},
+	},
+}
+
 // processToolCall 处理工具调用
 func (s *AppServer) processToolCall(ctx context.Context, request *JSONRPCRequest) *JSONRPCResponse {
 	// 解析参数
 	params, ok := request.Params.(map[string]interface{})
 	if !ok {
-		return &JSONRPCResponse{
-			JSONRPC: "2.0",
-			Error: &JSONRPCError{
-				Code:    -32602,
-				Message: "Invalid params",
-			},
-			ID: request.ID,
-		}
+		return toolCallError(request.ID, "Invalid params", ToolErrorData{
+			Code:   "INVALID_ARGS",
+			Detail: "params 必须是包含 name 和 arguments 的对象",
+		})
 	}
 
 	toolName, _ := params["name"].(string)
 	toolArgs, _ := params["arguments"].(map[string]interface{})
 
+	if isToolDisabled(toolName) {
+		return toolCallError(request.ID, "Operation disabled: "+toolName, ToolErrorData{
+			Tool:   toolName,
+			Code:   "OPERATION_DISABLED",
+			Detail: ErrOperationDisabled.Error(),
+		})
+	}
+
+	// 按工具声明的 inputSchema 统一校验参数，类型错误、缺少必填参数时不进入处理函数
+	if schema := toolInputSchema(toolName); schema != nil {
+		if err := validateToolArgs(schema, toolArgs); err != nil {
+			return toolCallError(request.ID, "Invalid params: "+err.Error(), ToolErrorData{
+				Tool:   toolName,
+				Code:   "INVALID_ARGS",
+				Detail: err.Error(),
+				Field:  err.Field,
+			})
+		}
+	}
+
 	var result *MCPToolResult
 
 	switch toolName {
 	case "check_login_status":
 		result = s.handleCheckLoginStatus(ctx)
+	case "check_all_logins":
+		result = s.handleCheckAllLogins(ctx)
+	case "logout":
+		result = s.handleLogout(ctx)
 	case "publish_content":
 		result = s.handlePublishContent(ctx, toolArgs)
 	case "list_feeds":
 		result = s.handleListFeeds(ctx)
 	case "search_feeds":
 		result = s.handleSearchFeeds(ctx, toolArgs)
+	case "explore_feeds":
+		result = s.handleExploreFeeds(ctx, toolArgs)
+	case "search_and_detail":
+		result = s.handleSearchAndDetail(ctx, toolArgs)
+	case "search_topics":
+		result = s.handleSearchTopics(ctx, toolArgs)
+	case "trending_topics":
+		result = s.handleTrendingTopics(ctx)
 	case "get_feed_detail":
 		result = s.handleGetFeedDetail(ctx, toolArgs)
+	case "get_feed_by_url":
+		result = s.handleGetFeedByURL(ctx, toolArgs)
+	case "get_feed_link":
+		result = s.handleGetFeedLink(ctx, toolArgs)
+	case "get_feed_tags":
+		result = s.handleGetFeedTags(ctx, toolArgs)
+	case "related_feeds":
+		result = s.handleRelatedFeeds(ctx, toolArgs)
+	case "screenshot_feed":
+		result = s.handleScreenshotFeed(ctx, toolArgs)
+	case "edit_feed":
+		result = s.handleEditFeed(ctx, toolArgs)
+	case "hide_feed":
+		result = s.handleHideFeed(ctx, toolArgs)
 	case "user_profile":
 		result = s.handleUserProfile(ctx, toolArgs)
+	case "user_feeds":
+		result = s.handleUserFeeds(ctx, toolArgs)
+	case "user_collections":
+		result = s.handleUserCollections(ctx, toolArgs)
+	case "feed_analytics":
+		result = s.handleFeedAnalytics(ctx, toolArgs)
+	case "my_profile":
+		result = s.handleMyProfile(ctx)
+	case "get_comment_tree":
+		result = s.handleGetCommentTree(ctx, toolArgs)
+	case "list_notifications":
+		result = s.handleListNotifications(ctx, toolArgs)
 	case "post_comment_to_feed":
 		result = s.handlePostComment(ctx, toolArgs)
-	default:
-		return &JSONRPCResponse{
-			JSONRPC: "2.0",
-			Error: &JSONRPCError{
-				Code:    -32602,
-				Message: fmt.Sprintf("Unknown tool: %s", toolName),
-			},
-			ID: request.ID,
+	case "post_comments":
+		result = s.handlePostComments(ctx, toolArgs)
+	case "debug_page_html":
+		// 未开启 -debug 时与未知工具一致，不暴露调试工具的存在
+		if !configs.IsDebug() {
+			return unknownToolError(request.ID, toolName)
 		}
+		result = s.handleDebugPageHTML(ctx, toolArgs)
+	default:
+		return unknownToolError(request.ID, toolName)
+	}
+
+	result = withToolErrorData(toolName, result)
+	if account := s.account.Get(); account != (AccountInfo{}) {
+		result.Meta = &ToolMeta{Account: account}
 	}
 
 	return &JSONRPCResponse{
 		JSONRPC: "2.0",
-		Result:  result,
+		Result:  limitToolResult(toolName, result),
 		ID:      request.ID,
 	}
 }
 
+// unknownToolError 调用不存在的工具时返回的错误
+func unknownToolError(id any, toolName string) *JSONRPCResponse {
+	return toolCallError(id, fmt.Sprintf("Unknown tool: %s", toolName), ToolErrorData{
+		Tool:   toolName,
+		Code:   "UNKNOWN_TOOL",
+		Detail: fmt.Sprintf("工具 %s 不存在", toolName),
+	})
+}
+
 // isStreamableMethod 判断方法是否支持流式响应
 func (s *AppServer) isStreamableMethod(_ string) bool {
 	// 目前我们的方法都不需要流式响应
@@ -350,7 +1068,13 @@ func (s *AppServer) isStreamableMethod(_ string) bool {
 
 // sendJSONResponse 发送普通 JSON 响应
 func (s *AppServer) sendJSONResponse(w http.ResponseWriter, response *JSONRPCResponse) {
+	s.sendJSONResponseWithStatus(w, http.StatusOK, response)
+}
+
+// sendJSONResponseWithStatus 发送指定 HTTP 状态码的 JSON 响应
+func (s *AppServer) sendJSONResponseWithStatus(w http.ResponseWriter, statusCode int, response *JSONRPCResponse) {
 	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(statusCode)
 
 	if err := json.NewEncoder(w).Encode(response); err != nil {
 		logrus.WithError(err).Error("Failed to encode response")
@@ -0,0 +1,187 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/xpzouying/xiaohongshu-mcp/configs"
+)
+
+func TestJSONRPCRequestIsNotification(t *testing.T) {
+	tests := []struct {
+		name string
+		body string
+		want bool
+	}{
+		{name: "no id", body: `{"jsonrpc": "2.0", "method": "notifications/initialized"}`, want: true},
+		{name: "null id", body: `{"jsonrpc": "2.0", "id": null, "method": "ping"}`},
+		{name: "number id", body: `{"jsonrpc": "2.0", "id": 1, "method": "ping"}`},
+		{name: "string id", body: `{"jsonrpc": "2.0", "id": "a", "method": "ping"}`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var request JSONRPCRequest
+			require.NoError(t, json.Unmarshal([]byte(tt.body), &request))
+			assert.Equal(t, tt.want, request.IsNotification())
+		})
+	}
+}
+
+// mcpPost 向 /mcp 发送 JSON-RPC 请求，sessionID 不为空时带上会话头
+func mcpPost(t *testing.T, router *gin.Engine, sessionID, body string) *httptest.ResponseRecorder {
+	t.Helper()
+
+	req := httptest.NewRequest(http.MethodPost, "/mcp", strings.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Accept", "application/json")
+	if sessionID != "" {
+		req.Header.Set(mcpSessionHeader, sessionID)
+	}
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	return w
+}
+
+func TestStreamableHTTPNotifications(t *testing.T) {
+	router, _ := newTestRouter(t)
+
+	w := mcpPost(t, router, "", `{"jsonrpc": "2.0", "id": 1, "method": "initialize", "params": {"protocolVersion": "2025-03-26"}}`)
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	sessionID := w.Header().Get(mcpSessionHeader)
+	require.NotEmpty(t, sessionID)
+
+	t.Run("tools before initialized", func(t *testing.T) {
+		w := mcpPost(t, router, sessionID, `{"jsonrpc": "2.0", "id": 2, "method": "tools/list"}`)
+		require.Equal(t, http.StatusBadRequest, w.Code, w.Body.String())
+
+		var response JSONRPCResponse
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+		require.NotNil(t, response.Error)
+		assert.Equal(t, -32000, response.Error.Code)
+	})
+
+	t.Run("notification without id", func(t *testing.T) {
+		w := mcpPost(t, router, sessionID, `{"jsonrpc": "2.0", "method": "notifications/initialized"}`)
+		assert.Equal(t, http.StatusAccepted, w.Code)
+		assert.Empty(t, w.Body.String())
+	})
+
+	t.Run("null id still gets a response", func(t *testing.T) {
+		w := mcpPost(t, router, sessionID, `{"jsonrpc": "2.0", "id": null, "method": "ping"}`)
+		require.Equal(t, http.StatusOK, w.Code)
+
+		var response map[string]json.RawMessage
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+		assert.JSONEq(t, `null`, string(response["id"]))
+		assert.JSONEq(t, `{}`, string(response["result"]))
+	})
+
+	t.Run("tools after initialized", func(t *testing.T) {
+		w := mcpPost(t, router, sessionID, `{"jsonrpc": "2.0", "id": 3, "method": "tools/list"}`)
+		require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+		assert.Contains(t, w.Body.String(), `"tools"`)
+	})
+}
+
+func TestToolCallVisibleBrowserNotAllowed(t *testing.T) {
+	configs.SetAllowVisibleBrowser(false)
+	router, _ := newTestRouter(t)
+
+	w := mcpPost(t, router, "", `{"jsonrpc": "2.0", "id": 1, "method": "initialize", "params": {"protocolVersion": "2025-03-26"}}`)
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	sessionID := w.Header().Get(mcpSessionHeader)
+	w = mcpPost(t, router, sessionID, `{"jsonrpc": "2.0", "method": "notifications/initialized"}`)
+	require.Equal(t, http.StatusAccepted, w.Code)
+
+	tests := []struct {
+		tool string
+		args string
+	}{
+		{tool: "publish_content", args: `{"title": "标题", "content": "正文", "images": ["https://example.com/1.jpg"], "visible": true}`},
+		{tool: "post_comment_to_feed", args: `{"feed_id": "1", "xsec_token": "t", "content": "c", "visible": true}`},
+		{tool: "post_comments", args: `{"comments": [{"feed_id": "1", "xsec_token": "t", "content": "c"}], "visible": true}`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.tool, func(t *testing.T) {
+			w := mcpPost(t, router, sessionID, `{"jsonrpc": "2.0", "id": 2, "method": "tools/call", "params": {"name": "`+tt.tool+`", "arguments": `+tt.args+`}}`)
+			require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+
+			var response struct {
+				Result struct {
+					IsError           bool          `json:"isError"`
+					StructuredContent ToolErrorData `json:"structuredContent"`
+				} `json:"result"`
+			}
+			require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+			assert.True(t, response.Result.IsError)
+			assert.Equal(t, "INVALID_ARGS", response.Result.StructuredContent.Code)
+			assert.NotContains(t, response.Result.StructuredContent.Detail, "PUBLISH_FAILED")
+		})
+	}
+}
+
+func TestStreamableHTTPSSESession(t *testing.T) {
+	router, _ := newTestRouter(t)
+
+	sseGet := func(t *testing.T, sessionID string, timeout time.Duration) *httptest.ResponseRecorder {
+		t.Helper()
+
+		ctx, cancel := context.WithTimeout(context.Background(), timeout)
+		defer cancel()
+		req := httptest.NewRequest(http.MethodGet, "/mcp", nil).WithContext(ctx)
+		req.Header.Set("Accept", "text/event-stream")
+		if sessionID != "" {
+			req.Header.Set(mcpSessionHeader, sessionID)
+		}
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		return w
+	}
+
+	w := mcpPost(t, router, "", `{"jsonrpc": "2.0", "id": 1, "method": "initialize", "params": {"protocolVersion": "2025-03-26"}}`)
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	sessionID := w.Header().Get(mcpSessionHeader)
+	require.NotEmpty(t, sessionID)
+
+	tests := []struct {
+		name      string
+		sessionID string
+		status    int
+	}{
+		{name: "missing session", status: http.StatusBadRequest},
+		{name: "unknown session", sessionID: "unknown", status: http.StatusNotFound},
+		{name: "uninitialized session", sessionID: sessionID, status: http.StatusBadRequest},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := sseGet(t, tt.sessionID, time.Second)
+			require.Equal(t, tt.status, w.Code, w.Body.String())
+
+			var response JSONRPCResponse
+			require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+			require.NotNil(t, response.Error)
+			assert.Equal(t, -32000, response.Error.Code)
+			assert.Nil(t, response.ID)
+		})
+	}
+
+	t.Run("initialized session", func(t *testing.T) {
+		w := mcpPost(t, router, sessionID, `{"jsonrpc": "2.0", "method": "notifications/initialized"}`)
+		require.Equal(t, http.StatusAccepted, w.Code)
+
+		// 连接保持打开直到请求取消
+		w = sseGet(t, sessionID, 50*time.Millisecond)
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Equal(t, "text/event-stream", w.Header().Get("Content-Type"))
+		assert.Contains(t, w.Body.String(), "event: open")
+	})
+}
@@ -0,0 +1,103 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"github.com/xpzouying/xiaohongshu-mcp/xiaohongshu"
+)
+
+// xsec_token 自动刷新
+//
+// 笔记的 xsec_token 会过期，过期后打开详情页会跳转到错误页。推荐列表、搜索结果和用户笔记中的每篇笔记
+// 都会记录来源，获取详情或评论时遇到 xiaohongshu.ErrTokenExpired，按来源重新获取一次列表，
+// 拿到新的 xsec_token 后重试一次，并在响应中返回新令牌。没有来源记录、刷新后列表中找不到该笔记
+// 或重试仍然失败时返回 ErrTokenExpired，调用方需要自行重新获取列表。
+
+const (
+	// feedTokenSourceTTL 笔记来源的保留时间，超过后不再尝试自动刷新
+	feedTokenSourceTTL = time.Hour
+	// feedTokenSourceMaxEntries 最多记录的笔记数量，超出时淘汰最久未使用的记录
+	feedTokenSourceMaxEntries = 5000
+)
+
+// feedTokenSource 笔记出现在哪个列表中，用于重新获取 xsec_token
+type feedTokenSource struct {
+	Tool          string // list_feeds、search_feeds、user_feeds、explore_feeds
+	Keyword       string // search_feeds 的关键词
+	Channel       string // explore_feeds 的频道ID
+	UserID        string // user_feeds 的用户ID
+	UserXsecToken string // user_feeds 的用户 xsec_token
+}
+
+// recordFeedSources 记录列表中每篇笔记的来源
+func (s *XiaohongshuService) recordFeedSources(feeds []xiaohongshu.Feed, source feedTokenSource) {
+	for _, feed := range feeds {
+		s.tokenSources.Set(feed.ID, source)
+	}
+}
+
+// refreshFeedToken 按记录的来源重新获取列表，返回笔记新的 xsec_token。
+// 没有来源记录、重新获取失败、列表中已没有该笔记或令牌没有变化时返回 false
+func (s *XiaohongshuService) refreshFeedToken(ctx context.Context, feedID, xsecToken string) (string, bool) {
+	// 来源记录不受请求的 fresh 参数影响
+	source, ok := s.tokenSources.Get(context.Background(), feedID)
+	if !ok {
+		return "", false
+	}
+
+	var feeds []xiaohongshu.Feed
+	var err error
+	switch source.Tool {
+	case "list_feeds":
+		var result *FeedsListResponse
+		if result, err = s.ListFeeds(ctx); err == nil {
+			feeds = result.Feeds
+		}
+	case "search_feeds":
+		var result *FeedsListResponse
+		if result, err = s.SearchFeeds(withFreshRead(ctx, true), source.Keyword); err == nil {
+			feeds = result.Feeds
+		}
+	case "explore_feeds":
+		var result *ExploreFeedsResponse
+		if result, err = s.ExploreFeeds(ctx, source.Channel, xiaohongshu.MaxExploreFeedsLimit); err == nil {
+			feeds = result.Feeds
+		}
+	case "user_feeds":
+		var result *UserFeedsResponse
+		if result, err = s.UserFeeds(ctx, source.UserID, source.UserXsecToken, xiaohongshu.MaxUserFeedsLimit, ""); err == nil {
+			feeds = result.Feeds
+		}
+	}
+	if err != nil {
+		logrus.WithContext(ctx).Warnf("重新获取笔记 %s 的 xsec_token 失败（来源 %s）: %v", feedID, source.Tool, err)
+		return "", false
+	}
+
+	for _, feed := range feeds {
+		if feed.ID == feedID && feed.XsecToken != "" && feed.XsecToken != xsecToken {
+			return feed.XsecToken, true
+		}
+	}
+	return "", false
+}
+
+// retryOnTokenExpired 执行读操作，笔记的 xsec_token 过期时刷新令牌并重试一次。
+// fn 接收本次使用的令牌，使用的令牌与传入的不同时说明已经刷新，由 fn 在结果中返回新令牌
+func retryOnTokenExpired[T any](ctx context.Context, s *XiaohongshuService, feedID, xsecToken string, fn func(xsecToken string) (T, error)) (T, error) {
+	result, err := fn(xsecToken)
+	if !errors.Is(err, xiaohongshu.ErrTokenExpired) {
+		return result, err
+	}
+
+	token, ok := s.refreshFeedToken(ctx, feedID, xsecToken)
+	if !ok {
+		return result, err
+	}
+
+	logrus.WithContext(ctx).Infof("笔记 %s 的 xsec_token 已过期，刷新后重试", feedID)
+	return fn(token)
+}
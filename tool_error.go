@@ -0,0 +1,105 @@
+package main
+
+import (
+	"errors"
+	"strings"
+
+	"github.com/xpzouying/xiaohongshu-mcp/xiaohongshu"
+)
+
+// 工具调用失败的结构化信息
+//
+// 工具执行失败时按 MCP 规范返回 isError 为 true 的结果，content 中是给模型看的说明文本，
+// structuredContent 中是 ToolErrorData，便于客户端按 code 判断是否需要重新登录、稍后重试等。
+// 工具名不存在、参数不符合 inputSchema 等在进入工具之前就失败的请求返回 JSON-RPC 错误，
+// 错误的 data 字段使用同一个 ToolErrorData 结构。
+
+// ToolErrorData 工具调用失败的结构化信息
+type ToolErrorData struct {
+	Tool   string `json:"tool"`            // 工具名
+	Code   string `json:"code"`            // 错误码，与 REST 接口的 code 一致，如 NOT_LOGGED_IN、UPLOAD_FAILED
+	Detail string `json:"detail"`          // 错误详情，与 content 中的说明文本相同
+	Field  string `json:"field,omitempty"` // 参数错误时出错的参数名
+}
+
+// toolErrorCode 将工具失败的原因映射为错误码，无法识别的错误返回 fallback
+func toolErrorCode(err error, fallback string) string {
+	var rateLimited *RateLimitedError
+	switch {
+	case errors.Is(err, ErrInvalidArgs):
+		return "INVALID_ARGS"
+	case errors.Is(err, xiaohongshu.ErrNotLoggedIn):
+		return "NOT_LOGGED_IN"
+	case errors.Is(err, xiaohongshu.ErrCaptchaRequired):
+		return "CAPTCHA_REQUIRED"
+	case errors.Is(err, xiaohongshu.ErrInvalidCursor):
+		return "INVALID_CURSOR"
+	case errors.Is(err, xiaohongshu.ErrTokenExpired):
+		return "TOKEN_EXPIRED"
+	case errors.Is(err, xiaohongshu.ErrNoteUnavailable):
+		return "NOTE_NOT_FOUND"
+	case errors.Is(err, ErrServerBusy):
+		return "SERVER_BUSY"
+	case errors.Is(err, xiaohongshu.ErrRateLimited), errors.As(err, &rateLimited):
+		return "RATE_LIMITED"
+	case errors.Is(err, xiaohongshu.ErrServiceBusy):
+		return "SERVICE_BUSY"
+	case errors.Is(err, xiaohongshu.ErrUploadFailed):
+		return "UPLOAD_FAILED"
+	case errors.Is(err, xiaohongshu.ErrContentRejected):
+		return "CONTENT_REJECTED"
+	case errors.Is(err, xiaohongshu.ErrPublishUnconfirmed):
+		return "PUBLISH_UNCONFIRMED"
+	case errors.Is(err, xiaohongshu.ErrNotNoteOwner):
+		return "NOT_OWNER"
+	case errors.Is(err, xiaohongshu.ErrVisibilityUnsupported):
+		return "VISIBILITY_UNSUPPORTED"
+	case errors.Is(err, xiaohongshu.ErrProfilePrivate):
+		return "PROFILE_PRIVATE"
+	default:
+		return fallback
+	}
+}
+
+// toolErrorResult 工具执行失败的结果，text 为给模型看的说明，err 为失败原因，用于生成结构化的错误信息
+func toolErrorResult(text string, err error) *MCPToolResult {
+	return &MCPToolResult{
+		Content: []MCPContent{{
+			Type: "text",
+			Text: text,
+		}},
+		IsError: true,
+		err:     err,
+	}
+}
+
+// withToolErrorData 为失败的工具结果补充结构化的错误信息，无法识别的错误使用 <TOOL>_FAILED 作为错误码
+func withToolErrorData(tool string, result *MCPToolResult) *MCPToolResult {
+	if result == nil || !result.IsError || result.StructuredContent != nil {
+		return result
+	}
+
+	data := ToolErrorData{
+		Tool: tool,
+		Code: toolErrorCode(result.err, strings.ToUpper(tool)+"_FAILED"),
+	}
+	if len(result.Content) > 0 {
+		data.Detail = result.Content[0].Text
+	}
+	result.StructuredContent = data
+
+	return result
+}
+
+// toolCallError 进入工具之前就失败的 tools/call 请求返回的 JSON-RPC 错误，data 为 ToolErrorData
+func toolCallError(id any, message string, data ToolErrorData) *JSONRPCResponse {
+	return &JSONRPCResponse{
+		JSONRPC: "2.0",
+		Error: &JSONRPCError{
+			Code:    -32602,
+			Message: message,
+			Data:    data,
+		},
+		ID: id,
+	}
+}
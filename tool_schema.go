@@ -0,0 +1,162 @@
+package main
+
+import (
+	"fmt"
+	"maps"
+	"math"
+	"slices"
+	"strconv"
+)
+
+// 工具参数校验
+//
+// 按 tools/list 中声明的 inputSchema 校验 tools/call 的 arguments，保证声明与实际处理一致。
+// 只实现工具定义中用到的 JSON Schema 子集：type、properties、required、items、enum、
+// minItems、maxItems、minimum、maximum。未声明的参数不做校验，由处理函数忽略。
+// 参数值为 null 时视为未传：可选参数跳过校验，必需参数报告缺失。
+
+// toolArgsError 参数不符合 inputSchema，Field 为出错参数的路径，如 comments[0].feed_id
+type toolArgsError struct {
+	Field   string
+	Message string
+}
+
+func (e *toolArgsError) Error() string {
+	return e.Field + ": " + e.Message
+}
+
+// validateToolArgs 按 inputSchema 校验工具参数，返回第一个不符合的参数。未传 arguments 时按空对象处理
+func validateToolArgs(schema map[string]interface{}, args map[string]interface{}) *toolArgsError {
+	if args == nil {
+		args = map[string]interface{}{}
+	}
+	return validateSchemaValue("", schema, args)
+}
+
+// validateSchemaValue 按 schema 校验单个值，path 为该值在参数中的路径
+func validateSchemaValue(path string, schema map[string]interface{}, value interface{}) *toolArgsError {
+	fail := func(format string, args ...any) *toolArgsError {
+		field := path
+		if field == "" {
+			field = "arguments"
+		}
+		return &toolArgsError{Field: field, Message: fmt.Sprintf(format, args...)}
+	}
+
+	if enum := schemaEnum(schema["enum"]); enum != nil && !slices.Contains(enum, value) {
+		return fail("must be one of %v", enum)
+	}
+
+	typ, _ := schema["type"].(string)
+	switch typ {
+	case "object":
+		obj, ok := value.(map[string]interface{})
+		if !ok {
+			return fail("must be an object")
+		}
+
+		required, _ := schema["required"].([]string)
+		for _, name := range required {
+			if obj[name] == nil {
+				return &toolArgsError{Field: joinSchemaPath(path, name), Message: "is required"}
+			}
+		}
+
+		// 按参数名顺序校验，多个参数不符合时每次报告同一个
+		properties, _ := schema["properties"].(map[string]interface{})
+		for _, name := range slices.Sorted(maps.Keys(obj)) {
+			prop, ok := properties[name].(map[string]interface{})
+			if !ok || obj[name] == nil {
+				continue
+			}
+			if err := validateSchemaValue(joinSchemaPath(path, name), prop, obj[name]); err != nil {
+				return err
+			}
+		}
+
+	case "array":
+		items, ok := value.([]interface{})
+		if !ok {
+			return fail("must be an array")
+		}
+		if n, ok := schemaNumber(schema["minItems"]); ok && float64(len(items)) < n {
+			return fail("must contain at least %v items, got %d", n, len(items))
+		}
+		if n, ok := schemaNumber(schema["maxItems"]); ok && float64(len(items)) > n {
+			return fail("must contain at most %v items, got %d", n, len(items))
+		}
+
+		itemSchema, _ := schema["items"].(map[string]interface{})
+		if itemSchema == nil {
+			break
+		}
+		for i, item := range items {
+			if err := validateSchemaValue(path+"["+strconv.Itoa(i)+"]", itemSchema, item); err != nil {
+				return err
+			}
+		}
+
+	case "string":
+		if _, ok := value.(string); !ok {
+			return fail("must be a string")
+		}
+
+	case "boolean":
+		if _, ok := value.(bool); !ok {
+			return fail("must be a boolean")
+		}
+
+	case "number", "integer":
+		n, ok := value.(float64)
+		if !ok {
+			return fail("must be a %s", typ)
+		}
+		if typ == "integer" && n != math.Trunc(n) {
+			return fail("must be an integer")
+		}
+		if minimum, ok := schemaNumber(schema["minimum"]); ok && n < minimum {
+			return fail("must be >= %v", minimum)
+		}
+		if maximum, ok := schemaNumber(schema["maximum"]); ok && n > maximum {
+			return fail("must be <= %v", maximum)
+		}
+	}
+
+	return nil
+}
+
+// joinSchemaPath 拼接参数路径
+func joinSchemaPath(path, name string) string {
+	if path == "" {
+		return name
+	}
+	return path + "." + name
+}
+
+// schemaEnum 将 schema 中的 enum 统一为 []interface{}，便于与 JSON 解析出的值比较
+func schemaEnum(v interface{}) []interface{} {
+	switch enum := v.(type) {
+	case []string:
+		values := make([]interface{}, len(enum))
+		for i, s := range enum {
+			values[i] = s
+		}
+		return values
+	case []interface{}:
+		return enum
+	}
+	return nil
+}
+
+// schemaNumber 读取 schema 中的数值约束，工具定义中使用 int 字面量
+func schemaNumber(v interface{}) (float64, bool) {
+	switch n := v.(type) {
+	case int:
+		return float64(n), true
+	case int64:
+		return float64(n), true
+	case float64:
+		return n, true
+	}
+	return 0, false
+}
@@ -0,0 +1,78 @@
+package main
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// parseToolArgs 按 tools/call 的方式解析 JSON 参数
+func parseToolArgs(t *testing.T, s string) map[string]interface{} {
+	t.Helper()
+
+	var args map[string]interface{}
+	require.NoError(t, json.Unmarshal([]byte(s), &args))
+	return args
+}
+
+func TestValidateToolArgs(t *testing.T) {
+	schema := map[string]interface{}{
+		"type": "object",
+		"properties": map[string]interface{}{
+			"feed_id": map[string]interface{}{"type": "string"},
+			"limit":   map[string]interface{}{"type": "integer", "minimum": 1, "maximum": 100},
+			"scope":   map[string]interface{}{"type": "string", "enum": []string{"note", "page"}},
+			"tags": map[string]interface{}{
+				"type":     "array",
+				"items":    map[string]interface{}{"type": "string"},
+				"maxItems": 2,
+			},
+		},
+		"required": []string{"feed_id"},
+	}
+
+	tests := []struct {
+		name      string
+		args      string
+		wantField string
+	}{
+		{"valid", `{"feed_id": "1", "limit": 10, "scope": "page", "tags": ["a"]}`, ""},
+		{"optional fields omitted", `{"feed_id": "1"}`, ""},
+		{"optional fields null", `{"feed_id": "1", "limit": null, "scope": null, "tags": null}`, ""},
+		{"unknown field", `{"feed_id": "1", "extra": 1}`, ""},
+		{"required missing", `{}`, "feed_id"},
+		{"required null", `{"feed_id": null}`, "feed_id"},
+		{"wrong type", `{"feed_id": 1}`, "feed_id"},
+		{"not an integer", `{"feed_id": "1", "limit": 1.5}`, "limit"},
+		{"below minimum", `{"feed_id": "1", "limit": 0}`, "limit"},
+		{"not in enum", `{"feed_id": "1", "scope": "all"}`, "scope"},
+		{"too many items", `{"feed_id": "1", "tags": ["a", "b", "c"]}`, "tags"},
+		{"null item", `{"feed_id": "1", "tags": [null]}`, "tags[0]"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := validateToolArgs(schema, parseToolArgs(t, tt.args))
+			if tt.wantField == "" {
+				assert.Nil(t, err)
+				return
+			}
+			require.NotNil(t, err)
+			assert.Equal(t, tt.wantField, err.Field)
+		})
+	}
+}
+
+func TestValidateToolArgsNullOptionalInToolSchemas(t *testing.T) {
+	schema := toolInputSchema("publish_content")
+	require.NotNil(t, schema)
+
+	args := parseToolArgs(t, `{"title": "标题", "content": "正文", "images": ["a.jpg"], "tags": null, "cover_index": null, "visibility": null}`)
+	assert.Nil(t, validateToolArgs(schema, args))
+
+	args = parseToolArgs(t, `{"title": null, "content": "正文", "images": ["a.jpg"]}`)
+	err := validateToolArgs(schema, args)
+	require.NotNil(t, err)
+	assert.Equal(t, "title", err.Field)
+}
@@ -1,5 +1,10 @@
 package main
 
+import (
+	"encoding/json"
+	"fmt"
+)
+
 // HTTP API 响应类型
 
 // ErrorResponse 错误响应
@@ -11,9 +16,10 @@ type ErrorResponse struct {
 
 // SuccessResponse 成功响应
 type SuccessResponse struct {
-	Success bool   `json:"success"`
-	Data    any    `json:"data"`
-	Message string `json:"message,omitempty"`
+	Success bool         `json:"success"`
+	Data    any          `json:"data"`
+	Message string       `json:"message,omitempty"`
+	Account *AccountInfo `json:"account,omitempty"` // 处理请求的账号
 }
 
 // JSON-RPC 相关类型
@@ -24,6 +30,30 @@ type JSONRPCRequest struct {
 	Method  string `json:"method"`
 	Params  any    `json:"params,omitempty"`
 	ID      any    `json:"id"`
+
+	notification bool // 请求没有 id 字段，是不需要响应的通知
+}
+
+// UnmarshalJSON 解析请求，并记录是否携带 id 字段。
+// "id": null 与不带 id 解析后都是 nil，只能根据字段是否存在区分通知
+func (r *JSONRPCRequest) UnmarshalJSON(data []byte) error {
+	type plainRequest JSONRPCRequest
+	if err := json.Unmarshal(data, (*plainRequest)(r)); err != nil {
+		return err
+	}
+
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal(data, &fields); err != nil {
+		return err
+	}
+	_, hasID := fields["id"]
+	r.notification = !hasID
+	return nil
+}
+
+// IsNotification 请求是否为通知，按 JSON-RPC 规范服务端执行通知但不返回响应
+func (r *JSONRPCRequest) IsNotification() bool {
+	return r.notification
 }
 
 // JSONRPCResponse JSON-RPC 响应
@@ -34,6 +64,13 @@ type JSONRPCResponse struct {
 	ID      any           `json:"id"`
 }
 
+// JSONRPCNotification JSON-RPC 通知（没有 id，不需要响应）
+type JSONRPCNotification struct {
+	JSONRPC string `json:"jsonrpc"`
+	Method  string `json:"method"`
+	Params  any    `json:"params,omitempty"`
+}
+
 // JSONRPCError JSON-RPC 错误
 type JSONRPCError struct {
 	Code    int    `json:"code"`
@@ -49,28 +86,126 @@ type MCPToolCall struct {
 	Arguments map[string]interface{} `json:"arguments"`
 }
 
-// MCPToolResult MCP 工具结果
+// MCPToolResult MCP 工具结果。StructuredContent 为结果的结构化数据，
+// 便于调用方直接解析，Content 中保留同样内容的 JSON 文本供只读文本的客户端使用
 type MCPToolResult struct {
-	Content []MCPContent `json:"content"`
-	IsError bool         `json:"isError,omitempty"`
+	Content           []MCPContent `json:"content"`
+	StructuredContent any          `json:"structuredContent,omitempty"`
+	IsError           bool         `json:"isError,omitempty"`
+	Meta              *ToolMeta    `json:"_meta,omitempty"`
+
+	err error // 失败原因，由 withToolErrorData 转换为结构化的错误信息
+}
+
+// ToolMeta 工具结果的附加信息
+type ToolMeta struct {
+	Account AccountInfo `json:"account"` // 处理请求的账号
 }
 
-// MCPContent MCP 内容
+// MCPContent MCP 内容，Type 为 text 时使用 Text，为 image 时使用 Data（base64）和 MimeType
 type MCPContent struct {
-	Type string `json:"type"`
-	Text string `json:"text"`
+	Type     string `json:"type"`
+	Text     string `json:"text,omitempty"`
+	Data     string `json:"data,omitempty"`
+	MimeType string `json:"mimeType,omitempty"`
+}
+
+// ListFeedsQuery Feeds 列表的分页与过滤参数
+type ListFeedsQuery struct {
+	Limit    int    `form:"limit" binding:"min=0,max=100"`                    // 每页数量，0 表示返回全部
+	Cursor   string `form:"cursor"`                                           // 上一页返回的 next_cursor，为空表示第一页
+	NoteType string `form:"note_type" binding:"omitempty,oneof=normal video"` // 笔记类型：normal（图文）、video（视频），为空不过滤
+}
+
+// SearchDetailQuery 搜索并获取详情的查询参数
+type SearchDetailQuery struct {
+	Keyword string `form:"keyword" binding:"required"`
+	Limit   int    `form:"limit" binding:"min=0"` // 获取详情的笔记数量，0 表示默认 5 篇，超过上限时按上限处理
+	Fresh   bool   `form:"fresh"`                 // 跳过缓存
 }
 
 // FeedDetailRequest Feed详情请求
 type FeedDetailRequest struct {
 	FeedID    string `json:"feed_id" binding:"required"`
 	XsecToken string `json:"xsec_token" binding:"required"`
+	Fresh     bool   `json:"fresh,omitempty"` // 跳过缓存，重新获取
+}
+
+// ListCommentsRequest 笔记评论列表请求
+type ListCommentsRequest struct {
+	FeedID    string `json:"feed_id" binding:"required"`
+	XsecToken string `json:"xsec_token" binding:"required"`
+	Limit     int    `json:"limit,omitempty" binding:"min=0,max=100"` // 每页数量，0 表示默认 20 条
+	Cursor    string `json:"cursor,omitempty"`                        // 上一页返回的 next_cursor，为空表示第一页
+}
+
+// CommentTreeRequest 笔记评论树请求
+type CommentTreeRequest struct {
+	FeedID       string `json:"feed_id" binding:"required"`
+	XsecToken    string `json:"xsec_token" binding:"required"`
+	Limit        int    `json:"limit,omitempty" binding:"min=0,max=100"`         // 每页顶层评论数量，0 表示默认 20 条
+	RepliesLimit int    `json:"replies_limit,omitempty" binding:"min=0,max=100"` // 每条顶层评论最多展开的回复数量，0 表示默认 10 条
+	Cursor       string `json:"cursor,omitempty"`                                // 上一页返回的 next_cursor，为空表示第一页
+}
+
+// NoteOwnershipQuery 笔记归属查询参数
+type NoteOwnershipQuery struct {
+	FeedID    string `form:"feed_id" binding:"required"`
+	XsecToken string `form:"xsec_token" binding:"required"`
+}
+
+// FeedLinkQuery 笔记链接查询参数，short=true 时打开详情页获取分享短链
+type FeedLinkQuery struct {
+	FeedID    string `form:"feed_id" binding:"required"`
+	XsecToken string `form:"xsec_token" binding:"required"`
+	Short     bool   `form:"short"`
+}
+
+// FeedAnalyticsQuery 笔记数据查询参数
+type FeedAnalyticsQuery struct {
+	FeedID string `form:"feed_id" binding:"required"`
+}
+
+// FeedTagsQuery 笔记话题标签查询参数
+type FeedTagsQuery struct {
+	FeedID    string `form:"feed_id" binding:"required"`
+	XsecToken string `form:"xsec_token" binding:"required"`
+}
+
+// ExploreFeedsQuery 发现页推荐笔记查询参数，均为可选，channel 为频道名称或频道ID
+type ExploreFeedsQuery struct {
+	Channel string `form:"channel"`
+	Limit   int    `form:"limit" binding:"omitempty,min=1,max=100"`
+}
+
+// RelatedFeedsQuery 笔记相关推荐查询参数
+type RelatedFeedsQuery struct {
+	FeedID    string `form:"feed_id" binding:"required"`
+	XsecToken string `form:"xsec_token" binding:"required"`
+}
+
+// ScreenshotFeedQuery 笔记截图查询参数，width 为 0 时使用 -viewport 的宽度
+type ScreenshotFeedQuery struct {
+	FeedID    string `form:"feed_id" binding:"required"`
+	XsecToken string `form:"xsec_token" binding:"required"`
+	Width     int    `form:"width" binding:"omitempty,min=320,max=1920"`
+	Scope     string `form:"scope" binding:"omitempty,oneof=note page"`
 }
 
 // FeedDetailResponse Feed详情响应
 type FeedDetailResponse struct {
 	FeedID string `json:"feed_id"`
 	Data   any    `json:"data"`
+
+	// 视频笔记的播放地址，图文笔记为空。分段流式播放的视频可能只有 VideoManifestURL
+	VideoURL         string   `json:"video_url,omitempty"`
+	VideoBackupURLs  []string `json:"video_backup_urls,omitempty"`
+	VideoManifestURL string   `json:"video_manifest_url,omitempty"`
+
+	// 传入的 xsec_token 已过期时自动刷新得到的新令牌，后续请求应改用它
+	XsecToken string `json:"xsec_token,omitempty"`
+
+	Warnings []string `json:"warnings,omitempty"` // 详情中不完整的数据，如缺少作者或互动数无法解析
 }
 
 // PostCommentRequest 发表评论请求
@@ -78,17 +213,88 @@ type PostCommentRequest struct {
 	FeedID    string `json:"feed_id" binding:"required"`
 	XsecToken string `json:"xsec_token" binding:"required"`
 	Content   string `json:"content" binding:"required"`
+	Image     string `json:"image,omitempty"` // 评论图片，支持本地路径、URL 和 data URL，只用于单条评论
 }
 
 // PostCommentResponse 发表评论响应
 type PostCommentResponse struct {
-	FeedID  string `json:"feed_id"`
-	Success bool   `json:"success"`
-	Message string `json:"message"`
+	FeedID   string   `json:"feed_id"`
+	Success  bool     `json:"success"`
+	Message  string   `json:"message"`
+	Warnings []string `json:"warnings,omitempty"` // 评论已发表但与请求不完全一致，如笔记不支持图片评论时只发表了文字
 }
 
-// UserProfileRequest 用户主页请求
-type UserProfileRequest struct {
+// PostCommentsRequest 批量发表评论请求
+type PostCommentsRequest struct {
+	Comments     []PostCommentRequest `json:"comments" binding:"required,min=1,dive"`
+	DelaySeconds *int                 `json:"delay_seconds,omitempty"` // 两条评论之间的间隔秒数，默认5秒，不能为负数
+}
+
+// PostCommentResult 批量评论中单条评论的结果
+type PostCommentResult struct {
+	FeedID       string `json:"feed_id"`
+	Success      bool   `json:"success"`
+	NotAttempted bool   `json:"not_attempted,omitempty"` // 批量评论被取消或超时，这条评论没有尝试发表
+	Error        string `json:"error,omitempty"`
+}
+
+// PostCommentsResponse 批量发表评论响应
+type PostCommentsResponse struct {
+	Results      []PostCommentResult `json:"results"`
+	Succeeded    int                 `json:"succeeded"`
+	Failed       int                 `json:"failed"`
+	NotAttempted int                 `json:"not_attempted,omitempty"`
+}
+
+// EditFeedRequest 编辑笔记请求，只修改提供了的字段
+type EditFeedRequest struct {
+	FeedID    string   `json:"feed_id" binding:"required"`
+	XsecToken string   `json:"xsec_token" binding:"required"`
+	Title     *string  `json:"title,omitempty"`
+	Content   *string  `json:"content,omitempty"`
+	Tags      []string `json:"tags,omitempty"`
+	Images    []string `json:"images,omitempty"`
+}
+
+// HasUpdates 是否至少提供了一个需要修改的字段
+func (r *EditFeedRequest) HasUpdates() bool {
+	return r.Title != nil || r.Content != nil || r.Tags != nil || r.Images != nil
+}
+
+// FeedVisibilityRequest 修改笔记可见范围请求，visibility 为空时设为仅自己可见
+type FeedVisibilityRequest struct {
+	FeedID     string `json:"feed_id" binding:"required"`
+	XsecToken  string `json:"xsec_token" binding:"required"`
+	Visibility string `json:"visibility,omitempty"`
+}
+
+// UserCollectionsRequest 用户收藏专辑请求
+type UserCollectionsRequest struct {
 	UserID    string `json:"user_id" binding:"required"`
 	XsecToken string `json:"xsec_token" binding:"required"`
 }
+
+// FeedVisibilityResponse 修改笔记可见范围响应
+type FeedVisibilityResponse struct {
+	FeedID     string `json:"feed_id"`
+	Visibility string `json:"visibility"`
+	Message    string `json:"message"`
+}
+
+// UserProfileRequest 用户主页请求，需要提供 user_id+xsec_token 或 profile_url 其中一组
+type UserProfileRequest struct {
+	UserID     string `json:"user_id"`
+	XsecToken  string `json:"xsec_token"`
+	ProfileURL string `json:"profile_url"`
+}
+
+// Validate 校验用户主页请求参数
+func (r *UserProfileRequest) Validate() error {
+	if r.ProfileURL != "" {
+		return nil
+	}
+	if r.UserID == "" || r.XsecToken == "" {
+		return fmt.Errorf("需要提供 user_id 和 xsec_token，或者提供 profile_url")
+	}
+	return nil
+}
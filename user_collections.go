@@ -0,0 +1,69 @@
+package main
+
+import (
+	"context"
+	"errors"
+
+	"github.com/xpzouying/xiaohongshu-mcp/xiaohongshu"
+)
+
+// UserCollectionsResponse 用户收藏专辑列表响应
+type UserCollectionsResponse struct {
+	UserID      string                       `json:"userId"`
+	Collections []xiaohongshu.UserCollection `json:"collections"`
+	Count       int                          `json:"count"`
+	Warnings    []string                     `json:"warnings,omitempty"` // 收藏被隐藏等导致列表为空的原因
+}
+
+// UserCollections 获取用户主页“收藏”页签中公开的收藏专辑。
+// 私密账号或用户隐藏了收藏时返回空列表并在 warnings 中说明原因
+func (s *XiaohongshuService) UserCollections(ctx context.Context, userID, xsecToken string) (*UserCollectionsResponse, error) {
+	return retryOnBrowserCrash("user_collections", func() (*UserCollectionsResponse, error) {
+		return s.userCollections(ctx, userID, xsecToken)
+	})
+}
+
+// userCollections UserCollections 的单次执行，浏览器崩溃时由 UserCollections 重试
+func (s *XiaohongshuService) userCollections(ctx context.Context, userID, xsecToken string) (*UserCollectionsResponse, error) {
+	ctx, done := withTimeout(ctx, "user_collections", false)
+	defer done()
+
+	b, err := s.newBrowser(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer b.Close()
+
+	page := newPage(b)
+	defer page.Close()
+	defer saveCookies(page)
+
+	action := xiaohongshu.NewUserProfileAction(page.Context(ctx))
+
+	var collections []xiaohongshu.UserCollection
+	err = retryOnCaptcha(ctx, page, func() (err error) {
+		collections, err = action.Collections(ctx, userID, xsecToken)
+		return err
+	})
+	if errors.Is(err, xiaohongshu.ErrProfilePrivate) || errors.Is(err, xiaohongshu.ErrCollectionsHidden) {
+		return hiddenCollectionsResponse(userID, err), nil
+	}
+	if err = checkBlockedPage(page, err, len(collections) == 0); err != nil {
+		return nil, screenshotOnError(page, err)
+	}
+
+	return &UserCollectionsResponse{
+		UserID:      userID,
+		Collections: collections,
+		Count:       len(collections),
+	}, nil
+}
+
+// hiddenCollectionsResponse 收藏不可见时返回的空列表，reason 作为 warning 返回
+func hiddenCollectionsResponse(userID string, reason error) *UserCollectionsResponse {
+	return &UserCollectionsResponse{
+		UserID:      userID,
+		Collections: []xiaohongshu.UserCollection{},
+		Warnings:    []string{reason.Error()},
+	}
+}
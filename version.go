@@ -0,0 +1,64 @@
+package main
+
+import (
+	"runtime"
+	"runtime/debug"
+
+	"github.com/gin-gonic/gin"
+)
+
+// 构建信息，发布时通过 -ldflags 注入，例如：
+//
+//	go build -ldflags "-X main.version=v2.1.0 -X main.gitCommit=$(git rev-parse --short HEAD) -X main.buildDate=$(date -u +%Y-%m-%dT%H:%M:%SZ)"
+var (
+	version   = "dev"
+	gitCommit = ""
+	buildDate = ""
+)
+
+// BuildInfo 运行中服务的构建信息
+type BuildInfo struct {
+	Version   string `json:"version"`
+	GitCommit string `json:"git_commit"`
+	BuildDate string `json:"build_date"`
+	GoVersion string `json:"go_version"`
+}
+
+// buildInfo 返回构建信息。未通过 -ldflags 注入提交和构建时间时，
+// 使用 go build 自动写入的 VCS 信息
+func buildInfo() BuildInfo {
+	info := BuildInfo{
+		Version:   version,
+		GitCommit: gitCommit,
+		BuildDate: buildDate,
+		GoVersion: runtime.Version(),
+	}
+
+	if bi, ok := debug.ReadBuildInfo(); ok {
+		for _, setting := range bi.Settings {
+			switch setting.Key {
+			case "vcs.revision":
+				if info.GitCommit == "" {
+					info.GitCommit = setting.Value
+				}
+			case "vcs.time":
+				if info.BuildDate == "" {
+					info.BuildDate = setting.Value
+				}
+			}
+		}
+	}
+
+	if info.GitCommit == "" {
+		info.GitCommit = "unknown"
+	}
+	if info.BuildDate == "" {
+		info.BuildDate = "unknown"
+	}
+	return info
+}
+
+// versionHandler 返回运行中服务的版本、提交和构建时间
+func (s *AppServer) versionHandler(c *gin.Context) {
+	respondSuccess(c, buildInfo(), "获取版本信息成功")
+}
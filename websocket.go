@@ -0,0 +1,202 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/gorilla/websocket"
+	"github.com/sirupsen/logrus"
+	"github.com/xpzouying/xiaohongshu-mcp/xiaohongshu"
+)
+
+// WebSocket 传输
+//
+// 客户端连接 /mcp/ws 后，每条文本消息是一个 JSON-RPC 请求，服务端按收到的顺序依次处理，
+// 并以一条文本消息返回对应的 JSON-RPC 响应。支持的方法与 Streamable HTTP 完全一致：
+// initialize、initialized、ping、tools/list、tools/call。
+// 一个连接对应一个会话，需要先调用 initialize，连接断开时会话随之结束。
+
+const (
+	// wsPongWait 等待客户端 pong 的最长时间，超时视为连接断开
+	wsPongWait = 60 * time.Second
+	// wsPingPeriod 服务端发送 ping 的间隔，需小于 wsPongWait
+	wsPingPeriod = wsPongWait * 9 / 10
+	// wsWriteWait 单次写消息的超时时间
+	wsWriteWait = 10 * time.Second
+	// wsMaxMessageSize 单条请求消息的最大字节数
+	wsMaxMessageSize = 10 << 20
+	// wsRequestQueueSize 等待处理的请求队列长度
+	wsRequestQueueSize = 16
+)
+
+var wsUpgrader = websocket.Upgrader{
+	// 与 HTTP 端点的 CORS 策略保持一致；没有 Origin 头的非浏览器客户端不受限制
+	CheckOrigin: func(r *http.Request) bool {
+		origin := r.Header.Get("Origin")
+		return origin == "" || isOriginAllowed(origin)
+	},
+}
+
+// handleWebSocket 处理 WebSocket 连接，复用 processJSONRPCRequest 分发请求
+func (s *AppServer) handleWebSocket(w http.ResponseWriter, r *http.Request) {
+	// 在升级之前检查连接数，超出上限时以普通 HTTP 响应返回 503
+	release, ok := s.acquireConnection(w, r, "websocket")
+	if !ok {
+		return
+	}
+	defer release()
+
+	conn, err := wsUpgrader.Upgrade(w, r, nil)
+	if err != nil {
+		logrus.WithError(err).Error("WebSocket upgrade failed")
+		return
+	}
+	defer conn.Close()
+
+	logrus.Infof("WebSocket 连接建立: %s", r.RemoteAddr)
+
+	// 连接断开时取消正在执行的工具调用
+	ctx, cancel := context.WithCancel(r.Context())
+	defer cancel()
+
+	conn.SetReadLimit(wsMaxMessageSize)
+	_ = conn.SetReadDeadline(time.Now().Add(wsPongWait))
+	conn.SetPongHandler(func(string) error {
+		return conn.SetReadDeadline(time.Now().Add(wsPongWait))
+	})
+
+	var writeMu sync.Mutex
+	writeJSON := func(message any) {
+		writeMu.Lock()
+		defer writeMu.Unlock()
+
+		_ = conn.SetWriteDeadline(time.Now().Add(wsWriteWait))
+		if err := conn.WriteJSON(message); err != nil {
+			logrus.WithError(err).Error("Failed to write WebSocket message")
+		}
+	}
+
+	// 请求在独立的 goroutine 中按顺序处理，读循环保持运行以便及时处理 pong 和断开
+	requests := make(chan *JSONRPCRequest, wsRequestQueueSize)
+	var wg sync.WaitGroup
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+
+		// 一个 WebSocket 连接对应一个会话，连接断开时结束会话
+		var sessionID string
+		defer func() {
+			if sessionID != "" {
+				s.sessions.Delete(sessionID)
+			}
+		}()
+
+		for request := range requests {
+			// nil 表示消息无法解析，按顺序返回解析错误
+			if request == nil {
+				writeJSON(&JSONRPCResponse{
+					JSONRPC: "2.0",
+					Error: &JSONRPCError{
+						Code:    -32700,
+						Message: "Parse error",
+					},
+				})
+				continue
+			}
+
+			// 同一连接上的每条消息是独立的操作，各自生成操作ID
+			reqCtx := xiaohongshu.WithOpID(ctx)
+			logrus.WithContext(reqCtx).WithField("method", request.Method).Info("Received WebSocket request")
+
+			// 每条消息都刷新会话的活跃时间
+			var session mcpSession
+			if sessionID != "" {
+				session, _ = s.sessions.Get(sessionID)
+			}
+
+			if requiresSession(request.Method) {
+				message := ""
+				switch {
+				case sessionID == "":
+					message = "Bad Request: call initialize first"
+				case !session.Initialized && requiresInitialized(request.Method):
+					message = notInitializedMessage
+				}
+				if message != "" {
+					if request.IsNotification() {
+						logrus.WithField("method", request.Method).Warn("Ignored WebSocket notification before initialization")
+					} else {
+						writeJSON(sessionRequiredError(request.ID, message))
+					}
+					continue
+				}
+			}
+			if sessionID != "" && isInitializedNotification(request.Method) {
+				s.sessions.MarkInitialized(sessionID)
+			}
+
+			response := s.processJSONRPCRequest(request, reqCtx)
+			if request.Method == "initialize" && response.Error == nil && sessionID == "" {
+				sessionID = s.sessions.CreateForConnection().ID
+			}
+			// 通知不返回响应
+			if !request.IsNotification() {
+				writeJSON(response)
+			}
+		}
+	}()
+
+	// 定时发送 ping 保活，并转发服务端通知
+	notifications := s.notifications.Subscribe()
+	defer s.notifications.Unsubscribe(notifications)
+
+	go func() {
+		ticker := time.NewTicker(wsPingPeriod)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case notification, ok := <-notifications:
+				if !ok {
+					return
+				}
+				writeJSON(notification)
+			case <-ticker.C:
+				if err := conn.WriteControl(websocket.PingMessage, nil, time.Now().Add(wsWriteWait)); err != nil {
+					return
+				}
+			}
+		}
+	}()
+
+	for {
+		messageType, data, err := conn.ReadMessage()
+		if err != nil {
+			if websocket.IsUnexpectedCloseError(err, websocket.CloseNormalClosure, websocket.CloseGoingAway) {
+				logrus.WithError(err).Warn("WebSocket 连接异常断开")
+			}
+			break
+		}
+		if messageType != websocket.TextMessage {
+			continue
+		}
+
+		var request JSONRPCRequest
+		if err := json.Unmarshal(data, &request); err != nil {
+			requests <- nil
+			continue
+		}
+
+		requests <- &request
+	}
+
+	cancel()
+	close(requests)
+	wg.Wait()
+
+	logrus.Infof("WebSocket 连接关闭: %s", r.RemoteAddr)
+}
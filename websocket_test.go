@@ -0,0 +1,52 @@
+package main
+
+import (
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gorilla/websocket"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWebSocketInitialization(t *testing.T) {
+	router, _ := newTestRouter(t)
+	server := httptest.NewServer(router)
+	defer server.Close()
+
+	conn, _, err := websocket.DefaultDialer.Dial("ws"+strings.TrimPrefix(server.URL, "http")+"/mcp/ws", nil)
+	require.NoError(t, err)
+	defer conn.Close()
+
+	call := func(request string) JSONRPCResponse {
+		t.Helper()
+
+		require.NoError(t, conn.WriteMessage(websocket.TextMessage, []byte(request)))
+		require.NoError(t, conn.SetReadDeadline(time.Now().Add(5*time.Second)))
+		var response JSONRPCResponse
+		require.NoError(t, conn.ReadJSON(&response))
+		return response
+	}
+
+	response := call(`{"jsonrpc": "2.0", "id": 1, "method": "tools/list"}`)
+	require.NotNil(t, response.Error, "initialize 之前不能调用其他方法")
+
+	response = call(`{"jsonrpc": "2.0", "id": 2, "method": "initialize", "params": {"protocolVersion": "2025-03-26"}}`)
+	require.Nil(t, response.Error)
+
+	response = call(`{"jsonrpc": "2.0", "id": 3, "method": "tools/list"}`)
+	require.NotNil(t, response.Error, "initialized 通知之前不能调用其他方法")
+	assert.Equal(t, notInitializedMessage, response.Error.Message)
+
+	// 通知没有响应，紧接着的 ping 的响应说明通知已按顺序处理
+	require.NoError(t, conn.WriteMessage(websocket.TextMessage, []byte(`{"jsonrpc": "2.0", "method": "notifications/initialized"}`)))
+	response = call(`{"jsonrpc": "2.0", "id": 4, "method": "ping"}`)
+	require.Nil(t, response.Error)
+	assert.EqualValues(t, 4, response.ID)
+
+	response = call(`{"jsonrpc": "2.0", "id": 5, "method": "tools/list"}`)
+	assert.Nil(t, response.Error)
+	assert.EqualValues(t, 5, response.ID)
+}
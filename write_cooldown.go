@@ -0,0 +1,102 @@
+package main
+
+import (
+	"fmt"
+	"math"
+	"sync"
+	"time"
+
+	"github.com/xpzouying/xiaohongshu-mcp/xiaohongshu"
+)
+
+// RateLimitedError 账号被平台限流，RetryAfter 为距离允许下一次写操作的剩余时间。
+// errors.Is(err, xiaohongshu.ErrRateLimited) 成立
+type RateLimitedError struct {
+	RetryAfter time.Duration
+	Err        error // 触发限流的原始错误，冷却期间拒绝的请求为 nil
+}
+
+func (e *RateLimitedError) Error() string {
+	retry := e.RetryAfter.Round(time.Second)
+	if e.Err != nil && retry <= 0 {
+		// 未配置冷却时间
+		return e.Err.Error()
+	}
+	if e.Err != nil {
+		return fmt.Sprintf("%s，已暂停写操作，请在 %s 后重试", e.Err.Error(), retry)
+	}
+	return fmt.Sprintf("%s，写操作冷却中，请在 %s 后重试", xiaohongshu.ErrRateLimited.Error(), retry)
+}
+
+// Unwrap 返回原始错误和 xiaohongshu.ErrRateLimited
+func (e *RateLimitedError) Unwrap() []error {
+	if e.Err != nil {
+		return []error{xiaohongshu.ErrRateLimited, e.Err}
+	}
+	return []error{xiaohongshu.ErrRateLimited}
+}
+
+// RetryAfterSeconds 返回建议客户端等待的秒数，未配置冷却时间时为 0
+func (e *RateLimitedError) RetryAfterSeconds() int {
+	return int(math.Ceil(e.RetryAfter.Seconds()))
+}
+
+// writeCooldown 账号被平台限流后暂停写操作。连续被限流时暂停时间按
+// base、2×base、4×base… 指数增长，不超过 max；距上次冷却结束超过 max 后重新从 base 开始
+type writeCooldown struct {
+	base time.Duration
+	max  time.Duration
+
+	mu      sync.Mutex
+	strikes map[string]int       // 每个账号连续被限流的次数
+	until   map[string]time.Time // 每个账号冷却结束的时间
+	now     func() time.Time
+}
+
+// newWriteCooldown 创建写操作冷却，base 为 0 时不暂停
+func newWriteCooldown(base, max time.Duration) *writeCooldown {
+	return &writeCooldown{
+		base:    base,
+		max:     max,
+		strikes: make(map[string]int),
+		until:   make(map[string]time.Time),
+		now:     time.Now,
+	}
+}
+
+// Check 账号处于冷却期时返回 *RateLimitedError
+func (c *writeCooldown) Check(account string) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if remaining := c.until[account].Sub(c.now()); remaining > 0 {
+		return &RateLimitedError{RetryAfter: remaining}
+	}
+	return nil
+}
+
+// Trip 记录一次平台限流，开始新的冷却期，返回冷却时长
+func (c *writeCooldown) Trip(account string) time.Duration {
+	if c.base <= 0 {
+		return 0
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	now := c.now()
+	if until, ok := c.until[account]; ok && now.Sub(until) > c.max {
+		// 上次冷却结束后已经很久没有被限流，重新从初始时间开始
+		c.strikes[account] = 0
+	}
+	c.strikes[account]++
+
+	d := c.base
+	for i := 1; i < c.strikes[account] && d < c.max; i++ {
+		d *= 2
+	}
+	d = min(d, c.max)
+
+	c.until[account] = now.Add(d)
+	return d
+}
@@ -0,0 +1,68 @@
+package main
+
+import (
+	"context"
+	"sync"
+)
+
+// accountWriteGuard 同一账号的写操作（发布、评论、编辑）串行执行，避免共用同一份
+// cookies 的多个编辑器互相干扰；读操作（列表、搜索、详情）不受影响，可以并发执行。
+type accountWriteGuard struct {
+	mu    sync.Mutex
+	locks map[string]*accountWriteLock
+}
+
+// accountWriteLock 单个账号的写锁
+type accountWriteLock struct {
+	sem   chan struct{}
+	depth int // 正在执行和排队等待的写操作数量，受 accountWriteGuard.mu 保护
+}
+
+// newAccountWriteGuard 创建账号写操作保护
+func newAccountWriteGuard() *accountWriteGuard {
+	return &accountWriteGuard{
+		locks: make(map[string]*accountWriteLock),
+	}
+}
+
+// Acquire 获取账号的写锁，排队期间 ctx 取消时返回错误。成功时返回释放函数。
+func (g *accountWriteGuard) Acquire(ctx context.Context, account string) (func(), error) {
+	g.mu.Lock()
+	lock, ok := g.locks[account]
+	if !ok {
+		lock = &accountWriteLock{sem: make(chan struct{}, 1)}
+		g.locks[account] = lock
+	}
+	lock.depth++
+	g.mu.Unlock()
+
+	done := func() {
+		g.mu.Lock()
+		lock.depth--
+		g.mu.Unlock()
+	}
+
+	select {
+	case lock.sem <- struct{}{}:
+		return func() {
+			<-lock.sem
+			done()
+		}, nil
+	case <-ctx.Done():
+		done()
+		return nil, ctx.Err()
+	}
+}
+
+// QueueDepth 返回每个账号正在执行和排队等待的写操作数量
+func (g *accountWriteGuard) QueueDepth() map[string]int {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	depth := make(map[string]int, len(g.locks))
+	for account, lock := range g.locks {
+		depth[account] = lock.depth
+	}
+
+	return depth
+}
@@ -0,0 +1,57 @@
+package main
+
+import (
+	"context"
+	"time"
+)
+
+// XHSService 处理器依赖的小红书服务。真实实现为 XiaohongshuService，
+// -mock 模式下使用返回固定数据的 mockService
+type XHSService interface {
+	CheckLoginStatus(ctx context.Context) (*LoginStatusResponse, error)
+	CheckAllLogins(ctx context.Context) (*AllLoginsResponse, error)
+	Logout(ctx context.Context) (*LogoutResponse, error)
+	PublishContent(ctx context.Context, req *PublishRequest) (*PublishResponse, error)
+	EditFeed(ctx context.Context, feedID, xsecToken string, updates EditFeedRequest) (*FeedDetailResponse, error)
+	SetFeedVisibility(ctx context.Context, feedID, xsecToken, visibility string) (*FeedVisibilityResponse, error)
+
+	ListFeeds(ctx context.Context) (*FeedsListResponse, error)
+	ListFeedsPage(ctx context.Context, query ListFeedsQuery) (*FeedsListResponse, error)
+	SearchFeeds(ctx context.Context, keyword string) (*FeedsListResponse, error)
+	ExploreFeeds(ctx context.Context, channel string, limit int) (*ExploreFeedsResponse, error)
+	SearchTopics(ctx context.Context, keyword string) (*TopicsResponse, error)
+	TrendingTopics(ctx context.Context) (*TopicsResponse, error)
+
+	GetFeedDetail(ctx context.Context, feedID, xsecToken string) (*FeedDetailResponse, error)
+	SearchAndDetail(ctx context.Context, keyword string, limit int) (*SearchDetailResponse, error)
+	GetFeedByURL(ctx context.Context, url string) (*FeedDetailResponse, error)
+	IsOwnNote(ctx context.Context, feedID, xsecToken string) (bool, error)
+	GetFeedLink(ctx context.Context, feedID, xsecToken string, short bool) (*FeedLinkResponse, error)
+	GetFeedTags(ctx context.Context, feedID, xsecToken string) (*FeedTagsResponse, error)
+	RelatedFeeds(ctx context.Context, feedID, xsecToken string) (*RelatedFeedsResponse, error)
+	ScreenshotFeed(ctx context.Context, feedID, xsecToken string, width int, scope string) (*ScreenshotFeedResponse, error)
+	ListComments(ctx context.Context, feedID, xsecToken string, limit int, cursor string) (*CommentsResponse, error)
+	GetCommentTree(ctx context.Context, feedID, xsecToken string, limit, repliesLimit int, cursor string) (*CommentTreeResponse, error)
+
+	UserProfile(ctx context.Context, userID, xsecToken string) (*UserProfileResponse, error)
+	UserProfileByURL(ctx context.Context, profileURL string) (*UserProfileResponse, error)
+	UserFeeds(ctx context.Context, userID, xsecToken string, limit int, cursor string) (*UserFeedsResponse, error)
+	UserCollections(ctx context.Context, userID, xsecToken string) (*UserCollectionsResponse, error)
+	MyProfile(ctx context.Context) (*MyProfileResponse, error)
+	FeedAnalytics(ctx context.Context, feedID string) (*FeedAnalyticsResponse, error)
+	ListNotifications(ctx context.Context, category string) (*NotificationsResponse, error)
+
+	PostCommentToFeed(ctx context.Context, feedID, xsecToken, content, image string) (*PostCommentResponse, error)
+	PostCommentsBatch(ctx context.Context, comments []PostCommentRequest, delay time.Duration) (*PostCommentsResponse, error)
+
+	DebugPage(ctx context.Context, pageURL string, screenshot bool) (*DebugPageResponse, error)
+	WriteQueueDepth() map[string]int
+	CacheStats() map[string]cacheStats
+	BrowserStats() browserStats
+}
+
+// 编译期确认两种实现都满足接口，新增服务方法时需要同时补齐 mock 实现
+var (
+	_ XHSService = (*XiaohongshuService)(nil)
+	_ XHSService = (*mockService)(nil)
+)
@@ -0,0 +1,56 @@
+package xiaohongshu
+
+import (
+	"context"
+	"time"
+
+	"github.com/go-rod/rod"
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+)
+
+// ErrCaptchaRequired 页面要求完成滑块/验证码验证，无头模式下无法继续
+var ErrCaptchaRequired = errors.New("小红书要求完成验证码验证，请使用 -headless=false 启动并在浏览器窗口中手动完成")
+
+// captchaSelector 滑块/验证码弹窗
+const captchaSelector = ".red-captcha, #red-captcha, .captcha-container, .verify-container, iframe[src*='captcha']"
+
+// captchaPollInterval 等待人工完成验证码时的检查间隔
+const captchaPollInterval = time.Second
+
+// HasCaptcha 判断页面上是否显示了验证码弹窗
+func HasCaptcha(page *rod.Page) bool {
+	result, err := page.Eval(`(selector) => Array.from(document.querySelectorAll(selector))
+		.some(el => el.offsetWidth > 0 && el.offsetHeight > 0)`, captchaSelector)
+	return err == nil && result.Value.Bool()
+}
+
+// WaitForCaptcha 等待验证码弹窗消失。无头模式下无法人工操作，直接返回 ErrCaptchaRequired；
+// 有界面模式下等待人工在浏览器窗口中完成验证，超过 maxWait 仍未完成时返回 ErrCaptchaRequired。
+func WaitForCaptcha(ctx context.Context, page *rod.Page, headless bool, maxWait time.Duration) error {
+	if headless || maxWait <= 0 {
+		return ErrCaptchaRequired
+	}
+
+	logrus.WithContext(ctx).Warnf("检测到验证码，请在浏览器窗口中完成验证（最多等待 %s）", maxWait)
+
+	deadline := time.NewTimer(maxWait)
+	defer deadline.Stop()
+	ticker := time.NewTicker(captchaPollInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-deadline.C:
+			logrus.WithContext(ctx).Warnf("等待 %s 后验证码仍未完成", maxWait)
+			return ErrCaptchaRequired
+		case <-ticker.C:
+			if !HasCaptcha(page) {
+				logrus.WithContext(ctx).Info("验证码已完成，继续执行")
+				return nil
+			}
+		}
+	}
+}
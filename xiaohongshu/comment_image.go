@@ -0,0 +1,64 @@
+package xiaohongshu
+
+import (
+	"context"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+)
+
+// ErrCommentImageUnsupported 评论框没有图片按钮，该笔记不支持图片评论
+var ErrCommentImageUnsupported = errors.New("该笔记不支持图片评论")
+
+// PostImageComment 打开笔记详情页，输入评论文字并通过评论框的图片按钮上传 imagePath 后发送。
+// 评论框没有图片按钮时不输入也不发送，返回 ErrCommentImageUnsupported，由调用方决定是否只发表文字
+func (c *CommentFeedAction) PostImageComment(ctx context.Context, feedID, xsecToken, content, imagePath string) error {
+	page := c.page.Context(ctx)
+
+	if err := Navigate(page, makeFeedCommentsURL(feedID, xsecToken)); err != nil {
+		return errors.Wrap(err, "打开笔记详情页失败")
+	}
+
+	placeholder, err := findElement(page, "div.input-box div.content-edit span")
+	if err != nil {
+		return errors.Wrap(err, "没有找到评论输入框")
+	}
+	if err := humanClick(placeholder); err != nil {
+		return errors.Wrap(err, "打开评论输入框失败")
+	}
+
+	// 图片按钮在评论框展开后才出现，背后是一个隐藏的文件选择框
+	uploadInput, err := page.Timeout(3 * time.Second).Element(`div.engage-bar input[type="file"]`)
+	if err != nil {
+		return ErrCommentImageUnsupported
+	}
+
+	contentElem, err := findElement(page, "div.input-box div.content-edit p.content-input")
+	if err != nil {
+		return errors.Wrap(err, "没有找到评论输入框")
+	}
+	if err := humanInput(contentElem, content); err != nil {
+		return errors.Wrap(err, "输入评论内容失败")
+	}
+
+	if err := uploadInput.SetFiles([]string{imagePath}); err != nil {
+		return errors.Wrap(err, "上传评论图片失败")
+	}
+	if _, err := findElement(page, "div.engage-bar .comment-picture img, div.engage-bar .image-preview img"); err != nil {
+		return errors.Wrap(err, "评论图片上传后没有出现预览")
+	}
+
+	submitButton, err := findElement(page, "div.bottom button.submit")
+	if err != nil {
+		return errors.Wrap(err, "没有找到发送按钮")
+	}
+	if err := humanClick(submitButton); err != nil {
+		return errors.Wrap(err, "发送评论失败")
+	}
+	time.Sleep(time.Second)
+
+	logrus.WithContext(ctx).Infof("图片评论发表完成: %s", feedID)
+
+	return nil
+}
@@ -0,0 +1,236 @@
+package xiaohongshu
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+
+	"github.com/go-rod/rod"
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+)
+
+const (
+	// DefaultCommentRepliesLimit 评论树中每条顶层评论默认最多加载的回复数
+	DefaultCommentRepliesLimit = 10
+	// MaxCommentRepliesLimit 评论树中每条顶层评论最多加载的回复数
+	MaxCommentRepliesLimit = 100
+	// maxReplyExpansions 每条顶层评论最多点击“展开更多回复”的次数
+	maxReplyExpansions = 10
+)
+
+// CommentThread 页面上的一条顶层评论及其已展开的回复，回复按时间顺序平铺，
+// 通过 TargetComment 指向被回复的评论
+type CommentThread struct {
+	FeedComment
+	SubComments       []CommentReply `json:"subComments"`
+	SubCommentHasMore bool           `json:"subCommentHasMore"`
+}
+
+// CommentReply 顶层评论下的一条回复
+type CommentReply struct {
+	FeedComment
+	TargetComment *struct {
+		ID       string          `json:"id"`
+		UserInfo FeedCommentUser `json:"userInfo"`
+	} `json:"targetComment,omitempty"`
+}
+
+// CommentNode 评论树的节点，Replies 为直接回复这条评论的回复
+type CommentNode struct {
+	ID         string           `json:"id"`
+	Author     FeedCommentUser  `json:"author"`
+	Content    string           `json:"content"`
+	LikeCount  int              `json:"like_count"`
+	CreateTime int64            `json:"create_time"` // 毫秒时间戳
+	IPLocation string           `json:"ip_location,omitempty"`
+	ReplyTo    *FeedCommentUser `json:"reply_to,omitempty"` // 回复的是另一条回复时，被回复的用户
+	Replies    []CommentNode    `json:"replies,omitempty"`
+
+	// 以下字段只出现在顶层评论中
+	ReplyCount  int  `json:"reply_count,omitempty"`  // 页面显示的回复总数
+	MoreReplies bool `json:"more_replies,omitempty"` // 还有回复没有加载，可增大 replies_limit 获取
+}
+
+// CommentTree 打开笔记详情页，返回从 cursor 开始的最多 limit 条顶层评论及下一页的游标。
+// 每条顶层评论点击“展开更多回复”加载最多 repliesLimit 条回复，并按回复关系组织成树。
+// 游标与 ListComments 的游标通用；滚动评论区的次数达到 -max-scrolls 时 truncated 为 true
+func (c *CommentsAction) CommentTree(ctx context.Context, feedID, xsecToken string, limit, repliesLimit int, cursor Cursor) ([]CommentNode, string, bool, error) {
+	if limit <= 0 {
+		limit = DefaultCommentsLimit
+	}
+	limit = min(limit, MaxCommentsLimit)
+	if repliesLimit <= 0 {
+		repliesLimit = DefaultCommentRepliesLimit
+	}
+	repliesLimit = min(repliesLimit, MaxCommentRepliesLimit)
+
+	page := c.page.Context(ctx)
+
+	if err := Navigate(page, makeFeedCommentsURL(feedID, xsecToken)); err != nil {
+		return nil, "", false, errors.Wrap(err, "打开笔记详情页失败")
+	}
+
+	comments, truncated, err := scrollComments(page, feedID, cursor.Offset+limit+1)
+	if err != nil {
+		return nil, "", false, err
+	}
+	offset, end, next := commentsPage(feedID, comments, limit, cursor)
+
+	for _, comment := range comments[offset:end] {
+		if err := expandReplies(ctx, page, feedID, comment.ID, repliesLimit); err != nil {
+			return nil, "", false, err
+		}
+	}
+
+	threads, err := loadedCommentThreads(page, feedID)
+	if err != nil {
+		return nil, "", false, err
+	}
+	byID := make(map[string]CommentThread, len(threads))
+	for _, thread := range threads {
+		byID[thread.ID] = thread
+	}
+
+	nodes := make([]CommentNode, 0, end-offset)
+	for _, comment := range comments[offset:end] {
+		thread, ok := byID[comment.ID]
+		if !ok {
+			thread = CommentThread{FeedComment: comment}
+		}
+		nodes = append(nodes, NewCommentNode(thread, repliesLimit))
+	}
+
+	return nodes, next, truncated, nil
+}
+
+// expandReplies 点击顶层评论下的“展开更多回复”，直到已加载的回复不少于 repliesLimit 条或没有更多回复
+func expandReplies(ctx context.Context, page *rod.Page, feedID, commentID string, repliesLimit int) error {
+	for i := 0; i < maxReplyExpansions; i++ {
+		thread, err := loadedCommentThread(page, feedID, commentID)
+		if err != nil {
+			return err
+		}
+		if thread == nil || len(thread.SubComments) >= repliesLimit || !thread.SubCommentHasMore {
+			return nil
+		}
+
+		clicked, err := page.Eval(`(id) => {
+			const item = document.querySelector('#comment-' + id);
+			const parent = item && item.closest('.parent-comment');
+			const more = parent && parent.querySelector('.show-more');
+			if (!more) {
+				return false;
+			}
+			more.scrollIntoView({ block: 'center' });
+			more.click();
+			return true;
+		}`, commentID)
+		if err != nil {
+			return errors.Wrap(err, "展开评论回复失败")
+		}
+		if !clicked.Value.Bool() {
+			logrus.WithContext(ctx).Debugf("评论 %s 没有找到“展开更多回复”", commentID)
+			return nil
+		}
+
+		if err := humanPause(ctx, time.Second, 2*time.Second); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// loadedCommentThread 读取一条顶层评论及其已展开的回复，评论不在已加载的列表中时返回 nil
+func loadedCommentThread(page *rod.Page, feedID, commentID string) (*CommentThread, error) {
+	threads, err := loadedCommentThreads(page, feedID)
+	if err != nil {
+		return nil, err
+	}
+	for i := range threads {
+		if threads[i].ID == commentID {
+			return &threads[i], nil
+		}
+	}
+	return nil, nil
+}
+
+// loadedCommentThreads 读取详情页当前已加载的顶层评论及其回复，与 loadedComments 读取同一份数据
+func loadedCommentThreads(page *rod.Page, feedID string) ([]CommentThread, error) {
+	result, err := page.Eval(`(feedID) => {
+		const state = window.__INITIAL_STATE__ || {};
+		const map = state.note && state.note.noteDetailMap;
+		const details = (map && (map._value || map.value || map)) || {};
+		const comments = (details[feedID] && details[feedID].comments) || {};
+		return JSON.stringify(Array.isArray(comments.list) ? comments.list : []);
+	}`, feedID)
+	if err != nil {
+		return nil, errors.Wrap(err, "获取评论回复失败")
+	}
+
+	var threads []CommentThread
+	if err := json.Unmarshal([]byte(result.Value.String()), &threads); err != nil {
+		return nil, errors.Wrap(err, "解析评论回复失败")
+	}
+	return threads, nil
+}
+
+// NewCommentNode 将顶层评论及其回复组织成评论树，最多保留 repliesLimit 条回复。
+// 回复的是另一条已加载的回复时挂在那条回复下面，否则挂在顶层评论下面
+func NewCommentNode(thread CommentThread, repliesLimit int) CommentNode {
+	root := commentNode(thread.FeedComment)
+	root.ReplyCount = max(countOrZero(thread.SubCommentCount), len(thread.SubComments))
+
+	replies := thread.SubComments
+	if len(replies) > repliesLimit {
+		replies = replies[:repliesLimit]
+	}
+	root.MoreReplies = thread.SubCommentHasMore || len(replies) < root.ReplyCount
+
+	// 回复按时间顺序排列，被回复的评论总在前面
+	loaded := make(map[string]bool, len(replies))
+	children := make(map[string][]CommentReply, len(replies))
+	for _, reply := range replies {
+		parentID := thread.ID
+		if reply.TargetComment != nil && loaded[reply.TargetComment.ID] {
+			parentID = reply.TargetComment.ID
+		}
+		loaded[reply.ID] = true
+		children[parentID] = append(children[parentID], reply)
+	}
+
+	root.Replies = replyNodes(thread.ID, thread.ID, children)
+	return root
+}
+
+// replyNodes 递归构建 parentID 下的回复节点，rootID 为所属顶层评论
+func replyNodes(rootID, parentID string, children map[string][]CommentReply) []CommentNode {
+	replies := children[parentID]
+	if len(replies) == 0 {
+		return nil
+	}
+
+	nodes := make([]CommentNode, 0, len(replies))
+	for _, reply := range replies {
+		node := commentNode(reply.FeedComment)
+		if reply.TargetComment != nil && reply.TargetComment.ID != rootID {
+			user := reply.TargetComment.UserInfo
+			node.ReplyTo = &user
+		}
+		node.Replies = replyNodes(rootID, reply.ID, children)
+		nodes = append(nodes, node)
+	}
+	return nodes
+}
+
+// commentNode 由页面上的评论生成不含回复的节点
+func commentNode(comment FeedComment) CommentNode {
+	return CommentNode{
+		ID:         comment.ID,
+		Author:     comment.UserInfo,
+		Content:    comment.Content,
+		LikeCount:  countOrZero(comment.LikeCount),
+		CreateTime: comment.CreateTime,
+		IPLocation: comment.IPLocation,
+	}
+}
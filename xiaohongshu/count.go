@@ -0,0 +1,59 @@
+package xiaohongshu
+
+import (
+	"strconv"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+// countUnits 小红书页面上常见的数量单位
+var countUnits = []struct {
+	suffix     string
+	multiplier float64
+}{
+	{"亿", 1e8},
+	{"万", 1e4},
+	{"w", 1e4},
+	{"W", 1e4},
+	{"k", 1e3},
+	{"K", 1e3},
+}
+
+// parseCount 将页面上展示的数量解析为整数。
+// 支持纯数字、千分位逗号、小数、"万"/"w"、"亿"、"k" 单位以及 "999+" 这类末尾带 "+" 的写法，
+// "+" 表示下限，按去掉 "+" 后的值返回。空字符串视为 0。
+func parseCount(s string) (int, error) {
+	raw := s
+
+	s = strings.TrimSpace(s)
+	s = strings.TrimSuffix(s, "+")
+	s = strings.ReplaceAll(s, ",", "")
+	if s == "" {
+		return 0, nil
+	}
+
+	multiplier := 1.0
+	for _, unit := range countUnits {
+		if strings.HasSuffix(s, unit.suffix) {
+			s, multiplier = strings.TrimSpace(strings.TrimSuffix(s, unit.suffix)), unit.multiplier
+			break
+		}
+	}
+
+	f, err := strconv.ParseFloat(s, 64)
+	if err != nil || f < 0 {
+		return 0, errors.Errorf("无法解析数量: %q", raw)
+	}
+
+	return int(f*multiplier + 0.5), nil
+}
+
+// countOrZero 解析数量，无法解析时返回 0
+func countOrZero(s string) int {
+	n, err := parseCount(s)
+	if err != nil {
+		return 0
+	}
+	return n
+}
@@ -0,0 +1,40 @@
+package xiaohongshu
+
+import (
+	"encoding/json"
+)
+
+// 以下 MarshalJSON 在原始字符串字段之外输出解析后的数值，
+// 下游做统计分析时不需要各自实现一遍数量解析。
+
+// MarshalJSON 输出互动数据及其数值形式
+func (i InteractInfo) MarshalJSON() ([]byte, error) {
+	type raw InteractInfo
+
+	return json.Marshal(struct {
+		raw
+		LikedCountNum     int `json:"likedCountNum"`
+		CollectedCountNum int `json:"collectedCountNum"`
+		CommentCountNum   int `json:"commentCountNum"`
+		SharedCountNum    int `json:"sharedCountNum"`
+	}{
+		raw:               raw(i),
+		LikedCountNum:     countOrZero(i.LikedCount),
+		CollectedCountNum: countOrZero(i.CollectedCount),
+		CommentCountNum:   countOrZero(i.CommentCount),
+		SharedCountNum:    countOrZero(i.SharedCount),
+	})
+}
+
+// MarshalJSON 输出用户关注/粉丝/获赞数及其数值形式
+func (u UserInteractions) MarshalJSON() ([]byte, error) {
+	type raw UserInteractions
+
+	return json.Marshal(struct {
+		raw
+		CountNum int `json:"countNum"`
+	}{
+		raw:      raw(u),
+		CountNum: countOrZero(u.Count),
+	})
+}
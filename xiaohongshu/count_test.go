@@ -0,0 +1,74 @@
+package xiaohongshu
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseCount(t *testing.T) {
+	tests := []struct {
+		input   string
+		want    int
+		wantErr bool
+	}{
+		{input: "", want: 0},
+		{input: "56", want: 56},
+		{input: " 1,024 ", want: 1024},
+		{input: "999+", want: 999},
+		{input: "1.2万", want: 12000},
+		{input: "10万+", want: 100000},
+		{input: "3w", want: 30000},
+		{input: "2.5W", want: 25000},
+		{input: "1.05w", want: 10500},
+		{input: "1.5k", want: 1500},
+		{input: "1.5亿", want: 150000000},
+		{input: "赞", wantErr: true},
+		{input: "万", wantErr: true},
+		{input: "-1", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			got, err := parseCount(tt.input)
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestInteractInfoMarshalJSONFixture(t *testing.T) {
+	data, err := os.ReadFile(filepath.Join("testdata", "feed_card_counts.json"))
+	require.NoError(t, err)
+
+	var feeds []Feed
+	require.NoError(t, json.Unmarshal(data, &feeds))
+
+	want := []map[string]int{
+		{"likedCountNum": 12000, "collectedCountNum": 30000, "commentCountNum": 999, "sharedCountNum": 56},
+		{"likedCountNum": 100000, "collectedCountNum": 25000, "commentCountNum": 1024, "sharedCountNum": 0},
+		{"likedCountNum": 10500, "collectedCountNum": 0, "commentCountNum": 0, "sharedCountNum": 150000000},
+	}
+	require.Len(t, feeds, len(want))
+
+	for i, feed := range feeds {
+		encoded, err := json.Marshal(feed.NoteCard.InteractInfo)
+		require.NoError(t, err)
+
+		var got map[string]any
+		require.NoError(t, json.Unmarshal(encoded, &got))
+		for key, value := range want[i] {
+			assert.EqualValues(t, value, got[key], "%s: %s", feed.ID, key)
+		}
+		// 原始字符串保留不变
+		assert.Equal(t, feed.NoteCard.InteractInfo.LikedCount, got["likedCount"])
+	}
+}
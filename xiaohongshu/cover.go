@@ -0,0 +1,119 @@
+package xiaohongshu
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/binary"
+	"image/gif"
+	"os"
+	"strings"
+
+	"github.com/go-rod/rod"
+	"github.com/go-rod/rod/lib/proto"
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+	"github.com/xpzouying/xiaohongshu-mcp/configs"
+)
+
+// WarnCoverFrameExtracted 封面为动图时，提取第一帧作为静态封面后返回的警告
+const WarnCoverFrameExtracted = "封面为动图，小红书封面必须为静态图片，已提取第一帧作为封面"
+
+// PrepareCover 检查封面图片，动图（GIF、WebP）会被转换为第一帧的静态 JPEG。
+// 返回实际上传的封面路径及警告；生成了新文件时 tempFile 为 true，调用方负责在上传后删除。
+func PrepareCover(browser *rod.Browser, coverPath string) (path string, warning string, tempFile bool, err error) {
+	data, err := os.ReadFile(coverPath)
+	if err != nil {
+		return "", "", false, errors.Wrap(err, "读取封面失败")
+	}
+
+	mimeType, animated := detectAnimatedImage(data)
+	if !animated {
+		return coverPath, "", false, nil
+	}
+
+	logrus.WithContext(browser.GetContext()).Infof("封面 %s 为动图，提取第一帧作为静态封面", coverPath)
+
+	frame, err := renderFirstFrame(browser, mimeType, data)
+	if err != nil {
+		return "", "", false, err
+	}
+
+	file, err := os.CreateTemp(configs.GetTempDir(), "xhs-cover-*.jpg")
+	if err != nil {
+		return "", "", false, errors.Wrap(err, "创建封面临时文件失败")
+	}
+	defer file.Close()
+
+	if _, err := file.Write(frame); err != nil {
+		os.Remove(file.Name())
+		return "", "", false, errors.Wrap(err, "写入封面临时文件失败")
+	}
+
+	return file.Name(), WarnCoverFrameExtracted, true, nil
+}
+
+// detectAnimatedImage 判断图片是否为动图，返回图片的 MIME 类型
+func detectAnimatedImage(data []byte) (mimeType string, animated bool) {
+	switch {
+	case bytes.HasPrefix(data, []byte("GIF8")):
+		g, err := gif.DecodeAll(bytes.NewReader(data))
+		return "image/gif", err == nil && len(g.Image) > 1
+	case len(data) >= 12 && string(data[0:4]) == "RIFF" && string(data[8:12]) == "WEBP":
+		return "image/webp", isAnimatedWebP(data)
+	default:
+		return "", false
+	}
+}
+
+// isAnimatedWebP 检查 WebP 扩展格式（VP8X）头中的动画标志位
+func isAnimatedWebP(data []byte) bool {
+	// RIFF 头 12 字节，之后是第一个 chunk：4 字节类型 + 4 字节长度 + 数据
+	if len(data) < 21 || string(data[12:16]) != "VP8X" || binary.LittleEndian.Uint32(data[16:20]) < 1 {
+		return false
+	}
+
+	const animationFlag = 0x02
+	return data[20]&animationFlag != 0
+}
+
+// renderFirstFrame 在空白页中用 canvas 绘制动图的第一帧并导出为 JPEG。
+// 使用浏览器解码是因为标准库不支持 WebP，且可以避开站点页面的 CSP 限制。
+func renderFirstFrame(browser *rod.Browser, mimeType string, data []byte) ([]byte, error) {
+	page, err := browser.Page(proto.TargetCreateTarget{URL: "about:blank"})
+	if err != nil {
+		return nil, errors.Wrap(err, "创建封面处理页面失败")
+	}
+	defer page.Close()
+
+	src := "data:" + mimeType + ";base64," + base64.StdEncoding.EncodeToString(data)
+	result, err := page.Eval(`async (src) => {
+		const img = new Image();
+		img.src = src;
+		await img.decode();
+		const canvas = document.createElement('canvas');
+		canvas.width = img.naturalWidth;
+		canvas.height = img.naturalHeight;
+		const ctx = canvas.getContext('2d');
+		// JPEG 不支持透明，透明区域填充为白色
+		ctx.fillStyle = '#fff';
+		ctx.fillRect(0, 0, canvas.width, canvas.height);
+		ctx.drawImage(img, 0, 0);
+		return canvas.toDataURL('image/jpeg', 0.92);
+	}`, src)
+	if err != nil {
+		return nil, errors.Wrap(err, "提取动图第一帧失败")
+	}
+
+	dataURL := result.Value.String()
+	encoded, ok := strings.CutPrefix(dataURL, "data:image/jpeg;base64,")
+	if !ok {
+		return nil, errors.New("提取动图第一帧失败: 浏览器没有返回 JPEG 图片")
+	}
+
+	frame, err := base64.StdEncoding.DecodeString(encoded)
+	if err != nil {
+		return nil, errors.Wrap(err, "解码封面图片失败")
+	}
+
+	return frame, nil
+}
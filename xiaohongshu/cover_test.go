@@ -0,0 +1,72 @@
+package xiaohongshu
+
+import (
+	"bytes"
+	"image"
+	"image/color"
+	"image/gif"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// encodeTestGIF 生成包含 frames 帧的 GIF 图片
+func encodeTestGIF(t *testing.T, frames int) []byte {
+	t.Helper()
+
+	palette := color.Palette{color.White, color.Black}
+	g := &gif.GIF{}
+	for i := 0; i < frames; i++ {
+		frame := image.NewPaletted(image.Rect(0, 0, 4, 4), palette)
+		frame.SetColorIndex(i%4, i%4, 1)
+		g.Image = append(g.Image, frame)
+		g.Delay = append(g.Delay, 10)
+	}
+
+	var buf bytes.Buffer
+	require.NoError(t, gif.EncodeAll(&buf, g))
+	return buf.Bytes()
+}
+
+// webpHeader 构造只含 VP8X chunk 的 WebP 文件头，flags 为扩展格式的标志位
+func webpHeader(flags byte) []byte {
+	data := []byte("RIFF\x00\x00\x00\x00WEBPVP8X\x0a\x00\x00\x00")
+	return append(data, flags, 0, 0, 0, 0, 0, 0, 0, 0, 0)
+}
+
+func TestDetectAnimatedImage(t *testing.T) {
+	tests := []struct {
+		name         string
+		data         []byte
+		wantMIME     string
+		wantAnimated bool
+	}{
+		{"animated gif", encodeTestGIF(t, 2), "image/gif", true},
+		{"single frame gif", encodeTestGIF(t, 1), "image/gif", false},
+		{"animated webp", webpHeader(0x02), "image/webp", true},
+		{"static webp", webpHeader(0x00), "image/webp", false},
+		{"jpeg", []byte{0xff, 0xd8, 0xff, 0xe0}, "", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mimeType, animated := detectAnimatedImage(tt.data)
+			assert.Equal(t, tt.wantMIME, mimeType)
+			assert.Equal(t, tt.wantAnimated, animated)
+		})
+	}
+}
+
+func TestPrepareCoverStaticGIF(t *testing.T) {
+	coverPath := filepath.Join(t.TempDir(), "cover.gif")
+	require.NoError(t, os.WriteFile(coverPath, encodeTestGIF(t, 1), 0o644))
+
+	// 静态图片直接使用原文件，不需要浏览器
+	path, warning, tempFile, err := PrepareCover(nil, coverPath)
+	require.NoError(t, err)
+	assert.Equal(t, coverPath, path)
+	assert.Empty(t, warning)
+	assert.False(t, tempFile)
+}
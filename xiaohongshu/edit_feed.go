@@ -0,0 +1,131 @@
+package xiaohongshu
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/go-rod/rod"
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+	"github.com/xpzouying/xiaohongshu-mcp/configs"
+)
+
+// ErrImageEditUnsupported 笔记编辑页不允许修改图片（如部分视频笔记、已关联商品的笔记）
+var ErrImageEditUnsupported = errors.New("该笔记不支持修改图片，只能修改标题、正文和话题")
+
+// EditFeedContent 笔记编辑内容，nil 表示不修改对应字段
+type EditFeedContent struct {
+	Title      *string
+	Content    *string
+	Tags       []string
+	ImagePaths []string
+}
+
+// EditFeedAction 笔记编辑
+type EditFeedAction struct {
+	page *rod.Page
+}
+
+// NewEditFeedAction 创建笔记编辑 action
+func NewEditFeedAction(page *rod.Page) *EditFeedAction {
+	return &EditFeedAction{page: page}
+}
+
+// makeEditFeedURL 创作者中心的笔记编辑页
+func makeEditFeedURL(feedID string) string {
+	return configs.CreatorURL(fmt.Sprintf("/publish/update?id=%s&noteType=normal", feedID))
+}
+
+// EditFeed 打开笔记编辑页，只修改提供了的字段，然后保存
+func (e *EditFeedAction) EditFeed(ctx context.Context, feedID string, content EditFeedContent) error {
+	page := e.page.Context(ctx)
+
+	if err := Navigate(page, makeEditFeedURL(feedID)); err != nil {
+		return errors.Wrap(err, "打开笔记编辑页失败")
+	}
+
+	if content.ImagePaths != nil {
+		if err := replaceImages(page, content.ImagePaths); err != nil {
+			return err
+		}
+	}
+
+	if content.Title != nil {
+		titleElem, err := findElement(page, "div.d-input input")
+		if err != nil {
+			return errors.Wrap(err, "没有找到标题输入框")
+		}
+		if err := titleElem.SelectAllText(); err != nil {
+			return errors.Wrap(err, "修改标题失败")
+		}
+		if err := humanInput(titleElem, *content.Title); err != nil {
+			return errors.Wrap(err, "修改标题失败")
+		}
+		time.Sleep(500 * time.Millisecond)
+	}
+
+	if content.Content != nil || content.Tags != nil {
+		contentElem, err := findElement(page, "div.ql-editor, div.tiptap.ProseMirror")
+		if err != nil {
+			return errors.Wrap(err, "没有找到正文输入框")
+		}
+
+		if content.Content != nil {
+			// 正文与话题在同一个编辑器中，替换正文会一并清空原有话题
+			if err := contentElem.SelectAllText(); err != nil {
+				return errors.Wrap(err, "修改正文失败")
+			}
+			if err := humanInput(contentElem, *content.Content); err != nil {
+				return errors.Wrap(err, "修改正文失败")
+			}
+		} else {
+			// 只修改话题时，先删除正文中已有的话题
+			if _, err := contentElem.Eval(`() => this.querySelectorAll('a.tiptap-topic, a.topic').forEach(el => el.remove())`); err != nil {
+				return errors.Wrap(err, "清除原有话题失败")
+			}
+		}
+
+		inputTags(contentElem, content.Tags)
+		time.Sleep(500 * time.Millisecond)
+	}
+
+	submitButton, err := findElement(page, "div.submit div.d-button-content")
+	if err != nil {
+		return errors.Wrap(err, "没有找到保存按钮")
+	}
+	if err := humanClick(submitButton); err != nil {
+		return errors.Wrap(err, "保存笔记失败")
+	}
+	time.Sleep(3 * time.Second)
+
+	logrus.WithContext(ctx).Infof("笔记编辑完成: %s", feedID)
+
+	return nil
+}
+
+// replaceImages 删除笔记原有图片并上传新图片，编辑页没有图片上传入口时返回 ErrImageEditUnsupported
+func replaceImages(page *rod.Page, imagePaths []string) error {
+	uploadInput, err := page.Timeout(5 * time.Second).Element("input.upload-input")
+	if err != nil {
+		return ErrImageEditUnsupported
+	}
+
+	deleteButtons, err := page.Elements("div.img-preview-area .delete")
+	if err != nil {
+		return errors.Wrap(err, "获取原有图片失败")
+	}
+	for _, button := range deleteButtons {
+		if err := humanClick(button); err != nil {
+			return errors.Wrap(err, "删除原有图片失败")
+		}
+		time.Sleep(300 * time.Millisecond)
+	}
+
+	if err := uploadInput.SetFiles(imagePaths); err != nil {
+		return errors.Wrap(err, "上传图片失败")
+	}
+	time.Sleep(3 * time.Second)
+
+	return nil
+}
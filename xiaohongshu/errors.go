@@ -0,0 +1,6 @@
+package xiaohongshu
+
+import "github.com/pkg/errors"
+
+// ErrNotLoggedIn 当前浏览器会话没有登录小红书
+var ErrNotLoggedIn = errors.New("未登录小红书，请先登录")
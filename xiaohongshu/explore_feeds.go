@@ -0,0 +1,153 @@
+package xiaohongshu
+
+import (
+	"context"
+	"encoding/json"
+	"net/url"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/go-rod/rod"
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+	"github.com/xpzouying/xiaohongshu-mcp/configs"
+)
+
+const (
+	// DefaultExploreFeedsLimit 每次获取发现页笔记的默认数量
+	DefaultExploreFeedsLimit = 30
+	// MaxExploreFeedsLimit 每次获取发现页笔记的最大数量
+	MaxExploreFeedsLimit = 100
+	// exploreIdleScrolls 连续多少次滚动没有加载出新笔记时停止
+	exploreIdleScrolls = 3
+)
+
+// exploreChannelPrefix 发现页频道ID的前缀
+const exploreChannelPrefix = "homefeed"
+
+// exploreChannels 发现页顶部的频道及其频道ID
+var exploreChannels = map[string]string{
+	"推荐": "homefeed_recommend",
+	"穿搭": "homefeed.fashion_v3",
+	"美食": "homefeed.food_v3",
+	"彩妆": "homefeed.cosmetics_v3",
+	"影视": "homefeed.movie_and_tv_v3",
+	"职场": "homefeed.career_v3",
+	"情感": "homefeed.love_v3",
+	"家居": "homefeed.household_product_v3",
+	"游戏": "homefeed.gaming_v3",
+	"旅行": "homefeed.travel_v3",
+	"健身": "homefeed.fitness_v3",
+}
+
+// ExploreChannelNames 发现页支持按名称选择的频道
+func ExploreChannelNames() []string {
+	names := make([]string, 0, len(exploreChannels))
+	for name := range exploreChannels {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// ExploreChannelID 将频道名称（如“美食”）转换为频道ID，也接受 homefeed 开头的频道ID原样使用。
+// channel 为空时返回推荐频道；无法识别时返回 false
+func ExploreChannelID(channel string) (string, bool) {
+	channel = strings.TrimSpace(channel)
+	if channel == "" {
+		return exploreChannels["推荐"], true
+	}
+	if id, ok := exploreChannels[channel]; ok {
+		return id, true
+	}
+	if strings.HasPrefix(channel, exploreChannelPrefix) {
+		return channel, true
+	}
+	return "", false
+}
+
+// makeExploreURL 发现页链接
+func makeExploreURL(channelID string) string {
+	return configs.SiteURL("/explore?channel_id=" + url.QueryEscape(channelID))
+}
+
+// ExploreAction 读取发现页的推荐笔记
+type ExploreAction struct {
+	page *rod.Page
+}
+
+// NewExploreAction 创建读取发现页的 action
+func NewExploreAction(page *rod.Page) *ExploreAction {
+	return &ExploreAction{page: page}
+}
+
+// ExploreFeeds 打开发现页的 channelID 频道，滚动加载推荐笔记，返回最多 limit 条。
+// 推荐内容每次打开都不同，不支持游标分页，需要更多笔记时增大 limit。
+// 滚动次数达到 -max-scrolls 仍未加载够时 truncated 为 true，返回已加载的部分
+func (a *ExploreAction) ExploreFeeds(ctx context.Context, channelID string, limit int) ([]Feed, bool, error) {
+	if limit <= 0 {
+		limit = DefaultExploreFeedsLimit
+	}
+	limit = min(limit, MaxExploreFeedsLimit)
+
+	page := a.page.Context(ctx)
+
+	if err := Navigate(page, makeExploreURL(channelID)); err != nil {
+		return nil, false, errors.Wrap(err, "打开发现页失败")
+	}
+
+	feeds, err := loadedExploreFeeds(page)
+	if err != nil {
+		return nil, false, err
+	}
+
+	idle, scrolls, maxScrolls := 0, 0, configs.GetMaxScrolls()
+	for ; len(feeds) < limit && idle < exploreIdleScrolls && scrolls < maxScrolls; scrolls++ {
+		if _, err := page.Eval(`() => window.scrollTo(0, document.body.scrollHeight)`); err != nil {
+			return nil, false, errors.Wrap(err, "滚动发现页失败")
+		}
+		time.Sleep(1500 * time.Millisecond)
+
+		more, err := loadedExploreFeeds(page)
+		if err != nil {
+			return nil, false, err
+		}
+		if len(more) > len(feeds) {
+			idle = 0
+		} else {
+			idle++
+		}
+		feeds = more
+	}
+
+	truncated := len(feeds) < limit && idle < exploreIdleScrolls && scrolls >= maxScrolls
+	if truncated {
+		logrus.WithContext(ctx).Warnf("发现页频道 %s 滚动 %d 次后停止，只加载了 %d 条笔记", channelID, scrolls, len(feeds))
+	} else {
+		logrus.WithContext(ctx).Infof("发现页频道 %s 已加载 %d 条笔记", channelID, len(feeds))
+	}
+
+	return feeds[:min(limit, len(feeds))], truncated, nil
+}
+
+// loadedExploreFeeds 读取发现页当前已加载的笔记，按笔记ID去重。笔记存放在 __INITIAL_STATE__.feed.feeds 中
+func loadedExploreFeeds(page *rod.Page) ([]Feed, error) {
+	result, err := page.Eval(`() => {
+		const feed = window.__INITIAL_STATE__ && window.__INITIAL_STATE__.feed;
+		const feeds = feed && feed.feeds;
+		const list = feeds && (feeds._value || feeds.value || feeds);
+		return JSON.stringify(Array.isArray(list) ? list : []);
+	}`)
+	if err != nil {
+		return nil, errors.Wrap(err, "获取发现页笔记失败")
+	}
+
+	var feeds []Feed
+	if err := json.Unmarshal([]byte(result.Value.String()), &feeds); err != nil {
+		return nil, errors.Wrap(err, "解析发现页笔记失败")
+	}
+
+	feeds, _ = DedupeFeeds(feeds)
+	return feeds, nil
+}
@@ -0,0 +1,161 @@
+package xiaohongshu
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"math"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"github.com/go-rod/rod"
+	"github.com/pkg/errors"
+	"github.com/xpzouying/xiaohongshu-mcp/configs"
+)
+
+// FeedAnalytics 创作者中心的单篇笔记数据，只有笔记作者能看到。
+// 页面没有展示的计数为 0，是否展示以 Metrics 中是否有对应的指标为准
+type FeedAnalytics struct {
+	Views       int `json:"views"`       // 观看（阅读）数
+	Impressions int `json:"impressions"` // 曝光数
+	Reach       int `json:"reach"`       // 观看人数，页面没有单独展示时为 0
+	Likes       int `json:"likes"`
+	Comments    int `json:"comments"`
+	Collects    int `json:"collects"`
+	Shares      int `json:"shares"`
+	Follows     int `json:"follows"` // 通过该笔记涨粉数
+
+	CoverClickRate float64 `json:"cover_click_rate"` // 封面点击率，百分比
+	EngagementRate float64 `json:"engagement_rate"`  // 互动率，百分比
+	// 页面没有展示互动率时，按（点赞+评论+收藏+分享）/ 观看数计算
+	EngagementRateComputed bool `json:"engagement_rate_computed,omitempty"`
+
+	Metrics map[string]string `json:"metrics"` // 页面上的全部指标，指标名到原始文本
+}
+
+// feedAnalyticsLabels 创作者中心的指标名，同一指标在不同版本的页面上名称略有不同
+var feedAnalyticsLabels = []struct {
+	names []string
+	count func(a *FeedAnalytics) *int
+	rate  func(a *FeedAnalytics) *float64
+}{
+	{names: []string{"观看数", "观看量", "阅读数", "阅读量", "浏览数", "浏览量"}, count: func(a *FeedAnalytics) *int { return &a.Views }},
+	{names: []string{"曝光数", "曝光量", "曝光"}, count: func(a *FeedAnalytics) *int { return &a.Impressions }},
+	{names: []string{"观看人数", "阅读人数", "触达人数", "覆盖人数"}, count: func(a *FeedAnalytics) *int { return &a.Reach }},
+	{names: []string{"点赞数", "点赞"}, count: func(a *FeedAnalytics) *int { return &a.Likes }},
+	{names: []string{"评论数", "评论"}, count: func(a *FeedAnalytics) *int { return &a.Comments }},
+	{names: []string{"收藏数", "收藏"}, count: func(a *FeedAnalytics) *int { return &a.Collects }},
+	{names: []string{"分享数", "分享"}, count: func(a *FeedAnalytics) *int { return &a.Shares }},
+	{names: []string{"涨粉数", "涨粉"}, count: func(a *FeedAnalytics) *int { return &a.Follows }},
+	{names: []string{"封面点击率", "点击率"}, rate: func(a *FeedAnalytics) *float64 { return &a.CoverClickRate }},
+	{names: []string{"互动率"}, rate: func(a *FeedAnalytics) *float64 { return &a.EngagementRate }},
+}
+
+// NewFeedAnalytics 根据页面上的指标（指标名到原始文本）解析笔记数据，无法解析的指标只保留在 Metrics 中
+func NewFeedAnalytics(metrics map[string]string) *FeedAnalytics {
+	a := &FeedAnalytics{Metrics: metrics}
+
+	for _, label := range feedAnalyticsLabels {
+		for _, name := range label.names {
+			raw, ok := metrics[name]
+			if !ok {
+				continue
+			}
+			if label.count != nil {
+				if n, err := parseCount(raw); err == nil {
+					*label.count(a) = n
+				}
+			} else if rate, err := parseRate(raw); err == nil {
+				*label.rate(a) = rate
+			}
+			break
+		}
+	}
+
+	if _, shown := metrics["互动率"]; !shown && a.Views > 0 {
+		interactions := a.Likes + a.Comments + a.Collects + a.Shares
+		a.EngagementRate = math.Round(float64(interactions)/float64(a.Views)*10000) / 100
+		a.EngagementRateComputed = true
+	}
+
+	return a
+}
+
+// parseRate 解析百分比文本，如 "12.5%"，返回去掉 % 的数值
+func parseRate(s string) (float64, error) {
+	s = strings.TrimSpace(strings.TrimSuffix(strings.TrimSpace(s), "%"))
+	rate, err := strconv.ParseFloat(s, 64)
+	if err != nil {
+		return 0, errors.Errorf("无法解析百分比: %q", s)
+	}
+	return rate, nil
+}
+
+// FeedAnalyticsAction 创作者中心的笔记数据
+type FeedAnalyticsAction struct {
+	page *rod.Page
+}
+
+// NewFeedAnalyticsAction 创建笔记数据 action
+func NewFeedAnalyticsAction(page *rod.Page) *FeedAnalyticsAction {
+	return &FeedAnalyticsAction{page: page}
+}
+
+// makeFeedAnalyticsURL 创作者中心的单篇笔记数据页
+func makeFeedAnalyticsURL(feedID string) string {
+	return configs.CreatorURL(fmt.Sprintf("/statistics/note-detail?noteId=%s", url.QueryEscape(feedID)))
+}
+
+// feedMetricsJS 按文档顺序读取页面上的叶子节点文本，指标名后紧跟的数值、百分比或时长视为该指标的值
+const feedMetricsJS = `() => {
+	const texts = [];
+	const walker = document.createTreeWalker(document.querySelector('#app, main, body') || document.body, NodeFilter.SHOW_TEXT);
+	while (walker.nextNode()) {
+		const text = (walker.currentNode.textContent || '').trim();
+		const el = walker.currentNode.parentElement;
+		if (text && el && el.offsetWidth > 0) {
+			texts.push(text);
+		}
+	}
+	const isLabel = t => t.length <= 8 && /^[一-龥]+$/.test(t);
+	const isValue = t => /^[\d.,]+\s*(万|w|W|亿|k|K|%|s|秒|分|分钟)?\+?$/.test(t) || /^\d+(分|min)\d+(秒|s)$/.test(t);
+	const metrics = {};
+	for (let i = 0; i + 1 < texts.length; i++) {
+		if (isLabel(texts[i]) && isValue(texts[i + 1]) && !(texts[i] in metrics)) {
+			metrics[texts[i]] = texts[i + 1];
+		}
+	}
+	return metrics;
+}`
+
+// Analytics 打开创作者中心的笔记数据页，读取观看、曝光、互动等指标。
+// 创作者中心只展示当前账号自己的笔记，查不到数据时返回 ErrNotNoteOwner
+func (a *FeedAnalyticsAction) Analytics(ctx context.Context, feedID string) (*FeedAnalytics, error) {
+	page := a.page.Context(ctx)
+
+	if err := Navigate(page, makeFeedAnalyticsURL(feedID)); err != nil {
+		return nil, errors.Wrap(err, "打开笔记数据页失败")
+	}
+	if err := CheckLoginWall(page); err != nil {
+		return nil, err
+	}
+
+	// 数据异步加载，指标出现前持续等待，超时后按没有数据处理
+	_ = page.Timeout(configs.GetElementTimeout()).Wait(rod.Eval(`() => Object.keys((` + feedMetricsJS + `)()).length > 0`))
+
+	result, err := page.Eval(`() => JSON.stringify((` + feedMetricsJS + `)())`)
+	if err != nil {
+		return nil, errors.Wrap(err, "读取笔记数据失败")
+	}
+
+	var metrics map[string]string
+	if err := json.Unmarshal([]byte(result.Value.String()), &metrics); err != nil {
+		return nil, errors.Wrap(err, "解析笔记数据失败")
+	}
+	if len(metrics) == 0 {
+		return nil, errors.Wrapf(ErrNotNoteOwner, "创作者中心没有笔记 %s 的数据", feedID)
+	}
+
+	return NewFeedAnalytics(metrics), nil
+}
@@ -0,0 +1,168 @@
+package xiaohongshu
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"time"
+
+	"github.com/go-rod/rod"
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+	"github.com/xpzouying/xiaohongshu-mcp/configs"
+)
+
+const (
+	// DefaultCommentsLimit 每次获取评论的默认数量
+	DefaultCommentsLimit = 20
+	// MaxCommentsLimit 每次获取评论的最大数量
+	MaxCommentsLimit = 100
+	// commentsIdleScrolls 连续多少次滚动没有加载出新评论时认为已到底
+	commentsIdleScrolls = 3
+)
+
+// FeedCommentUser 评论者信息
+type FeedCommentUser struct {
+	UserID   string `json:"userId"`
+	Nickname string `json:"nickname"`
+	Image    string `json:"image,omitempty"`
+}
+
+// FeedComment 笔记评论，SubComments 为页面已展开的回复
+type FeedComment struct {
+	ID              string          `json:"id"`
+	NoteID          string          `json:"noteId"`
+	Content         string          `json:"content"`
+	LikeCount       string          `json:"likeCount"`
+	CreateTime      int64           `json:"createTime"`
+	IPLocation      string          `json:"ipLocation,omitempty"`
+	UserInfo        FeedCommentUser `json:"userInfo"`
+	SubCommentCount string          `json:"subCommentCount,omitempty"`
+	SubComments     []FeedComment   `json:"subComments,omitempty"`
+}
+
+// CommentsAction 读取笔记评论
+type CommentsAction struct {
+	page *rod.Page
+}
+
+// NewCommentsAction 创建读取笔记评论的 action
+func NewCommentsAction(page *rod.Page) *CommentsAction {
+	return &CommentsAction{page: page}
+}
+
+// makeFeedCommentsURL 笔记详情页链接，评论在详情页中加载
+func makeFeedCommentsURL(feedID, xsecToken string) string {
+	return configs.SiteURL(fmt.Sprintf("/explore/%s?xsec_token=%s&xsec_source=pc_feed", feedID, url.QueryEscape(xsecToken)))
+}
+
+// ListComments 打开笔记详情页并滚动评论区，返回从 cursor 开始的最多 limit 条评论及下一页的游标。
+// 每次请求都会重新打开详情页并滚动到游标对应的位置；没有更多评论时下一页游标为空。
+// 滚动次数达到 -max-scrolls 仍未加载够时 truncated 为 true，返回已加载的部分
+func (c *CommentsAction) ListComments(ctx context.Context, feedID, xsecToken string, limit int, cursor Cursor) ([]FeedComment, string, bool, error) {
+	if limit <= 0 {
+		limit = DefaultCommentsLimit
+	}
+	limit = min(limit, MaxCommentsLimit)
+
+	page := c.page.Context(ctx)
+
+	if err := Navigate(page, makeFeedCommentsURL(feedID, xsecToken)); err != nil {
+		return nil, "", false, errors.Wrap(err, "打开笔记详情页失败")
+	}
+
+	// 多取一条，用于判断是否还有下一页
+	comments, truncated, err := scrollComments(page, feedID, cursor.Offset+limit+1)
+	if err != nil {
+		return nil, "", false, err
+	}
+
+	offset, end, next := commentsPage(feedID, comments, limit, cursor)
+	return comments[offset:end], next, truncated, nil
+}
+
+// scrollComments 滚动评论区，直到已加载的顶层评论不少于 want 条或没有更多评论，返回已加载的全部顶层评论。
+// 滚动次数达到 -max-scrolls 时停止，truncated 为 true
+func scrollComments(page *rod.Page, feedID string, want int) ([]FeedComment, bool, error) {
+	comments, hasMore, err := loadedComments(page, feedID)
+	if err != nil {
+		return nil, false, err
+	}
+
+	idle, scrolls, maxScrolls := 0, 0, configs.GetMaxScrolls()
+	for ; len(comments) < want && hasMore && idle < commentsIdleScrolls && scrolls < maxScrolls; scrolls++ {
+		// 评论区在详情弹层的滚动容器中，滚动窗口不会加载更多
+		if _, err := page.Eval(`() => {
+			const scroller = document.querySelector('.note-scroller') || document.scrollingElement;
+			scroller.scrollTo(0, scroller.scrollHeight);
+		}`); err != nil {
+			return nil, false, errors.Wrap(err, "滚动评论区失败")
+		}
+		time.Sleep(1500 * time.Millisecond)
+
+		more, moreHasMore, err := loadedComments(page, feedID)
+		if err != nil {
+			return nil, false, err
+		}
+		if len(more) > len(comments) {
+			idle = 0
+		} else {
+			idle++
+		}
+		comments, hasMore = more, moreHasMore
+	}
+
+	truncated := len(comments) < want && hasMore && idle < commentsIdleScrolls && scrolls >= maxScrolls
+	if truncated {
+		logrus.WithContext(page.GetContext()).Warnf("笔记 %s 评论区滚动 %d 次后停止，只加载了 %d 条评论", feedID, scrolls, len(comments))
+	} else {
+		logrus.WithContext(page.GetContext()).Infof("笔记 %s 已加载 %d 条评论", feedID, len(comments))
+	}
+	return comments, truncated, nil
+}
+
+// commentsPage 计算已加载的评论中从 cursor 开始、最多 limit 条的范围 [offset, end) 及下一页的游标，
+// 没有更多评论时下一页游标为空
+func commentsPage(feedID string, comments []FeedComment, limit int, cursor Cursor) (int, int, string) {
+	ids := make([]string, len(comments))
+	for i, comment := range comments {
+		ids[i] = comment.ID
+	}
+	offset := min(cursor.ResumeOffset(ids), len(comments))
+
+	end := min(offset+limit, len(comments))
+	next := ""
+	if end < len(comments) {
+		next = NextCursor(CommentsCursorScope(feedID), end, comments[end-1].ID)
+	}
+
+	return offset, end, next
+}
+
+// loadedComments 读取详情页当前已加载的评论，评论存放在 __INITIAL_STATE__.note.noteDetailMap[feedID].comments 中
+func loadedComments(page *rod.Page, feedID string) ([]FeedComment, bool, error) {
+	result, err := page.Eval(`(feedID) => {
+		const state = window.__INITIAL_STATE__ || {};
+		const map = state.note && state.note.noteDetailMap;
+		const details = (map && (map._value || map.value || map)) || {};
+		const comments = (details[feedID] && details[feedID].comments) || {};
+		return JSON.stringify({
+			list: Array.isArray(comments.list) ? comments.list : [],
+			hasMore: !!comments.hasMore,
+		});
+	}`, feedID)
+	if err != nil {
+		return nil, false, errors.Wrap(err, "获取评论失败")
+	}
+
+	var loaded struct {
+		List    []FeedComment `json:"list"`
+		HasMore bool          `json:"hasMore"`
+	}
+	if err := json.Unmarshal([]byte(result.Value.String()), &loaded); err != nil {
+		return nil, false, errors.Wrap(err, "解析评论失败")
+	}
+
+	return loaded.List, loaded.HasMore, nil
+}
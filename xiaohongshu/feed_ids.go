@@ -0,0 +1,69 @@
+package xiaohongshu
+
+import (
+	"net/url"
+	"regexp"
+	"strings"
+)
+
+// feedIDPattern 笔记ID由字母和数字组成，一般为 24 位十六进制
+var feedIDPattern = regexp.MustCompile(`^[0-9A-Za-z]+$`)
+
+// CanonicalFeedID 规范化笔记ID：去掉首尾空白，ID 被提取成详情页路径或链接时取出其中的笔记ID。
+// 无法得到合法的笔记ID时返回空字符串
+func CanonicalFeedID(id string) string {
+	id = strings.TrimSpace(id)
+	if strings.ContainsAny(id, "/?") {
+		u, err := url.Parse(id)
+		if err != nil {
+			return ""
+		}
+		id = ""
+		for _, prefix := range feedPathPrefixes {
+			if rest, ok := strings.CutPrefix(u.Path, prefix); ok {
+				id = strings.Trim(rest, "/")
+				break
+			}
+		}
+	}
+
+	if !feedIDPattern.MatchString(id) {
+		return ""
+	}
+	return id
+}
+
+// SanitizeFeeds 规范化列表、搜索结果中的笔记ID和 xsec_token，
+// 丢弃无法取得两者的条目（如广告、推荐词卡片），返回保留的笔记和丢弃的数量。
+// 下游获取详情、发表评论都需要这两个字段，缺少时调用必然失败
+func SanitizeFeeds(feeds []Feed) ([]Feed, int) {
+	kept := make([]Feed, 0, len(feeds))
+	for _, feed := range feeds {
+		feed.ID = CanonicalFeedID(feed.ID)
+		feed.XsecToken = strings.TrimSpace(feed.XsecToken)
+		if feed.ID == "" || feed.XsecToken == "" {
+			continue
+		}
+		kept = append(kept, feed)
+	}
+
+	return kept, len(feeds) - len(kept)
+}
+
+// DedupeFeeds 按笔记ID去重，保留每篇笔记第一次出现的位置和内容，返回去重后的笔记和丢弃的重复条目数。
+// 滚动加载时同一张卡片可能在前后两屏中都被读到；没有ID的条目原样保留，由 SanitizeFeeds 处理
+func DedupeFeeds(feeds []Feed) ([]Feed, int) {
+	kept := make([]Feed, 0, len(feeds))
+	seen := make(map[string]bool, len(feeds))
+	for _, feed := range feeds {
+		if feed.ID != "" {
+			if seen[feed.ID] {
+				continue
+			}
+			seen[feed.ID] = true
+		}
+		kept = append(kept, feed)
+	}
+
+	return kept, len(feeds) - len(kept)
+}
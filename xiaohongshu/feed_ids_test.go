@@ -0,0 +1,52 @@
+package xiaohongshu
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// loadScrollFrames 读取滚动加载时前后几屏读到的笔记卡片，按读到的顺序拼接
+func loadScrollFrames(t *testing.T) []Feed {
+	t.Helper()
+
+	data, err := os.ReadFile(filepath.Join("testdata", "feeds_scroll_frames.json"))
+	require.NoError(t, err)
+
+	var frames [][]Feed
+	require.NoError(t, json.Unmarshal(data, &frames))
+
+	var feeds []Feed
+	for _, frame := range frames {
+		feeds = append(feeds, frame...)
+	}
+	return feeds
+}
+
+func TestDedupeFeedsScrollFrames(t *testing.T) {
+	feeds, dropped := DedupeFeeds(loadScrollFrames(t))
+
+	assert.Equal(t, 2, dropped)
+	assert.Equal(t, []string{
+		"64f0a1b2c3d4e5f6a7b8c901",
+		"64f0a1b2c3d4e5f6a7b8c902",
+		"64f0a1b2c3d4e5f6a7b8c903",
+		"64f0a1b2c3d4e5f6a7b8c904",
+		"64f0a1b2c3d4e5f6a7b8c905",
+	}, FeedIDs(feeds), "保留第一次读到的顺序")
+
+	// 重复的卡片保留第一屏读到的内容
+	assert.Equal(t, "ABtoken2", feeds[1].XsecToken)
+	assert.Equal(t, "ABtoken3", feeds[2].XsecToken)
+}
+
+func TestDedupeFeedsKeepsEntriesWithoutID(t *testing.T) {
+	feeds, dropped := DedupeFeeds([]Feed{{XsecToken: "a"}, {XsecToken: "b"}, {ID: "1"}, {ID: "1"}})
+
+	assert.Equal(t, 1, dropped)
+	assert.Len(t, feeds, 3)
+}
@@ -0,0 +1,77 @@
+package xiaohongshu
+
+import (
+	"context"
+	"regexp"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// ErrShareLinkUnavailable 笔记详情页没有“复制链接”入口，无法获取分享短链
+var ErrShareLinkUnavailable = errors.New("笔记详情页没有复制链接入口")
+
+// shareLinkPattern 复制的分享文案中的链接，文案形如“标题 http://xhslink.com/a/xxx 复制本条信息，打开【小红书】App查看精彩内容！”
+var shareLinkPattern = regexp.MustCompile(`https?://[^\s，。！]+`)
+
+// ShareLink 打开笔记详情页，点击分享中的“复制链接”，返回复制内容中的分享链接。
+// 页面写入剪贴板前先拦截写入的内容，不依赖系统剪贴板，无头模式下也能获取
+func (f *FeedDetailAction) ShareLink(ctx context.Context, feedID, xsecToken string) (string, error) {
+	page := f.page.Context(ctx)
+
+	if err := Navigate(page, noteDetailPageURL(feedID, xsecToken)); err != nil {
+		return "", errors.Wrap(err, "打开笔记详情页失败")
+	}
+
+	// navigator.clipboard.writeText 和 document.execCommand('copy') 两种复制方式都记录下来
+	if _, err := page.Eval(`() => {
+		window.__xhsCopied = '';
+		if (navigator.clipboard) {
+			navigator.clipboard.writeText = text => {
+				window.__xhsCopied = String(text || '');
+				return Promise.resolve();
+			};
+		}
+		document.addEventListener('copy', () => {
+			const el = document.activeElement;
+			const text = el && typeof el.value === 'string'
+				? el.value.substring(el.selectionStart, el.selectionEnd)
+				: String(document.getSelection() || '');
+			if (text) {
+				window.__xhsCopied = text;
+			}
+		}, true);
+	}`); err != nil {
+		return "", errors.Wrap(err, "准备读取复制内容失败")
+	}
+
+	share, err := findElement(page, ".engage-bar-container .share-wrapper, .interactions .share-wrapper")
+	if err != nil {
+		return "", ErrShareLinkUnavailable
+	}
+	if err := humanClick(share); err != nil {
+		return "", errors.Wrap(err, "打开分享菜单失败")
+	}
+	time.Sleep(500 * time.Millisecond)
+
+	copyButton, err := findElementR(page, "div, span", "^复制链接$")
+	if err != nil {
+		return "", ErrShareLinkUnavailable
+	}
+	if err := humanClick(copyButton); err != nil {
+		return "", errors.Wrap(err, "复制链接失败")
+	}
+	time.Sleep(500 * time.Millisecond)
+
+	result, err := page.Eval(`() => window.__xhsCopied || ''`)
+	if err != nil {
+		return "", errors.Wrap(err, "读取复制内容失败")
+	}
+
+	link := shareLinkPattern.FindString(result.Value.String())
+	if link == "" {
+		return "", errors.Errorf("复制内容中没有链接: %q", result.Value.String())
+	}
+
+	return link, nil
+}
@@ -0,0 +1,107 @@
+package xiaohongshu
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// initialStatePattern 页面中 window.__INITIAL_STATE__ 的赋值语句
+var initialStatePattern = regexp.MustCompile(`(?s)window\.__INITIAL_STATE__=(\{.*?\})</script>`)
+
+// loadStateFeeds 从保存的页面中取出 __INITIAL_STATE__ 下 section.feeds 中的笔记，
+// 与列表、搜索页面读取的位置相同。页面状态中的 undefined 按 null 解析
+func loadStateFeeds(t *testing.T, name, section string) []Feed {
+	t.Helper()
+
+	data, err := os.ReadFile(filepath.Join("testdata", name))
+	require.NoError(t, err)
+
+	match := initialStatePattern.FindSubmatch(data)
+	require.NotNil(t, match, "页面中没有 __INITIAL_STATE__")
+
+	var state map[string]struct {
+		Feeds struct {
+			Value []Feed `json:"_value"`
+		} `json:"feeds"`
+	}
+	require.NoError(t, json.Unmarshal([]byte(strings.ReplaceAll(string(match[1]), ":undefined", ":null")), &state))
+	return state[section].Feeds.Value
+}
+
+func TestSanitizeFeedsFixtures(t *testing.T) {
+	tests := []struct {
+		name        string
+		file        string
+		section     string
+		wantIDs     []string
+		wantTokens  []string
+		wantDropped int
+	}{
+		{
+			name:    "feeds list",
+			file:    "feeds_page.html",
+			section: "feed",
+			wantIDs: []string{
+				"64f0a1b2c3d4e5f6a7b8c901",
+				"64f0a1b2c3d4e5f6a7b8c902",
+				"64f0a1b2c3d4e5f6a7b8c903",
+			},
+			wantTokens:  []string{"ABfeed1", "ABfeed2", "ABfeed3"},
+			wantDropped: 2,
+		},
+		{
+			name:    "search",
+			file:    "search_page.html",
+			section: "search",
+			wantIDs: []string{
+				"65a0b1c2d3e4f5a6b7c8d901",
+				"65a0b1c2d3e4f5a6b7c8d902",
+			},
+			wantTokens:  []string{"ABsearch1", "ABsearch2"},
+			wantDropped: 3,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			feeds := loadStateFeeds(t, tt.file, tt.section)
+			require.NotEmpty(t, feeds)
+
+			kept, dropped := SanitizeFeeds(feeds)
+			assert.Equal(t, tt.wantDropped, dropped)
+			assert.Equal(t, tt.wantIDs, FeedIDs(kept))
+
+			tokens := make([]string, len(kept))
+			for i, feed := range kept {
+				tokens[i] = feed.XsecToken
+			}
+			assert.Equal(t, tt.wantTokens, tokens)
+		})
+	}
+}
+
+func TestCanonicalFeedID(t *testing.T) {
+	tests := []struct {
+		id   string
+		want string
+	}{
+		{"64f0a1b2c3d4e5f6a7b8c901", "64f0a1b2c3d4e5f6a7b8c901"},
+		{"  64f0a1b2c3d4e5f6a7b8c901\n", "64f0a1b2c3d4e5f6a7b8c901"},
+		{"/explore/64f0a1b2c3d4e5f6a7b8c901?xsec_token=abc", "64f0a1b2c3d4e5f6a7b8c901"},
+		{"https://www.xiaohongshu.com/discovery/item/64f0a1b2c3d4e5f6a7b8c901/", "64f0a1b2c3d4e5f6a7b8c901"},
+		{"/user/profile/5a1b2c3d4e5f6a7b8c9d0e1f", ""},
+		{"hot_query_1", ""},
+		{"", ""},
+	}
+
+	for _, tt := range tests {
+		assert.Equal(t, tt.want, CanonicalFeedID(tt.id), tt.id)
+	}
+}
@@ -0,0 +1,111 @@
+package xiaohongshu
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/go-rod/rod"
+	"github.com/go-rod/rod/lib/proto"
+	"github.com/pkg/errors"
+)
+
+const (
+	// MinScreenshotWidth 笔记截图的最小视口宽度，再窄时详情页会切换为移动端布局
+	MinScreenshotWidth = 320
+	// MaxScreenshotWidth 笔记截图的最大视口宽度
+	MaxScreenshotWidth = 1920
+	// MaxScreenshotHeight 笔记截图的最大高度，超出部分被裁掉，避免长笔记生成过大的图片
+	MaxScreenshotHeight = 8000
+)
+
+const (
+	// ScreenshotScopeNote 只截取笔记区域
+	ScreenshotScopeNote = "note"
+	// ScreenshotScopePage 截取整个页面
+	ScreenshotScopePage = "page"
+)
+
+// FeedScreenshot 笔记截图
+type FeedScreenshot struct {
+	PNG       []byte
+	Width     int
+	Height    int
+	Truncated bool // 内容高度超过 MaxScreenshotHeight，只保留了上半部分
+}
+
+// ScreenshotFeed 截取已打开的笔记详情页。scope 为 ScreenshotScopeNote 时只截取笔记区域，
+// 页面上找不到笔记区域时返回错误；为 ScreenshotScopePage 时截取整个页面（包括视口以外的部分）
+func ScreenshotFeed(page *rod.Page, scope string) (*FeedScreenshot, error) {
+	result, err := page.Eval(`(scope) => {
+		const doc = document.documentElement;
+		if (scope === 'page') {
+			return JSON.stringify({ found: true, x: 0, y: 0, width: doc.scrollWidth, height: doc.scrollHeight });
+		}
+		const el = document.querySelector('#noteContainer, .note-container');
+		if (!el) {
+			return JSON.stringify({ found: false });
+		}
+		const rect = el.getBoundingClientRect();
+		return JSON.stringify({
+			found: true,
+			x: rect.left + window.scrollX,
+			y: rect.top + window.scrollY,
+			width: rect.width,
+			height: rect.height,
+		});
+	}`, scope)
+	if err != nil {
+		return nil, errors.Wrap(err, "获取截图区域失败")
+	}
+
+	var area struct {
+		Found  bool    `json:"found"`
+		X      float64 `json:"x"`
+		Y      float64 `json:"y"`
+		Width  float64 `json:"width"`
+		Height float64 `json:"height"`
+	}
+	if err := json.Unmarshal([]byte(result.Value.String()), &area); err != nil {
+		return nil, errors.Wrap(err, "解析截图区域失败")
+	}
+	if !area.Found || area.Width < 1 || area.Height < 1 {
+		return nil, errors.New("页面上没有找到笔记区域")
+	}
+
+	shot := &FeedScreenshot{
+		Width:  int(min(area.Width, MaxScreenshotWidth)),
+		Height: int(area.Height),
+	}
+	if shot.Height > MaxScreenshotHeight {
+		shot.Height = MaxScreenshotHeight
+		shot.Truncated = true
+	}
+
+	shot.PNG, err = page.Screenshot(false, &proto.PageCaptureScreenshot{
+		Format: proto.PageCaptureScreenshotFormatPng,
+		Clip: &proto.PageViewport{
+			X:      area.X,
+			Y:      area.Y,
+			Width:  float64(shot.Width),
+			Height: float64(shot.Height),
+			Scale:  1,
+		},
+		CaptureBeyondViewport: true,
+	})
+	if err != nil {
+		return nil, errors.Wrap(err, "截图失败")
+	}
+
+	return shot, nil
+}
+
+// ValidateScreenshotOptions 校验截图的视口宽度和范围，width 为 0 表示使用默认视口宽度
+func ValidateScreenshotOptions(width int, scope string) error {
+	if width != 0 && (width < MinScreenshotWidth || width > MaxScreenshotWidth) {
+		return fmt.Errorf("width 需要在 %d 到 %d 之间", MinScreenshotWidth, MaxScreenshotWidth)
+	}
+	if scope != "" && scope != ScreenshotScopeNote && scope != ScreenshotScopePage {
+		return fmt.Errorf("scope 只支持 %s 或 %s", ScreenshotScopeNote, ScreenshotScopePage)
+	}
+	return nil
+}
@@ -0,0 +1,76 @@
+package xiaohongshu
+
+import (
+	"encoding/json"
+	"net/url"
+	"strings"
+
+	"github.com/go-rod/rod"
+	"github.com/pkg/errors"
+	"github.com/xpzouying/xiaohongshu-mcp/configs"
+)
+
+// FeedTag 笔记的话题标签
+type FeedTag struct {
+	ID   string `json:"id,omitempty"` // 话题ID，只能从正文链接中读到文字时为空
+	Name string `json:"name"`         // 话题名称，不含开头的 #
+	URL  string `json:"url"`          // 话题页链接，没有话题ID时为按话题名称搜索的链接
+}
+
+// NewFeedTag 根据话题ID和名称构造话题标签，名称开头的 # 会被去掉
+func NewFeedTag(id, name string) FeedTag {
+	name = strings.TrimSpace(strings.TrimPrefix(strings.TrimSpace(name), "#"))
+	tag := FeedTag{ID: id, Name: name}
+	if id != "" {
+		tag.URL = configs.SiteURL("/page/topics/" + url.PathEscape(id))
+	} else {
+		tag.URL = configs.SiteURL("/search_result?keyword=" + url.QueryEscape("#"+name) + "&source=tag")
+	}
+	return tag
+}
+
+// GetFeedTags 从已打开的笔记详情页读取笔记的话题标签，按在笔记中出现的顺序返回。
+// 优先读取初始状态中的 tagList，没有时再读取正文中的 #话题 链接；笔记没有话题时返回空列表
+func GetFeedTags(page *rod.Page, feedID string) ([]FeedTag, error) {
+	result, err := page.Eval(`(feedID) => {
+		const state = window.__INITIAL_STATE__ || {};
+		const map = state.note && state.note.noteDetailMap;
+		const details = (map && (map._value || map.value || map)) || {};
+		const note = (details[feedID] && details[feedID].note) || {};
+		const tags = (note.tagList || [])
+			.filter(t => t && t.name && (!t.type || t.type === 'topic'))
+			.map(t => ({ id: t.id || '', name: t.name }));
+		if (tags.length > 0) {
+			return JSON.stringify(tags);
+		}
+		const links = document.querySelectorAll('#detail-desc a.tag, .note-content a.tag, #hash-tag');
+		return JSON.stringify(Array.from(links)
+			.map(a => ({ id: '', name: (a.innerText || '').trim() }))
+			.filter(t => t.name));
+	}`, feedID)
+	if err != nil {
+		return nil, errors.Wrap(err, "读取话题标签失败")
+	}
+
+	var raw []FeedTag
+	if err := json.Unmarshal([]byte(result.Value.String()), &raw); err != nil {
+		return nil, errors.Wrap(err, "解析话题标签失败")
+	}
+
+	return dedupeFeedTags(raw), nil
+}
+
+// dedupeFeedTags 规范化话题名称并按名称去重，保留第一次出现的顺序
+func dedupeFeedTags(raw []FeedTag) []FeedTag {
+	tags := make([]FeedTag, 0, len(raw))
+	seen := make(map[string]bool, len(raw))
+	for _, t := range raw {
+		tag := NewFeedTag(t.ID, t.Name)
+		if tag.Name == "" || seen[tag.Name] {
+			continue
+		}
+		seen[tag.Name] = true
+		tags = append(tags, tag)
+	}
+	return tags
+}
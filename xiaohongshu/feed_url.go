@@ -0,0 +1,88 @@
+package xiaohongshu
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/xpzouying/xiaohongshu-mcp/configs"
+)
+
+// feedPathPrefixes 笔记详情页的路径前缀，短链一般会跳转到 /discovery/item/
+var feedPathPrefixes = []string{"/explore/", "/discovery/item/"}
+
+// FeedURL 笔记的网页链接，带有 xsec_token，可以直接在浏览器中打开
+func FeedURL(feedID, xsecToken string) string {
+	return configs.SiteURL(fmt.Sprintf("/explore/%s?xsec_token=%s&xsec_source=pc_share", feedID, url.QueryEscape(xsecToken)))
+}
+
+// ResolveFeedURL 解析笔记分享链接，返回笔记ID和 xsec_token。
+// 支持 xiaohongshu.com/explore/... 完整链接以及 xhslink.com 短链（会跟随跳转）。
+func ResolveFeedURL(ctx context.Context, rawURL string) (feedID, xsecToken string, err error) {
+	u, err := url.Parse(strings.TrimSpace(rawURL))
+	if err != nil || u.Host == "" {
+		return "", "", errors.Errorf("无效的笔记链接: %s", rawURL)
+	}
+
+	if isShortLinkHost(u.Hostname()) {
+		if u, err = followShortLink(ctx, u.String()); err != nil {
+			return "", "", err
+		}
+	}
+
+	return parseFeedURL(u)
+}
+
+// isShortLinkHost 判断是否为小红书分享短链的域名 xhslink.com
+func isShortLinkHost(host string) bool {
+	host = strings.ToLower(host)
+	return host == "xhslink.com" || strings.HasSuffix(host, ".xhslink.com")
+}
+
+// followShortLink 请求短链并返回跳转后的最终地址
+func followShortLink(ctx context.Context, shortURL string) (*url.URL, error) {
+	client := &http.Client{Timeout: 15 * time.Second}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, shortURL, nil)
+	if err != nil {
+		return nil, errors.Wrap(err, "创建短链请求失败")
+	}
+	req.Header.Set("User-Agent", "Mozilla/5.0 (Macintosh; Intel Mac OS X 10_15_7) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/124.0.0.0 Safari/537.36")
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, errors.Wrapf(err, "解析短链失败: %s", shortURL)
+	}
+	defer resp.Body.Close()
+
+	return resp.Request.URL, nil
+}
+
+// parseFeedURL 从笔记详情页地址中提取笔记ID和 xsec_token
+func parseFeedURL(u *url.URL) (string, string, error) {
+	if !configs.IsSiteHost(u.Hostname()) {
+		return "", "", errors.Errorf("链接没有指向小红书笔记: %s", u.String())
+	}
+
+	var feedID string
+	for _, prefix := range feedPathPrefixes {
+		if strings.HasPrefix(u.Path, prefix) {
+			feedID = strings.Trim(strings.TrimPrefix(u.Path, prefix), "/")
+			break
+		}
+	}
+	if feedID == "" || strings.Contains(feedID, "/") {
+		return "", "", errors.Errorf("链接没有指向小红书笔记，应形如 https://www.xiaohongshu.com/explore/<feed_id>?xsec_token=...: %s", u.String())
+	}
+
+	xsecToken := u.Query().Get("xsec_token")
+	if xsecToken == "" {
+		return "", "", errors.Errorf("链接中缺少 xsec_token，请使用 App 或网页的“分享”功能复制完整链接: %s", u.String())
+	}
+
+	return feedID, xsecToken, nil
+}
@@ -0,0 +1,71 @@
+package xiaohongshu
+
+import (
+	"encoding/json"
+	"strings"
+
+	"github.com/go-rod/rod"
+	"github.com/pkg/errors"
+)
+
+// FeedVideo 视频笔记的播放地址
+type FeedVideo struct {
+	URL         string   `json:"url,omitempty"`          // 可直接下载的视频地址（含音频），带签名参数，有效期有限
+	BackupURLs  []string `json:"backup_urls,omitempty"`  // 备用地址，主地址失效时使用
+	ManifestURL string   `json:"manifest_url,omitempty"` // 分段流式播放时的清单地址（DASH .mpd 或 HLS .m3u8）
+}
+
+// GetFeedVideo 从已打开的笔记详情页读取视频地址，图文笔记返回 nil。
+// 优先使用页面初始状态中的视频流地址；页面以分段方式播放时返回加载过的清单地址
+func GetFeedVideo(page *rod.Page, feedID string) (*FeedVideo, error) {
+	result, err := page.Eval(`(feedID) => {
+		const state = window.__INITIAL_STATE__ || {};
+		const map = state.note && state.note.noteDetailMap;
+		const details = (map && (map._value || map.value || map)) || {};
+		const note = (details[feedID] && details[feedID].note) || {};
+		const stream = (note.video && note.video.media && note.video.media.stream) || {};
+		const playable = [].concat(stream.h264 || [], stream.h265 || [], stream.av1 || []).find(s => s && s.masterUrl) || {};
+		const video = document.querySelector('.note-container video, .player-container video');
+		const manifest = performance.getEntriesByType('resource').map(e => e.name).find(name => /\.(mpd|m3u8)(\?|$)/.test(name));
+		return JSON.stringify({
+			type: note.type || '',
+			url: playable.masterUrl || '',
+			backupUrls: playable.backupUrls || [],
+			src: video ? (video.currentSrc || video.src || '') : '',
+			manifest: manifest || '',
+		});
+	}`, feedID)
+	if err != nil {
+		return nil, errors.Wrap(err, "读取视频地址失败")
+	}
+
+	var info struct {
+		Type       string   `json:"type"`
+		URL        string   `json:"url"`
+		BackupURLs []string `json:"backupUrls"`
+		Src        string   `json:"src"`
+		Manifest   string   `json:"manifest"`
+	}
+	if err := json.Unmarshal([]byte(result.Value.String()), &info); err != nil {
+		return nil, errors.Wrap(err, "解析视频地址失败")
+	}
+
+	if info.Type != "video" && info.URL == "" && info.Src == "" {
+		return nil, nil
+	}
+
+	video := &FeedVideo{
+		URL:         info.URL,
+		BackupURLs:  info.BackupURLs,
+		ManifestURL: info.Manifest,
+	}
+	// 初始状态中没有视频流时，使用播放器的地址；blob: 地址是分段播放生成的，无法下载
+	if video.URL == "" && info.Src != "" && !strings.HasPrefix(info.Src, "blob:") {
+		video.URL = info.Src
+	}
+	if video.URL == "" && video.ManifestURL == "" {
+		return nil, errors.New("视频笔记没有可用的播放地址")
+	}
+
+	return video, nil
+}
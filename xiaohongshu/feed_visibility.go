@@ -0,0 +1,43 @@
+package xiaohongshu
+
+import (
+	"context"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+)
+
+// ErrVisibilityUnsupported 笔记编辑页没有可见范围设置，该类型的笔记不支持修改可见范围
+var ErrVisibilityUnsupported = errors.New("该笔记不支持修改可见范围")
+
+// SetVisibility 打开笔记编辑页，只修改笔记的可见范围后保存，其他内容保持不变。
+// 可用于把笔记临时设为仅自己可见，而不是删除笔记丢失数据
+func (e *EditFeedAction) SetVisibility(ctx context.Context, feedID string, visibility Visibility) error {
+	page := e.page.Context(ctx)
+
+	if err := Navigate(page, makeEditFeedURL(feedID)); err != nil {
+		return errors.Wrap(err, "打开笔记编辑页失败")
+	}
+
+	trigger, err := findVisibilityControl(page)
+	if err != nil {
+		return ErrVisibilityUnsupported
+	}
+	if err := setVisibility(page, trigger, visibility); err != nil {
+		return err
+	}
+
+	submitButton, err := findElement(page, "div.submit div.d-button-content")
+	if err != nil {
+		return errors.Wrap(err, "没有找到保存按钮")
+	}
+	if err := humanClick(submitButton); err != nil {
+		return errors.Wrap(err, "保存笔记失败")
+	}
+	time.Sleep(3 * time.Second)
+
+	logrus.WithContext(ctx).Infof("笔记可见范围修改完成: %s -> %s", feedID, visibility)
+
+	return nil
+}
@@ -0,0 +1,55 @@
+package xiaohongshu
+
+import "unicode"
+
+// splitGraphemes 把文本切分为用户看到的单个字符（字素），逐字输入时按字素输入，
+// 避免把带肤色、变体选择符、零宽连接符的 emoji 或国旗拆开后输入成几个独立的符号。
+// 只处理输入正文时常见的组合方式，不是完整的 Unicode 字素切分
+func splitGraphemes(text string) []string {
+	runes := []rune(text)
+	var graphemes []string
+	for i := 0; i < len(runes); {
+		j := i + 1
+		// 两个区域指示符组成一面国旗
+		if isRegionalIndicator(runes[i]) && j < len(runes) && isRegionalIndicator(runes[j]) {
+			j++
+		}
+		for j < len(runes) {
+			if isGraphemeExtend(runes[j]) {
+				j++
+				continue
+			}
+			if runes[j] == zeroWidthJoiner && j+1 < len(runes) {
+				j += 2
+				continue
+			}
+			break
+		}
+		graphemes = append(graphemes, string(runes[i:j]))
+		i = j
+	}
+	return graphemes
+}
+
+// zeroWidthJoiner 零宽连接符，用于把多个 emoji 组合为一个，如 👨‍👩‍👧
+const zeroWidthJoiner = '\u200d'
+
+// isGraphemeExtend 判断字符是否附着在前一个字符上：组合符号、变体选择符、肤色修饰符、键帽符号和标签字符
+func isGraphemeExtend(r rune) bool {
+	switch {
+	case unicode.Is(unicode.Mn, r), unicode.Is(unicode.Me, r):
+		return true
+	case r >= '\ufe00' && r <= '\ufe0f':
+		return true
+	case r >= 0x1f3fb && r <= 0x1f3ff:
+		return true
+	case r >= 0xe0020 && r <= 0xe007f:
+		return true
+	}
+	return false
+}
+
+// isRegionalIndicator 判断是否为区域指示符，两个一组表示国旗
+func isRegionalIndicator(r rune) bool {
+	return r >= 0x1f1e6 && r <= 0x1f1ff
+}
@@ -0,0 +1,131 @@
+package xiaohongshu
+
+import (
+	"context"
+	"math/rand/v2"
+	"strings"
+	"time"
+
+	"github.com/go-rod/rod"
+	"github.com/go-rod/rod/lib/input"
+	"github.com/pkg/errors"
+	"github.com/xpzouying/xiaohongshu-mcp/configs"
+)
+
+// 模拟人工操作
+//
+// -humanize 开启后，点击、悬停前随机停顿，文本逐字输入，降低被识别为自动化操作的概率。
+// 停顿期间响应 context 取消。未开启时直接操作，不增加耗时。
+
+// typingDelayDivisor 逐字输入时每个字符的停顿为点击停顿的几分之一，避免长正文输入过慢
+const typingDelayDivisor = 5
+
+// humanPause 随机停顿 [min, max) 区间内的一段时间，ctx 结束时提前返回错误
+func humanPause(ctx context.Context, minDelay, maxDelay time.Duration) error {
+	delay := minDelay
+	if maxDelay > minDelay {
+		delay += rand.N(maxDelay - minDelay)
+	}
+	if delay <= 0 {
+		return ctx.Err()
+	}
+
+	timer := time.NewTimer(delay)
+	defer timer.Stop()
+
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
+
+// humanDelay 未开启 -humanize 时不停顿，否则在配置范围内随机停顿
+func humanDelay(ctx context.Context) error {
+	if !configs.IsHumanize() {
+		return nil
+	}
+
+	minDelay, maxDelay := configs.GetHumanizeDelay()
+	return humanPause(ctx, minDelay, maxDelay)
+}
+
+// typeLine 在当前焦点处输入一行文本。未开启 -humanize 时一次性输入；
+// 开启时按字素逐个输入并随机停顿，emoji 等由多个码点组成的字符作为整体输入
+func typeLine(ctx context.Context, page *rod.Page, line string) error {
+	if line == "" {
+		return nil
+	}
+	if !configs.IsHumanize() {
+		return page.InsertText(line)
+	}
+
+	minDelay, maxDelay := configs.GetHumanizeDelay()
+	for _, grapheme := range splitGraphemes(line) {
+		if err := page.InsertText(grapheme); err != nil {
+			return err
+		}
+		if err := humanPause(ctx, minDelay/typingDelayDivisor, maxDelay/typingDelayDivisor); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// humanClick 随机停顿后左键单击元素
+func humanClick(el *rod.Element) error {
+	if err := humanDelay(el.GetContext()); err != nil {
+		return err
+	}
+	return el.Click("left", 1)
+}
+
+// humanHover 随机停顿后将鼠标移到元素上
+func humanHover(el *rod.Element) error {
+	if err := humanDelay(el.GetContext()); err != nil {
+		return err
+	}
+	return el.Hover()
+}
+
+// splitInputLines 把要输入的文本按行切分，兼容 \r\n 换行，空行保留为空字符串，输入时成为空段落
+func splitInputLines(text string) []string {
+	return strings.Split(strings.ReplaceAll(text, "\r\n", "\n"), "\n")
+}
+
+// humanInput 向元素输入文本。开启 -humanize 时逐字输入，每个字符之间随机停顿。
+// 多行文本逐行输入，行之间按回车键，富文本编辑器中会生成新的段落，直接插入换行符会被编辑器丢弃。
+// 输入完成后与 Element.Input 一样触发 input、change 事件
+func humanInput(el *rod.Element, text string) error {
+	lines := splitInputLines(text)
+	if !configs.IsHumanize() && len(lines) == 1 {
+		return el.Input(lines[0])
+	}
+
+	ctx := el.GetContext()
+	if err := humanDelay(ctx); err != nil {
+		return err
+	}
+	if err := el.Focus(); err != nil {
+		return errors.Wrap(err, "聚焦输入框失败")
+	}
+
+	page := el.Page().Context(ctx)
+	for i, line := range lines {
+		if i > 0 {
+			if err := page.Keyboard.Type(input.Enter); err != nil {
+				return errors.Wrap(err, "输入换行失败")
+			}
+		}
+		if err := typeLine(ctx, page, line); err != nil {
+			return err
+		}
+	}
+
+	_, err := el.Eval(`() => {
+		this.dispatchEvent(new Event('input', { bubbles: true }))
+		this.dispatchEvent(new Event('change', { bubbles: true }))
+	}`)
+	return err
+}
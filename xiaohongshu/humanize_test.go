@@ -0,0 +1,58 @@
+package xiaohongshu
+
+import (
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSplitGraphemes(t *testing.T) {
+	tests := []struct {
+		name string
+		text string
+		want []string
+	}{
+		{"empty", "", nil},
+		{"ascii", "ab", []string{"a", "b"}},
+		{"cjk", "小红书", []string{"小", "红", "书"}},
+		{"skin tone", "👍🏽!", []string{"👍🏽", "!"}},
+		{"variation selector", "❤️x", []string{"❤️", "x"}},
+		{"zwj family", "👨‍👩‍👧a", []string{"👨‍👩‍👧", "a"}},
+		{"flags", "🇨🇳🇯🇵", []string{"🇨🇳", "🇯🇵"}},
+		{"keycap", "1️⃣2", []string{"1️⃣", "2"}},
+		{"combining mark", "é!", []string{"é", "!"}},
+		{"trailing zwj", "a‍", []string{"a", "‍"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := splitGraphemes(tt.text)
+			assert.Equal(t, tt.want, got)
+			assert.Equal(t, tt.text, strings.Join(got, ""))
+		})
+	}
+}
+
+func TestSplitInputLines(t *testing.T) {
+	data, err := os.ReadFile("testdata/note_body.txt")
+	require.NoError(t, err)
+
+	lines := splitInputLines(string(data))
+	assert.Equal(t, []string{
+		"周末去了趟杭州 🌸",
+		"",
+		"第一站：西湖 👨‍👩‍👧",
+		"第二站：灵隐寺 🇨🇳",
+		"",
+		"",
+		"#旅行 #杭州",
+		"",
+	}, lines)
+
+	for _, line := range lines {
+		assert.NotContains(t, line, "\r")
+	}
+	assert.Equal(t, []string{"单行"}, splitInputLines("单行"))
+}
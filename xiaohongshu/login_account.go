@@ -0,0 +1,59 @@
+package xiaohongshu
+
+import (
+	"encoding/json"
+
+	"github.com/go-rod/rod"
+	"github.com/pkg/errors"
+)
+
+// LoginAccount 当前登录的账号
+type LoginAccount struct {
+	UserID   string `json:"user_id"`
+	Nickname string `json:"nickname,omitempty"`
+	Avatar   string `json:"avatar,omitempty"`
+}
+
+// CurrentAccount 从已登录的小红书页面读取当前账号的 user_id、昵称和头像。
+// 优先使用页面初始状态中的用户信息，缺失时回退到侧边栏“我”的链接和头像
+func CurrentAccount(page *rod.Page) (*LoginAccount, error) {
+	result, err := page.Eval(`() => {
+		const state = window.__INITIAL_STATE__ || {};
+		const user = state.user && state.user.userInfo;
+		const info = (user && (user._value || user.value || user)) || {};
+		const link = document.querySelector(".main-container .user a[href*='/user/profile/']");
+		const avatar = link && link.querySelector('img');
+		return JSON.stringify({
+			userId: info.userId || info.user_id || '',
+			nickname: info.nickname || '',
+			avatar: info.imageb || info.images || info.avatar || (avatar ? avatar.src : ''),
+			href: link ? link.href : '',
+		});
+	}`)
+	if err != nil {
+		return nil, errors.Wrap(err, "读取当前账号信息失败")
+	}
+
+	var info struct {
+		UserID   string `json:"userId"`
+		Nickname string `json:"nickname"`
+		Avatar   string `json:"avatar"`
+		Href     string `json:"href"`
+	}
+	if err := json.Unmarshal([]byte(result.Value.String()), &info); err != nil {
+		return nil, errors.Wrap(err, "解析当前账号信息失败")
+	}
+
+	if info.UserID == "" && info.Href != "" {
+		info.UserID, _ = userIDFromProfileURL(info.Href)
+	}
+	if info.UserID == "" {
+		return nil, errors.New("页面上没有当前账号信息")
+	}
+
+	return &LoginAccount{
+		UserID:   info.UserID,
+		Nickname: info.Nickname,
+		Avatar:   info.Avatar,
+	}, nil
+}
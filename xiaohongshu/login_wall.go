@@ -0,0 +1,39 @@
+package xiaohongshu
+
+import (
+	"net/url"
+	"strings"
+
+	"github.com/go-rod/rod"
+)
+
+// CheckLoginWall 检查页面是否被重定向到登录页或弹出了登录框，是则返回 ErrNotLoggedIn。
+// 登录过期时读取类页面往往不会报错，只是没有数据，需要在导航后显式检查才能和“没有数据”区分开。
+func CheckLoginWall(page *rod.Page) error {
+	if info, err := page.Info(); err == nil && isLoginURL(info.URL) {
+		return ErrNotLoggedIn
+	}
+
+	// 登录框以遮罩层形式覆盖在页面上，只判断可见的登录框
+	result, err := page.Eval(`() => Array.from(document.querySelectorAll('.login-container, .login-modal, .login-box'))
+		.some(el => el.offsetWidth > 0 && el.offsetHeight > 0 && getComputedStyle(el).visibility !== 'hidden')`)
+	if err != nil {
+		// 检查失败时不确定是否登录，交由调用方按原结果处理
+		return nil
+	}
+	if result.Value.Bool() {
+		return ErrNotLoggedIn
+	}
+
+	return nil
+}
+
+// isLoginURL 判断是否为登录页，未登录访问需要登录的页面时会跳转到 /login 或 website-login 页面
+func isLoginURL(rawURL string) bool {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return false
+	}
+
+	return strings.HasPrefix(u.Path, "/login") || strings.Contains(u.Path, "website-login")
+}
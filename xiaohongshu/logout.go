@@ -0,0 +1,78 @@
+package xiaohongshu
+
+import (
+	"context"
+	"time"
+
+	"github.com/go-rod/rod"
+	"github.com/pkg/errors"
+	"github.com/xpzouying/xiaohongshu-mcp/configs"
+)
+
+// ErrLogoutUnconfirmed 点击退出登录后重新打开首页，页面仍处于登录状态
+var ErrLogoutUnconfirmed = errors.New("退出登录未生效，页面仍处于登录状态")
+
+// profileLinkSelector 侧边栏“我”的链接，只有登录后才会出现，链接指向当前账号的主页
+const profileLinkSelector = ".main-container .user a[href*='/user/profile/']"
+
+// LogoutAction 退出登录
+type LogoutAction struct {
+	page *rod.Page
+}
+
+// NewLogoutAction 创建退出登录 action
+func NewLogoutAction(page *rod.Page) *LogoutAction {
+	return &LogoutAction{page: page}
+}
+
+// Logout 通过侧边栏“更多”菜单中的“退出登录”退出当前账号，然后重新打开首页确认已回到未登录状态。
+// 本来就没有登录时不做操作，返回 alreadyLoggedOut 为 true
+func (l *LogoutAction) Logout(ctx context.Context) (alreadyLoggedOut bool, err error) {
+	page := l.page.Context(ctx)
+
+	if err := Navigate(page, configs.SiteURL("/explore")); err != nil {
+		return false, errors.Wrap(err, "打开首页失败")
+	}
+	if _, err := page.Timeout(5 * time.Second).Element(profileLinkSelector); err != nil {
+		return true, nil
+	}
+
+	more, err := findElementR(page, ".side-bar div, .side-bar span", "^更多$")
+	if err != nil {
+		return false, errors.Wrap(err, "没有找到“更多”菜单")
+	}
+	if err := humanClick(more); err != nil {
+		return false, errors.Wrap(err, "打开“更多”菜单失败")
+	}
+	time.Sleep(500 * time.Millisecond)
+
+	logout, err := findElementR(page, "div, span, button", "^退出登录$")
+	if err != nil {
+		return false, errors.Wrap(err, "没有找到退出登录入口")
+	}
+	if err := humanClick(logout); err != nil {
+		return false, errors.Wrap(err, "点击退出登录失败")
+	}
+	time.Sleep(time.Second)
+
+	// 部分版本会弹出确认框
+	if confirm, err := page.Timeout(2*time.Second).ElementR(".d-modal button, .reds-modal button, .d-modal div, .reds-modal div", "^(确定|确认|退出|退出登录)$"); err == nil {
+		if err := humanClick(confirm); err != nil {
+			return false, errors.Wrap(err, "确认退出登录失败")
+		}
+		time.Sleep(time.Second)
+	}
+
+	if err := Navigate(page, configs.SiteURL("/explore")); err != nil {
+		return false, errors.Wrap(err, "退出后重新打开首页失败")
+	}
+	loggedIn, _, err := page.Has(profileLinkSelector)
+	if err != nil {
+		return false, errors.Wrap(err, "检查登录状态失败")
+	}
+	if loggedIn {
+		return false, ErrLogoutUnconfirmed
+	}
+
+	return false, nil
+}
@@ -0,0 +1,65 @@
+package xiaohongshu
+
+import (
+	"context"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/xpzouying/xiaohongshu-mcp/configs"
+)
+
+// ProfileTotals 账号互动数据汇总
+type ProfileTotals struct {
+	Follows     int `json:"follows"`     // 关注数
+	Fans        int `json:"fans"`        // 粉丝数
+	Interaction int `json:"interaction"` // 获赞与收藏数
+	NoteCount   int `json:"noteCount"`   // 主页已加载的笔记数
+	NoteLikes   int `json:"noteLikes"`   // 已加载笔记的点赞数之和
+}
+
+// MyProfile 获取当前登录账号的主页信息，返回主页数据和账号的 user_id。
+// 不需要 xsec_token，未登录时返回 ErrNotLoggedIn。
+func (u *UserProfileAction) MyProfile(ctx context.Context) (*UserProfileResponse, string, error) {
+	page := u.page.Context(ctx)
+
+	if err := Navigate(page, configs.SiteURL("/explore")); err != nil {
+		return nil, "", errors.Wrap(err, "打开首页失败")
+	}
+
+	// 侧边栏的“我”只有登录后才会出现，链接指向当前账号的主页
+	link, err := page.Timeout(5 * time.Second).Element(profileLinkSelector)
+	if err != nil {
+		return nil, "", ErrNotLoggedIn
+	}
+	href, err := link.Property("href")
+	if err != nil {
+		return nil, "", errors.Wrap(err, "获取当前账号主页链接失败")
+	}
+
+	return u.UserProfileByURL(ctx, href.String())
+}
+
+// SummarizeProfile 汇总主页上的关注、粉丝、获赞与收藏以及笔记点赞数
+func SummarizeProfile(profile *UserProfileResponse) ProfileTotals {
+	totals := ProfileTotals{
+		NoteCount: len(profile.Feeds),
+	}
+
+	for _, interaction := range profile.Interactions {
+		count := countOrZero(interaction.Count)
+		switch interaction.Type {
+		case "follows":
+			totals.Follows = count
+		case "fans":
+			totals.Fans = count
+		case "interaction":
+			totals.Interaction = count
+		}
+	}
+
+	for _, feed := range profile.Feeds {
+		totals.NoteLikes += countOrZero(feed.NoteCard.InteractInfo.LikedCount)
+	}
+
+	return totals
+}
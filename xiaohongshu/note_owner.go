@@ -0,0 +1,67 @@
+package xiaohongshu
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/url"
+
+	"github.com/pkg/errors"
+	"github.com/xpzouying/xiaohongshu-mcp/configs"
+)
+
+// ErrNotNoteOwner 笔记不属于当前登录账号，不能编辑或修改可见范围
+var ErrNotNoteOwner = errors.New("笔记不属于当前登录账号")
+
+// noteDetailPageURL 笔记详情页链接
+func noteDetailPageURL(feedID, xsecToken string) string {
+	return configs.SiteURL(fmt.Sprintf("/explore/%s?xsec_token=%s&xsec_source=pc_feed", feedID, url.QueryEscape(xsecToken)))
+}
+
+// IsOwnNote 打开笔记详情页，判断笔记是否属于当前登录账号。
+// 作者本人在详情页能看到编辑、删除入口；找不到入口时再比较笔记作者与当前账号的 user_id。
+func (f *FeedDetailAction) IsOwnNote(ctx context.Context, feedID, xsecToken string) (bool, error) {
+	page := f.page.Context(ctx)
+
+	if err := Navigate(page, noteDetailPageURL(feedID, xsecToken)); err != nil {
+		return false, errors.Wrap(err, "打开笔记详情页失败")
+	}
+
+	result, err := page.Eval(`() => {
+		const userID = a => a ? ((a.getAttribute('href') || '').match(/\/user\/profile\/([^/?#]+)/) || [])[1] || '' : '';
+		const note = document.querySelector('#noteContainer, .note-container');
+		if (!note) {
+			return JSON.stringify({ loaded: false });
+		}
+		const affordance = Array.from(note.querySelectorAll('.note-detail-mask .operation, .dropdown-item, .menu-item'))
+			.some(el => /编辑|删除/.test(el.innerText || ''));
+		return JSON.stringify({
+			loaded: true,
+			affordance,
+			authorID: userID(note.querySelector('.author-wrapper a[href*="/user/profile/"], .author a[href*="/user/profile/"]')),
+			myID: userID(document.querySelector('.main-container .user a[href*="/user/profile/"]')),
+		});
+	}`)
+	if err != nil {
+		return false, errors.Wrap(err, "检查笔记作者失败")
+	}
+
+	var state struct {
+		Loaded     bool   `json:"loaded"`
+		Affordance bool   `json:"affordance"`
+		AuthorID   string `json:"authorID"`
+		MyID       string `json:"myID"`
+	}
+	if err := json.Unmarshal([]byte(result.Value.String()), &state); err != nil {
+		return false, errors.Wrap(err, "解析笔记作者失败")
+	}
+
+	if !state.Loaded {
+		return false, errors.Wrapf(ErrNoteUnavailable, "无法打开笔记 %s", feedID)
+	}
+	if state.MyID == "" {
+		return false, ErrNotLoggedIn
+	}
+
+	return state.Affordance || (state.AuthorID != "" && state.AuthorID == state.MyID), nil
+}
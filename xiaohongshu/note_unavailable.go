@@ -0,0 +1,58 @@
+package xiaohongshu
+
+import (
+	"net/url"
+	"regexp"
+	"strings"
+
+	"github.com/go-rod/rod"
+	"github.com/pkg/errors"
+)
+
+// ErrNoteUnavailable 笔记已被删除、设为私密或因违规不可见。
+// 与 xsec_token 过期、页面加载失败不同，重试或换令牌也无法访问
+var ErrNoteUnavailable = errors.New("笔记不存在或已不可见")
+
+// noteUnavailablePattern 笔记被删除、设为私密或违规下架时页面上的提示
+var noteUnavailablePattern = regexp.MustCompile(`笔记不存在|笔记已(被)?删除|内容已(被)?删除|已被作者删除|笔记不见了|暂时无法浏览|无法查看该笔记|仅作者可见|设为私密|违规.{0,6}(删除|下架)|内容不存在`)
+
+// CheckNoteUnavailable 检查页面是否为笔记不存在、已删除等提示页，是则返回包含提示的 ErrNoteUnavailable。
+// 笔记不可见时详情页会跳转到 /404 并在 error_msg 中说明原因，或直接显示提示文字；
+// 与 CheckTokenExpired 一样只匹配正文较短的提示页，避免把笔记正文中的同样文字误判为提示页
+func CheckNoteUnavailable(page *rod.Page) error {
+	if info, err := page.Info(); err == nil {
+		if message := noteErrorMessage(info.URL); message != "" {
+			return errors.Wrapf(ErrNoteUnavailable, "页面提示“%s”", message)
+		}
+	}
+
+	result, err := page.Eval(`() => {
+		const text = ((document.body && document.body.innerText) || '').trim();
+		return text.length > 300 ? '' : text;
+	}`)
+	if err != nil {
+		// 检查失败时无法判断，交由调用方按原结果处理
+		return nil
+	}
+
+	for _, line := range strings.Split(result.Value.String(), "\n") {
+		if line = strings.TrimSpace(line); noteUnavailablePattern.MatchString(line) {
+			return errors.Wrapf(ErrNoteUnavailable, "页面提示“%s”", line)
+		}
+	}
+	return nil
+}
+
+// noteErrorMessage 从 /404 错误页地址的 error_msg 参数中取出笔记不可见的提示，无关时返回空
+func noteErrorMessage(rawURL string) string {
+	u, err := url.Parse(rawURL)
+	if err != nil || !strings.HasPrefix(u.Path, "/404") {
+		return ""
+	}
+
+	message := u.Query().Get("error_msg")
+	if !noteUnavailablePattern.MatchString(message) {
+		return ""
+	}
+	return message
+}
@@ -0,0 +1,65 @@
+package xiaohongshu
+
+import (
+	"net/url"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNoteErrorMessage(t *testing.T) {
+	errorPage := func(message string) string {
+		return "https://www.xiaohongshu.com/404?source=note&error_code=300031&error_msg=" + url.QueryEscape(message)
+	}
+
+	tests := []struct {
+		name string
+		url  string
+		want string
+	}{
+		{name: "deleted", url: errorPage("笔记不存在"), want: "笔记不存在"},
+		{name: "private", url: errorPage("当前笔记暂时无法浏览"), want: "当前笔记暂时无法浏览"},
+		{name: "404 path with suffix", url: "https://www.xiaohongshu.com/404/sec_abc?error_msg=" + url.QueryEscape("笔记已被删除"), want: "笔记已被删除"},
+		{name: "unrelated error", url: errorPage("网络连接失败")},
+		{name: "404 without message", url: "https://www.xiaohongshu.com/404?source=note"},
+		{name: "note page", url: "https://www.xiaohongshu.com/explore/64f0a1b2c3d4e5f6a7b8c9d0?xsec_token=abc&error_msg=" + url.QueryEscape("笔记不存在")},
+		{name: "invalid url", url: "://bad"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, noteErrorMessage(tt.url))
+		})
+	}
+}
+
+func TestNoteUnavailablePattern(t *testing.T) {
+	matches := []string{
+		"抱歉，你访问的笔记不存在",
+		"笔记已删除",
+		"该内容已被删除",
+		"笔记已被作者删除",
+		"你访问的笔记不见了",
+		"当前笔记暂时无法浏览",
+		"无法查看该笔记",
+		"该笔记仅作者可见",
+		"作者已将笔记设为私密",
+		"该笔记因违规已被删除",
+		"内容因违规被下架",
+		"内容不存在",
+	}
+	for _, text := range matches {
+		assert.True(t, noteUnavailablePattern.MatchString(text), text)
+	}
+
+	misses := []string{
+		"",
+		"登录后查看更多精彩内容",
+		"安全限制，请稍后再试",
+		"今天分享一个删除照片背景的小技巧",
+		"笔记存在感很强的一款包",
+	}
+	for _, text := range misses {
+		assert.False(t, noteUnavailablePattern.MatchString(text), text)
+	}
+}
@@ -0,0 +1,185 @@
+package xiaohongshu
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+	"time"
+
+	"github.com/go-rod/rod"
+	"github.com/pkg/errors"
+	"github.com/xpzouying/xiaohongshu-mcp/configs"
+)
+
+// 通知类别，与通知中心的分类对应
+const (
+	NotificationLikes    = "likes"    // 赞和收藏
+	NotificationComments = "comments" // 评论和回复
+	NotificationMentions = "mentions" // @我
+	NotificationFollows  = "follows"  // 新增关注
+)
+
+// notificationTabs 各通知类别所在的通知中心标签页，评论和 @ 在同一个标签页中
+var notificationTabs = map[string]string{
+	NotificationLikes:    "赞和收藏",
+	NotificationComments: "评论和@",
+	NotificationMentions: "评论和@",
+	NotificationFollows:  "新增关注",
+}
+
+// pathOfNotifications 通知中心
+const pathOfNotifications = "/notification"
+
+// NotificationActor 触发通知的用户
+type NotificationActor struct {
+	UserID   string `json:"user_id,omitempty"`
+	Nickname string `json:"nickname"`
+	Avatar   string `json:"avatar,omitempty"`
+}
+
+// Notification 通知中心的一条通知
+type Notification struct {
+	Category string            `json:"category"`          // likes、comments、mentions、follows
+	Action   string            `json:"action"`            // 页面上的原始描述，如“赞了你的笔记”
+	Actor    NotificationActor `json:"actor"`             // 触发通知的用户
+	Content  string            `json:"content,omitempty"` // 评论、回复的内容
+	NoteID   string            `json:"note_id,omitempty"` // 相关笔记，关注类通知为空
+	NoteURL  string            `json:"note_url,omitempty"`
+	Time     string            `json:"time,omitempty"` // 页面展示的时间，如“3小时前”
+}
+
+// NotificationsAction 读取通知中心
+type NotificationsAction struct {
+	page *rod.Page
+}
+
+// NewNotificationsAction 创建读取通知的 action
+func NewNotificationsAction(page *rod.Page) *NotificationsAction {
+	return &NotificationsAction{page: page}
+}
+
+// ValidNotificationCategory 判断通知类别是否有效，为空表示全部类别
+func ValidNotificationCategory(category string) bool {
+	_, ok := notificationTabs[category]
+	return category == "" || ok
+}
+
+// ListNotifications 打开通知中心，读取各标签页中最近的通知。category 为空时返回全部类别
+func (n *NotificationsAction) ListNotifications(ctx context.Context, category string) ([]Notification, error) {
+	if !ValidNotificationCategory(category) {
+		return nil, errors.Errorf("不支持的通知类别: %s，只支持 likes、comments、mentions、follows", category)
+	}
+
+	page := n.page.Context(ctx)
+
+	if err := Navigate(page, configs.SiteURL(pathOfNotifications)); err != nil {
+		return nil, errors.Wrap(err, "打开通知中心失败")
+	}
+	if err := CheckLoginWall(page); err != nil {
+		return nil, err
+	}
+
+	tabs := []string{"评论和@", "赞和收藏", "新增关注"}
+	if category != "" {
+		tabs = []string{notificationTabs[category]}
+	}
+
+	notifications := []Notification{}
+	for _, tab := range tabs {
+		items, err := readNotificationTab(page, tab)
+		if err != nil {
+			return nil, err
+		}
+		for _, item := range items {
+			if category == "" || item.Category == category {
+				notifications = append(notifications, item)
+			}
+		}
+	}
+
+	return notifications, nil
+}
+
+// readNotificationTab 切换到通知中心的标签页并读取其中已加载的通知
+func readNotificationTab(page *rod.Page, tab string) ([]Notification, error) {
+	button, err := findElementR(page, ".reds-tabs-list .reds-tab-item, .tabs .tab-item", tab)
+	if err != nil {
+		return nil, errors.Wrapf(err, "没有找到通知标签页: %s", tab)
+	}
+	if err := humanClick(button); err != nil {
+		return nil, errors.Wrapf(err, "切换通知标签页失败: %s", tab)
+	}
+	if err := page.WaitStable(time.Second); err != nil {
+		return nil, errors.Wrap(err, "等待通知加载失败")
+	}
+
+	result, err := page.Eval(`() => JSON.stringify(Array.from(document.querySelectorAll('.tabs-content-container .container')).map(item => {
+		const text = sel => { const el = item.querySelector(sel); return el ? el.innerText.trim() : ''; };
+		const user = item.querySelector('.user-info a[href*="/user/profile/"], a.user-avatar[href*="/user/profile/"]');
+		const avatar = item.querySelector('.user-avatar img');
+		const note = item.querySelector('a[href*="/explore/"], a[href*="/discovery/item/"]');
+		return {
+			action: text('.interaction-hint span:first-child') || text('.interaction-hint'),
+			nickname: text('.user-info a') || text('.user-info'),
+			userHref: user ? user.href : '',
+			avatar: avatar ? avatar.src : '',
+			content: text('.interaction-content'),
+			noteURL: note ? note.href : '',
+			time: text('.interaction-time') || text('.interaction-hint span:last-child'),
+		};
+	}))`)
+	if err != nil {
+		return nil, errors.Wrap(err, "获取通知失败")
+	}
+
+	var items []struct {
+		Action   string `json:"action"`
+		Nickname string `json:"nickname"`
+		UserHref string `json:"userHref"`
+		Avatar   string `json:"avatar"`
+		Content  string `json:"content"`
+		NoteURL  string `json:"noteURL"`
+		Time     string `json:"time"`
+	}
+	if err := json.Unmarshal([]byte(result.Value.String()), &items); err != nil {
+		return nil, errors.Wrap(err, "解析通知失败")
+	}
+
+	notifications := make([]Notification, 0, len(items))
+	for _, item := range items {
+		notification := Notification{
+			Category: notificationCategory(tab, item.Action),
+			Action:   item.Action,
+			Actor: NotificationActor{
+				Nickname: item.Nickname,
+				Avatar:   item.Avatar,
+			},
+			Content: item.Content,
+			NoteURL: item.NoteURL,
+			Time:    item.Time,
+		}
+		if item.UserHref != "" {
+			notification.Actor.UserID, _ = userIDFromProfileURL(item.UserHref)
+		}
+		if item.NoteURL != "" {
+			notification.NoteID = CanonicalFeedID(item.NoteURL)
+		}
+		notifications = append(notifications, notification)
+	}
+
+	return notifications, nil
+}
+
+// notificationCategory 根据所在标签页和通知描述判断类别，“评论和@”标签页中 @ 类通知归为 mentions
+func notificationCategory(tab, action string) string {
+	switch tab {
+	case notificationTabs[NotificationLikes]:
+		return NotificationLikes
+	case notificationTabs[NotificationFollows]:
+		return NotificationFollows
+	}
+	if strings.Contains(action, "@") || strings.Contains(action, "提到了你") {
+		return NotificationMentions
+	}
+	return NotificationComments
+}
@@ -0,0 +1,54 @@
+package xiaohongshu
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+
+	"github.com/sirupsen/logrus"
+)
+
+// 操作ID
+//
+// 每个 MCP/HTTP 请求生成一个操作ID，通过 context 传给 service 和各个 action。
+// 用 logrus.WithContext(ctx) 记录的日志会带上 op_id 字段，按操作ID grep 即可看到一次调用的完整过程。
+
+// opIDKey 操作ID在 context 中的键
+type opIDKey struct{}
+
+// WithOpID 生成新的操作ID并放入 context，已有的操作ID会被覆盖
+func WithOpID(ctx context.Context) context.Context {
+	b := make([]byte, 8)
+	_, _ = rand.Read(b)
+	return context.WithValue(ctx, opIDKey{}, hex.EncodeToString(b))
+}
+
+// OpIDFrom 读取 context 中的操作ID，没有时返回空字符串
+func OpIDFrom(ctx context.Context) string {
+	if ctx == nil {
+		return ""
+	}
+	id, _ := ctx.Value(opIDKey{}).(string)
+	return id
+}
+
+// opIDHook 为带 context 的日志添加 op_id 字段
+type opIDHook struct{}
+
+// NewOpIDHook 创建日志 hook，通过 logrus.AddHook 注册后，logrus.WithContext(ctx) 记录的日志带上操作ID
+func NewOpIDHook() logrus.Hook {
+	return opIDHook{}
+}
+
+// Levels 对所有日志级别生效
+func (opIDHook) Levels() []logrus.Level {
+	return logrus.AllLevels
+}
+
+// Fire 日志的 context 中有操作ID时添加 op_id 字段
+func (opIDHook) Fire(entry *logrus.Entry) error {
+	if id := OpIDFrom(entry.Context); id != "" {
+		entry.Data["op_id"] = id
+	}
+	return nil
+}
@@ -0,0 +1,103 @@
+package xiaohongshu
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// ErrInvalidCursor 游标无法解析，或不是由当前列表生成的
+var ErrInvalidCursor = errors.New("无效的游标")
+
+// Cursor 分页游标，Feeds 列表、评论、用户笔记等分页接口共用同一格式，
+// 编码为 base64 的 JSON，解码后即可查看内容，便于排查问题。
+// Scope 记录生成游标的列表，一个列表的游标不能用于另一个列表
+type Cursor struct {
+	Scope    string `json:"scope"`             // 生成游标的列表，如 comments:<feed_id>
+	Offset   int    `json:"offset"`            // 已返回的条目数
+	LastID   string `json:"last_id,omitempty"` // 上一页最后一条的 ID，列表前面插入了新条目时据此对齐
+	IssuedAt int64  `json:"issued_at"`         // 生成时间（Unix 秒）
+}
+
+// FeedsCursorScope 首页 Feeds 列表的游标范围，按笔记类型过滤后的列表单独计数
+func FeedsCursorScope(noteType string) string {
+	if noteType == "" {
+		return "feeds"
+	}
+	return "feeds:" + noteType
+}
+
+// CommentsCursorScope 笔记评论列表的游标范围
+func CommentsCursorScope(feedID string) string {
+	return "comments:" + feedID
+}
+
+// UserFeedsCursorScope 用户主页笔记列表的游标范围
+func UserFeedsCursorScope(userID string) string {
+	return "user_feeds:" + userID
+}
+
+// NextCursor 生成下一页的游标，offset 为已返回的条目数，lastID 为本页最后一条的 ID
+func NextCursor(scope string, offset int, lastID string) string {
+	return Cursor{
+		Scope:    scope,
+		Offset:   offset,
+		LastID:   lastID,
+		IssuedAt: time.Now().Unix(),
+	}.Encode()
+}
+
+// Encode 将游标编码为 URL 安全的 base64 字符串
+func (c Cursor) Encode() string {
+	data, _ := json.Marshal(c)
+	return base64.RawURLEncoding.EncodeToString(data)
+}
+
+// DecodeCursor 解码游标并确认游标属于 scope 指定的列表。s 为空表示第一页，返回偏移为 0 的游标
+func DecodeCursor(s, scope string) (Cursor, error) {
+	if s == "" {
+		return Cursor{Scope: scope}, nil
+	}
+
+	data, err := base64.RawURLEncoding.DecodeString(s)
+	if err != nil {
+		return Cursor{}, errors.Wrapf(ErrInvalidCursor, "游标不是有效的 base64: %s", s)
+	}
+
+	var c Cursor
+	if err := json.Unmarshal(data, &c); err != nil {
+		return Cursor{}, errors.Wrapf(ErrInvalidCursor, "游标内容格式错误: %s", s)
+	}
+	if c.Offset < 0 {
+		return Cursor{}, errors.Wrapf(ErrInvalidCursor, "游标偏移量不能为负数: %d", c.Offset)
+	}
+	if c.Scope != scope {
+		return Cursor{}, errors.Wrapf(ErrInvalidCursor, "游标属于 %s，不能用于 %s", c.Scope, scope)
+	}
+
+	return c, nil
+}
+
+// FeedIDs 返回笔记列表中各条笔记的 ID，用于 ResumeOffset
+func FeedIDs(feeds []Feed) []string {
+	ids := make([]string, len(feeds))
+	for i, feed := range feeds {
+		ids[i] = feed.ID
+	}
+	return ids
+}
+
+// ResumeOffset 返回本页的起始位置。ids 中能找到上一页最后一条时从它的下一条开始，
+// 避免列表前面插入新条目后重复返回；找不到时按偏移量继续
+func (c Cursor) ResumeOffset(ids []string) int {
+	if c.LastID != "" {
+		for i, id := range ids {
+			if id == c.LastID {
+				return i + 1
+			}
+		}
+	}
+	return c.Offset
+}
@@ -0,0 +1,78 @@
+package xiaohongshu
+
+import (
+	"encoding/base64"
+	"testing"
+
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCursorRoundTrip(t *testing.T) {
+	scope := CommentsCursorScope("64f0a1b2c3d4e5f6a7b8c9d0")
+
+	encoded := NextCursor(scope, 20, "c20")
+	assert.NotContains(t, encoded, "=", "游标应为无填充的 URL 安全 base64")
+
+	c, err := DecodeCursor(encoded, scope)
+	require.NoError(t, err)
+	assert.Equal(t, scope, c.Scope)
+	assert.Equal(t, 20, c.Offset)
+	assert.Equal(t, "c20", c.LastID)
+	assert.NotZero(t, c.IssuedAt)
+
+	assert.Equal(t, encoded, c.Encode())
+}
+
+func TestDecodeCursorFirstPage(t *testing.T) {
+	c, err := DecodeCursor("", FeedsCursorScope("video"))
+	require.NoError(t, err)
+	assert.Equal(t, Cursor{Scope: "feeds:video"}, c)
+}
+
+func TestDecodeCursorInvalid(t *testing.T) {
+	encode := func(raw string) string {
+		return base64.RawURLEncoding.EncodeToString([]byte(raw))
+	}
+
+	tests := []struct {
+		name   string
+		cursor string
+		scope  string
+	}{
+		{name: "other scope", cursor: NextCursor(FeedsCursorScope(""), 10, "a"), scope: FeedsCursorScope("video")},
+		{name: "other user", cursor: NextCursor(UserFeedsCursorScope("u1"), 10, ""), scope: UserFeedsCursorScope("u2")},
+		{name: "not base64", cursor: "!!!", scope: "feeds"},
+		{name: "not json", cursor: encode("offset=10"), scope: "feeds"},
+		{name: "negative offset", cursor: encode(`{"scope":"feeds","offset":-1}`), scope: "feeds"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := DecodeCursor(tt.cursor, tt.scope)
+			assert.True(t, errors.Is(err, ErrInvalidCursor), "err = %v", err)
+		})
+	}
+}
+
+func TestCursorResumeOffset(t *testing.T) {
+	tests := []struct {
+		name   string
+		cursor Cursor
+		ids    []string
+		want   int
+	}{
+		{name: "no last id", cursor: Cursor{Offset: 2}, ids: []string{"a", "b", "c"}, want: 2},
+		{name: "last id in place", cursor: Cursor{Offset: 2, LastID: "b"}, ids: []string{"a", "b", "c"}, want: 2},
+		{name: "new items prepended", cursor: Cursor{Offset: 2, LastID: "b"}, ids: []string{"x", "y", "a", "b", "c"}, want: 4},
+		{name: "last id gone", cursor: Cursor{Offset: 2, LastID: "gone"}, ids: []string{"a", "c"}, want: 2},
+		{name: "empty list", cursor: Cursor{Offset: 3, LastID: "b"}, want: 3},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, tt.cursor.ResumeOffset(tt.ids))
+		})
+	}
+}
@@ -0,0 +1,80 @@
+package xiaohongshu
+
+import (
+	"context"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/go-rod/rod"
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+)
+
+// ErrPublishUnconfirmed 点击发布后在等待时间内没有出现发布成功的提示，笔记可能发出了也可能没有
+var ErrPublishUnconfirmed = errors.New("点击发布后没有等到发布成功的提示，笔记可能仍在处理中，请到创作者中心确认后再决定是否重试")
+
+// publishConfirmPollInterval 等待发布成功提示时的检查间隔
+const publishConfirmPollInterval = 500 * time.Millisecond
+
+// publishSuccessKeywords 发布成功提示中的关键词
+var publishSuccessKeywords = []string{"发布成功", "已发布"}
+
+// publishSuccessPath 发布成功后创作者中心跳转到的页面
+const publishSuccessPath = "/publish/success"
+
+// WaitPublishConfirmed 点击发布后轮询页面，直到出现发布成功的提示或跳转到发布成功页，返回能从页面上取到的笔记ID（可能为空）。
+// 期间出现内容被拦截等错误提示时返回对应的错误；超过 timeout 仍未确认时返回 ErrPublishUnconfirmed
+func WaitPublishConfirmed(ctx context.Context, page *rod.Page, timeout time.Duration) (string, error) {
+	deadline := time.NewTimer(timeout)
+	defer deadline.Stop()
+	ticker := time.NewTicker(publishConfirmPollInterval)
+	defer ticker.Stop()
+
+	for {
+		banner := currentBannerText(page)
+		if confirmed, noteID := publishConfirmed(page, banner); confirmed {
+			logrus.WithContext(ctx).Infof("已确认发布成功，笔记ID: %s", noteID)
+			return noteID, nil
+		}
+		if err := classifyBanner(banner); err != nil {
+			return "", err
+		}
+
+		select {
+		case <-ctx.Done():
+			return "", ctx.Err()
+		case <-deadline.C:
+			logrus.WithContext(ctx).Warnf("点击发布后 %s 内没有出现发布成功的提示", timeout)
+			return "", ErrPublishUnconfirmed
+		case <-ticker.C:
+		}
+	}
+}
+
+// publishConfirmed 判断页面是否已跳转到发布成功页或显示了发布成功的提示 banner，跳转链接中带有笔记ID时一并返回
+func publishConfirmed(page *rod.Page, banner string) (bool, string) {
+	if info, err := page.Info(); err == nil {
+		if u, err := url.Parse(info.URL); err == nil && strings.HasPrefix(u.Path, publishSuccessPath) {
+			query := u.Query()
+			for _, key := range []string{"noteId", "note_id", "id"} {
+				if id := query.Get(key); id != "" {
+					return true, id
+				}
+			}
+			return true, ""
+		}
+	}
+
+	return containsAny(banner, publishSuccessKeywords), ""
+}
+
+// currentBannerText 立即读取页面上当前显示的提示文本，与 publishBannerText 不同，没有提示时不等待
+func currentBannerText(page *rod.Page) string {
+	result, err := page.Eval(`(selector) => Array.from(document.querySelectorAll(selector))
+		.map(el => el.innerText.trim()).filter(Boolean).join(' ')`, publishBannerSelector)
+	if err != nil {
+		return ""
+	}
+	return result.Value.String()
+}
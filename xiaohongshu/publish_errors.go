@@ -0,0 +1,106 @@
+package xiaohongshu
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/go-rod/rod"
+	"github.com/pkg/errors"
+)
+
+var (
+	// ErrUploadFailed 图片上传失败
+	ErrUploadFailed = errors.New("图片上传失败")
+	// ErrContentRejected 内容被平台拦截，未能发布
+	ErrContentRejected = errors.New("内容未通过平台审核")
+)
+
+// ContentRejectedError 内容被平台拦截，Reason 为页面提示的拦截原因
+type ContentRejectedError struct {
+	Reason string
+}
+
+func (e *ContentRejectedError) Error() string {
+	return fmt.Sprintf("%s: %s", ErrContentRejected.Error(), e.Reason)
+}
+
+// Is 使 errors.Is(err, ErrContentRejected) 成立
+func (e *ContentRejectedError) Is(target error) bool {
+	return target == ErrContentRejected
+}
+
+// 页面提示文本中用于归类错误的关键词
+var (
+	notLoggedInKeywords  = []string{"登录", "login"}
+	uploadFailedKeywords = []string{"上传失败", "图片格式", "图片过大", "上传出错"}
+	rejectedKeywords     = []string{"违规", "违反", "不符合", "敏感", "社区规范", "无法发布"}
+)
+
+// ClassifyPublishError 结合发布页当前的提示信息，把发布失败的原因归类为
+// ErrNotLoggedIn、ErrUploadFailed 或 ContentRejectedError；无法归类时原样返回 err。
+func ClassifyPublishError(page *rod.Page, err error) error {
+	if err == nil {
+		return nil
+	}
+
+	if info, infoErr := page.Info(); infoErr == nil && strings.Contains(info.URL, "/login") {
+		return errors.Wrap(ErrNotLoggedIn, err.Error())
+	}
+
+	banner := publishBannerText(page)
+	if classified := classifyBanner(banner); classified != nil {
+		return errors.Wrap(classified, err.Error())
+	}
+
+	return err
+}
+
+// CheckPublishResult 提交后检查页面是否出现错误提示，例如内容被拦截
+func CheckPublishResult(page *rod.Page) error {
+	return classifyBanner(publishBannerText(page))
+}
+
+// classifyBanner 根据提示文本归类错误，文本为空或无法归类时返回 nil
+func classifyBanner(banner string) error {
+	switch {
+	case banner == "":
+		return nil
+	case containsAny(banner, rejectedKeywords):
+		return &ContentRejectedError{Reason: banner}
+	case containsAny(banner, uploadFailedKeywords):
+		return errors.Wrap(ErrUploadFailed, banner)
+	case containsAny(banner, notLoggedInKeywords):
+		return errors.Wrap(ErrNotLoggedIn, banner)
+	default:
+		return nil
+	}
+}
+
+// publishBannerSelector 发布页上的提示（toast/message）
+const publishBannerSelector = ".d-toast, .d-message, .el-message, .error-tip"
+
+// publishBannerText 读取页面上的错误提示（toast/message），没有时返回空字符串
+func publishBannerText(page *rod.Page) string {
+	el, err := page.Timeout(2 * time.Second).Element(publishBannerSelector)
+	if err != nil {
+		return ""
+	}
+
+	text, err := el.Text()
+	if err != nil {
+		return ""
+	}
+
+	return strings.TrimSpace(text)
+}
+
+// containsAny 判断文本是否包含任意一个关键词
+func containsAny(text string, keywords []string) bool {
+	for _, keyword := range keywords {
+		if strings.Contains(text, keyword) {
+			return true
+		}
+	}
+	return false
+}
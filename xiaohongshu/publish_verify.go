@@ -0,0 +1,126 @@
+package xiaohongshu
+
+import (
+	"context"
+	"encoding/json"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/go-rod/rod"
+	"github.com/pkg/errors"
+	"github.com/xpzouying/xiaohongshu-mcp/configs"
+)
+
+// PublishedNote 创作者中心笔记管理中的一篇笔记
+type PublishedNote struct {
+	ID          string    `json:"id"`
+	Title       string    `json:"title"`
+	PublishedAt time.Time `json:"published_at"`
+}
+
+// NoteManagerAction 创作者中心的笔记管理页
+type NoteManagerAction struct {
+	page *rod.Page
+}
+
+// NewNoteManagerAction 创建笔记管理 action
+func NewNoteManagerAction(page *rod.Page) *NoteManagerAction {
+	return &NoteManagerAction{page: page}
+}
+
+// noteManagerItemSelector 笔记管理页中的笔记卡片
+const noteManagerItemSelector = `.note-item, .note-card, [class*="note-item"]`
+
+// noteTimeRe 笔记管理页显示的发布时间，如“发布于 2024年05月20日 14:30”或“2024-05-20 14:30”
+var noteTimeRe = regexp.MustCompile(`(\d{4})[年\-/](\d{1,2})[月\-/](\d{1,2})日?\s*(\d{1,2}):(\d{2})`)
+
+// creatorTimeZone 创作者中心按北京时间显示发布时间
+var creatorTimeZone = time.FixedZone("CST", 8*60*60)
+
+// RecentNotes 打开创作者中心的笔记管理页，返回第一页的笔记（最新发布的在前），包括审核中的笔记
+func (n *NoteManagerAction) RecentNotes(ctx context.Context) ([]PublishedNote, error) {
+	page := n.page.Context(ctx)
+
+	if err := Navigate(page, configs.CreatorURL("/new/note-manager")); err != nil {
+		return nil, errors.Wrap(err, "打开笔记管理页失败")
+	}
+	if err := CheckLoginWall(page); err != nil {
+		return nil, err
+	}
+
+	// 列表异步加载，账号没有笔记时等待超时后按空列表处理
+	_ = page.Timeout(configs.GetElementTimeout()).Wait(rod.Eval(`() => document.querySelectorAll('` + noteManagerItemSelector + `').length > 0`))
+
+	result, err := page.Eval(`() => JSON.stringify(Array.from(document.querySelectorAll('` + noteManagerItemSelector + `')).map(el => {
+		const lines = (el.innerText || '').split('\n').map(s => s.trim()).filter(Boolean);
+		const titleEl = el.querySelector('.title, .note-title, [class*="title"]');
+		const id = (((el.getAttribute('data-impression') || '') + ' ' + el.innerHTML).match(/[0-9a-f]{24}/) || [])[0] || '';
+		return {
+			id,
+			title: titleEl ? (titleEl.innerText || '').trim() : (lines[0] || ''),
+			time: lines.find(s => /\d{4}[年\-/]\d{1,2}[月\-/]\d{1,2}/.test(s)) || '',
+		};
+	}))`)
+	if err != nil {
+		return nil, errors.Wrap(err, "读取笔记管理列表失败")
+	}
+
+	var raw []struct {
+		ID    string `json:"id"`
+		Title string `json:"title"`
+		Time  string `json:"time"`
+	}
+	if err := json.Unmarshal([]byte(result.Value.String()), &raw); err != nil {
+		return nil, errors.Wrap(err, "解析笔记管理列表失败")
+	}
+
+	notes := make([]PublishedNote, 0, len(raw))
+	for _, r := range raw {
+		publishedAt, ok := parseNoteTime(r.Time)
+		if !ok {
+			continue
+		}
+		notes = append(notes, PublishedNote{ID: r.ID, Title: r.Title, PublishedAt: publishedAt})
+	}
+
+	return notes, nil
+}
+
+// parseNoteTime 解析笔记管理页显示的发布时间，精确到分钟
+func parseNoteTime(text string) (time.Time, bool) {
+	m := noteTimeRe.FindStringSubmatch(text)
+	if m == nil {
+		return time.Time{}, false
+	}
+
+	var parts [5]int
+	for i := range parts {
+		parts[i], _ = strconv.Atoi(m[i+1])
+	}
+	return time.Date(parts[0], time.Month(parts[1]), parts[2], parts[3], parts[4], 0, 0, creatorTimeZone), true
+}
+
+// FindPublishedNote 在 notes 中查找标题为 title、发布时间不早于 since 的笔记。
+// 笔记管理页的标题过长时会被截断为“...”结尾，此时按前缀匹配
+func FindPublishedNote(notes []PublishedNote, title string, since time.Time) (PublishedNote, bool) {
+	title = strings.TrimSpace(title)
+	for _, note := range notes {
+		if note.PublishedAt.Before(since) {
+			continue
+		}
+
+		shown := strings.TrimSpace(note.Title)
+		if shown == title {
+			return note, true
+		}
+		if prefix, ok := strings.CutSuffix(shown, "..."); ok && prefix != "" && strings.HasPrefix(title, prefix) {
+			return note, true
+		}
+		if prefix, ok := strings.CutSuffix(shown, "…"); ok && prefix != "" && strings.HasPrefix(title, prefix) {
+			return note, true
+		}
+	}
+	return PublishedNote{}, false
+}
@@ -0,0 +1,76 @@
+package xiaohongshu
+
+import (
+	"strings"
+	"time"
+
+	"github.com/go-rod/rod"
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+)
+
+// Visibility 笔记的可见范围
+type Visibility string
+
+const (
+	VisibilityPublic  Visibility = "public"  // 公开可见
+	VisibilityFriends Visibility = "friends" // 仅互关好友可见
+	VisibilityPrivate Visibility = "private" // 仅自己可见
+)
+
+// visibilityLabels 发布设置中各可见范围对应的选项文字
+var visibilityLabels = map[Visibility]string{
+	VisibilityPublic:  "公开可见",
+	VisibilityFriends: "好友可见",
+	VisibilityPrivate: "仅自己可见",
+}
+
+// ParseVisibility 解析可见范围，为空时默认公开
+func ParseVisibility(s string) (Visibility, error) {
+	v := Visibility(strings.ToLower(strings.TrimSpace(s)))
+	if v == "" {
+		return VisibilityPublic, nil
+	}
+	if _, ok := visibilityLabels[v]; !ok {
+		return "", errors.Errorf("不支持的可见范围: %s，只支持 public、friends、private", s)
+	}
+	return v, nil
+}
+
+// visibilityControlPattern 匹配可见范围设置当前显示的选项文字
+const visibilityControlPattern = "公开可见|好友可见|仅自己可见"
+
+// findVisibilityControl 查找发布、编辑页中的可见范围设置
+func findVisibilityControl(page *rod.Page) (*rod.Element, error) {
+	return page.Timeout(5*time.Second).ElementR("div.permission-card-wrapper, div.d-select-wrapper", visibilityControlPattern)
+}
+
+// setVisibility 展开可见范围设置并选择指定选项，当前已是该选项时不做操作
+func setVisibility(page *rod.Page, trigger *rod.Element, visibility Visibility) error {
+	label, ok := visibilityLabels[visibility]
+	if !ok {
+		return errors.Errorf("不支持的可见范围: %s", visibility)
+	}
+
+	if current, err := trigger.Text(); err == nil && strings.Contains(current, label) {
+		return nil
+	}
+
+	if err := humanClick(trigger); err != nil {
+		return errors.Wrap(err, "打开可见范围设置失败")
+	}
+	time.Sleep(500 * time.Millisecond)
+
+	option, err := findElementR(page, "div.d-popover div.d-options-wrapper div, div.d-dropdown-content div", label)
+	if err != nil {
+		return errors.Wrapf(err, "没有找到可见范围选项: %s", label)
+	}
+	if err := humanClick(option); err != nil {
+		return errors.Wrapf(err, "选择可见范围失败: %s", label)
+	}
+	time.Sleep(500 * time.Millisecond)
+
+	logrus.WithContext(page.GetContext()).Infof("已将笔记设为%s", label)
+
+	return nil
+}
@@ -0,0 +1,52 @@
+package xiaohongshu
+
+import (
+	"regexp"
+	"strings"
+
+	"github.com/go-rod/rod"
+	"github.com/pkg/errors"
+)
+
+// ErrRateLimited 小红书提示操作过于频繁，账号被平台临时限流。继续操作可能升级为封号，
+// 需要等待一段时间后再执行写操作。与本服务自身的浏览器并发限制无关
+var ErrRateLimited = errors.New("小红书提示操作过于频繁")
+
+// rateLimitSelector 显示限流提示的 toast、message 和弹窗
+const rateLimitSelector = ".d-toast, .d-message, .el-message, [role=alert], [role=dialog], .d-modal, .reds-modal"
+
+// rateLimitPattern 限流提示的文字
+var rateLimitPattern = regexp.MustCompile(`操作过于频繁|操作太频繁|操作频繁|请求过于频繁|访问过于频繁|频繁操作`)
+
+// CheckRateLimited 检查页面上是否出现“操作过于频繁”之类的提示，是则返回包含提示文字的 ErrRateLimited。
+// 只检查可见的 toast 和弹窗，与验证码检测一样，不会把笔记、评论正文中的同样文字误判为限流
+func CheckRateLimited(page *rod.Page) error {
+	result, err := page.Eval(`(selector) => Array.from(document.querySelectorAll(selector))
+		.filter(el => el.offsetWidth > 0 && el.offsetHeight > 0)
+		.map(el => (el.innerText || '').trim())`, rateLimitSelector)
+	if err != nil {
+		// 检查失败时无法判断，交由调用方按原结果处理
+		return nil
+	}
+
+	var texts []string
+	for _, text := range result.Value.Arr() {
+		texts = append(texts, text.String())
+	}
+	if message := rateLimitMessage(texts); message != "" {
+		return errors.Wrapf(ErrRateLimited, "页面提示“%s”", message)
+	}
+	return nil
+}
+
+// rateLimitMessage 在提示框的文字中找出限流提示所在的那一行，没有时返回空字符串
+func rateLimitMessage(texts []string) string {
+	for _, text := range texts {
+		for _, line := range strings.Split(text, "\n") {
+			if line = strings.TrimSpace(line); rateLimitPattern.MatchString(line) {
+				return line
+			}
+		}
+	}
+	return ""
+}
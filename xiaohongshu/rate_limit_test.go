@@ -0,0 +1,26 @@
+package xiaohongshu
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRateLimitMessage(t *testing.T) {
+	tests := []struct {
+		name  string
+		texts []string
+		want  string
+	}{
+		{"no containers", nil, ""},
+		{"toast", []string{"操作过于频繁，请稍后再试"}, "操作过于频繁，请稍后再试"},
+		{"dialog with title", []string{"提示\n  请求过于频繁，请休息一下  \n确定"}, "请求过于频繁，请休息一下"},
+		{"unrelated toast", []string{"评论成功"}, ""},
+		{"second container", []string{"发布成功", "访问过于频繁"}, "访问过于频繁"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, rateLimitMessage(tt.texts))
+		})
+	}
+}
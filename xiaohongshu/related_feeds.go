@@ -0,0 +1,70 @@
+package xiaohongshu
+
+import (
+	"encoding/json"
+
+	"github.com/go-rod/rod"
+	"github.com/pkg/errors"
+	"github.com/xpzouying/xiaohongshu-mcp/configs"
+)
+
+// relatedFeedsJS 读取详情页“相关推荐”中的笔记卡片，按 Feed 的 JSON 结构返回。
+// 卡片链接中带有每篇笔记自己的 xsec_token，当前笔记本身不计入
+const relatedFeedsJS = `(feedID) => {
+	const cards = Array.from(document.querySelectorAll('section.note-item'))
+		.filter(el => !el.closest('#noteContainer, .note-container'));
+	const feeds = [];
+	const seen = new Set([feedID]);
+	for (const el of cards) {
+		const link = Array.from(el.querySelectorAll('a[href]'))
+			.map(a => a.getAttribute('href') || '')
+			.find(href => /\/(explore|discovery\/item|search_result)\/[0-9a-zA-Z]+/.test(href));
+		if (!link) {
+			continue;
+		}
+		const u = new URL(link, location.origin);
+		const id = u.pathname.split('/').filter(Boolean).pop();
+		if (seen.has(id)) {
+			continue;
+		}
+		seen.add(id);
+		const author = el.querySelector('a.author, .author-wrapper a, .card-bottom-wrapper a');
+		const userID = author ? (((author.getAttribute('href') || '').match(/\/user\/profile\/([^/?#]+)/) || [])[1] || '') : '';
+		const text = sel => { const n = el.querySelector(sel); return n ? (n.innerText || '').trim() : ''; };
+		const cover = el.querySelector('a.cover img, img');
+		feeds.push({
+			id,
+			xsecToken: u.searchParams.get('xsec_token') || '',
+			modelType: 'note',
+			index: feeds.length,
+			noteCard: {
+				type: el.querySelector('.play-icon') ? 'video' : 'normal',
+				displayTitle: text('.footer .title, .title'),
+				user: { userId: userID, nickname: text('.author .name, .name'), avatar: (el.querySelector('.author img') || {}).src || '' },
+				interactInfo: { likedCount: text('.like-wrapper .count, .count') },
+				cover: { urlDefault: cover ? cover.src : '' },
+			},
+		});
+	}
+	return JSON.stringify(feeds);
+}`
+
+// GetRelatedFeeds 从已打开的笔记详情页读取“相关推荐”的笔记，推荐异步加载，等待至多元素超时时间。
+// 推荐没有加载出来时返回空列表
+func GetRelatedFeeds(page *rod.Page, feedID string) ([]Feed, error) {
+	// 推荐列表在详情下方，滚动一次触发懒加载
+	_, _ = page.Eval(`() => window.scrollTo(0, document.body.scrollHeight)`)
+	_ = page.Timeout(configs.GetElementTimeout()).Wait(rod.Eval(`(feedID) => JSON.parse((`+relatedFeedsJS+`)(feedID)).length > 0`, feedID))
+
+	result, err := page.Eval(relatedFeedsJS, feedID)
+	if err != nil {
+		return nil, errors.Wrap(err, "读取相关推荐失败")
+	}
+
+	var feeds []Feed
+	if err := json.Unmarshal([]byte(result.Value.String()), &feeds); err != nil {
+		return nil, errors.Wrap(err, "解析相关推荐失败")
+	}
+
+	return feeds, nil
+}
@@ -0,0 +1,32 @@
+package xiaohongshu
+
+import (
+	"strings"
+
+	"github.com/go-rod/rod"
+	"github.com/pkg/errors"
+)
+
+// ErrServiceBusy 小红书返回了“系统繁忙”、维护中等提示页，稍后重试即可，与选择器失效无关
+var ErrServiceBusy = errors.New("小红书服务暂时不可用，请稍后重试")
+
+// CheckServiceBusy 检查页面是否为系统繁忙、维护等提示页，是则返回包含页面提示文字的 ErrServiceBusy。
+// 提示页内容很少，只在页面正文较短时匹配，避免把笔记、评论中的同样文字误判为提示页
+func CheckServiceBusy(page *rod.Page) error {
+	result, err := page.Eval(`() => {
+		const text = ((document.body && document.body.innerText) || '').trim();
+		if (text.length > 300) return '';
+		const pattern = /系统繁忙|服务器繁忙|服务繁忙|开小差|系统维护|维护中|服务暂不可用|请稍后再试|请稍后重试/;
+		const line = text.split('\n').map(s => s.trim()).find(s => pattern.test(s));
+		return line || (pattern.test(document.title) ? document.title.trim() : '');
+	}`)
+	if err != nil {
+		// 检查失败时无法判断，交由调用方按原结果处理
+		return nil
+	}
+
+	if message := strings.TrimSpace(result.Value.String()); message != "" {
+		return errors.Wrapf(ErrServiceBusy, "页面提示“%s”", message)
+	}
+	return nil
+}
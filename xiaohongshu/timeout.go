@@ -0,0 +1,80 @@
+package xiaohongshu
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/go-rod/rod"
+	"github.com/pkg/errors"
+	"github.com/xpzouying/xiaohongshu-mcp/configs"
+)
+
+// TimeoutError 页面操作超时，标明是导航超时还是等待元素超时，以及对应的调整参数
+type TimeoutError struct {
+	Kind  string        // 超时类型：页面导航、等待页面元素
+	Flag  string        // 调整超时时间的启动参数
+	After time.Duration // 超时时间
+	Err   error
+}
+
+func (e *TimeoutError) Error() string {
+	return fmt.Sprintf("%s超时（超过 %s，可通过 -%s 调整）", e.Kind, e.After, e.Flag)
+}
+
+func (e *TimeoutError) Unwrap() error {
+	return e.Err
+}
+
+// asTimeoutError 把 page 自身的超时转换为 TimeoutError；
+// parent 已经结束（整个请求超时或被取消）时保留原错误，避免误报为某一步超时
+func asTimeoutError(parent context.Context, err error, kind, flag string, after time.Duration) error {
+	if err == nil || !errors.Is(err, context.DeadlineExceeded) || parent.Err() != nil {
+		return err
+	}
+	return &TimeoutError{Kind: kind, Flag: flag, After: after, Err: err}
+}
+
+// Navigate 打开页面并等待页面稳定，两步共用 -nav-timeout 设定的超时时间
+func Navigate(page *rod.Page, url string) error {
+	after := configs.GetNavTimeout()
+	p := page.Timeout(after)
+	defer p.CancelTimeout()
+
+	if err := p.Navigate(url); err != nil {
+		return asTimeoutError(page.GetContext(), err, "页面导航", "nav-timeout", after)
+	}
+	if err := p.WaitStable(time.Second); err != nil {
+		return errors.Wrap(asTimeoutError(page.GetContext(), err, "页面导航", "nav-timeout", after), "等待页面加载完成失败")
+	}
+
+	return nil
+}
+
+// findElement 等待匹配 selector 的元素出现，最多等待 -element-timeout 设定的时间
+func findElement(page *rod.Page, selector string) (*rod.Element, error) {
+	return waitElement(page, func(p *rod.Page) (*rod.Element, error) {
+		return p.Element(selector)
+	})
+}
+
+// findElementR 等待匹配 selector 且文本匹配 jsRegex 的元素出现，最多等待 -element-timeout 设定的时间
+func findElementR(page *rod.Page, selector, jsRegex string) (*rod.Element, error) {
+	return waitElement(page, func(p *rod.Page) (*rod.Element, error) {
+		return p.ElementR(selector, jsRegex)
+	})
+}
+
+// waitElement 在 -element-timeout 内执行查找，找到的元素改回 page 原来的 context，
+// 后续的点击、输入不受查找超时的限制
+func waitElement(page *rod.Page, find func(p *rod.Page) (*rod.Element, error)) (*rod.Element, error) {
+	after := configs.GetElementTimeout()
+	p := page.Timeout(after)
+	defer p.CancelTimeout()
+
+	el, err := find(p)
+	if err != nil {
+		return nil, asTimeoutError(page.GetContext(), err, "等待页面元素", "element-timeout", after)
+	}
+	return el.Context(page.GetContext()), nil
+}
@@ -0,0 +1,58 @@
+package xiaohongshu
+
+import (
+	"net/url"
+	"regexp"
+	"strings"
+
+	"github.com/go-rod/rod"
+	"github.com/pkg/errors"
+)
+
+// ErrTokenExpired 笔记或用户的 xsec_token 已过期或无效，需要重新获取列表、搜索结果以得到新的 xsec_token。
+// 与笔记不存在不同，换一个新的 xsec_token 后通常可以正常访问
+var ErrTokenExpired = errors.New("xsec_token 已过期或无效，请重新获取笔记列表或搜索结果中的 xsec_token")
+
+// tokenExpiredPattern 令牌失效时错误页或跳转地址中的提示
+var tokenExpiredPattern = regexp.MustCompile(`xsec_token|链接已失效|链接已过期|链接失效|链接过期|页面已失效|页面已过期`)
+
+// CheckTokenExpired 检查页面是否为 xsec_token 失效的错误页，是则返回包含提示的 ErrTokenExpired。
+// 令牌失效时详情页、主页会跳转到 /404 或 website-login/error，并在 error_msg 中说明原因；
+// 同时检查正文较短的错误页文字，避免把笔记正文中的同样文字误判为错误页
+func CheckTokenExpired(page *rod.Page) error {
+	if info, err := page.Info(); err == nil {
+		if message := tokenErrorMessage(info.URL); message != "" {
+			return errors.Wrapf(ErrTokenExpired, "页面提示“%s”", message)
+		}
+	}
+
+	result, err := page.Eval(`() => {
+		const text = ((document.body && document.body.innerText) || '').trim();
+		return text.length > 300 ? '' : text;
+	}`)
+	if err != nil {
+		// 检查失败时无法判断，交由调用方按原结果处理
+		return nil
+	}
+
+	for _, line := range strings.Split(result.Value.String(), "\n") {
+		if line = strings.TrimSpace(line); tokenExpiredPattern.MatchString(line) {
+			return errors.Wrapf(ErrTokenExpired, "页面提示“%s”", line)
+		}
+	}
+	return nil
+}
+
+// tokenErrorMessage 从错误页地址的 error_msg 参数中取出与令牌失效有关的提示，无关时返回空
+func tokenErrorMessage(rawURL string) string {
+	u, err := url.Parse(rawURL)
+	if err != nil || !(strings.HasPrefix(u.Path, "/404") || strings.Contains(u.Path, "website-login/error")) {
+		return ""
+	}
+
+	message := u.Query().Get("error_msg")
+	if !tokenExpiredPattern.MatchString(message) {
+		return ""
+	}
+	return message
+}
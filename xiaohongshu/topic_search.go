@@ -0,0 +1,156 @@
+package xiaohongshu
+
+import (
+	"context"
+	"encoding/json"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/go-rod/rod"
+	"github.com/pkg/errors"
+	"github.com/xpzouying/xiaohongshu-mcp/configs"
+)
+
+// Topic 话题及其热度
+type Topic struct {
+	Name             string `json:"name"`
+	ViewCount        string `json:"viewCount,omitempty"` // 页面展示的浏览量文本，如 "1.2亿次浏览"
+	ViewCountNum     int    `json:"viewCountNum"`
+	ParticipantCount string `json:"participantCount,omitempty"` // 页面展示的参与量文本，如 "3.5万人参与"
+	ParticipantNum   int    `json:"participantNum"`
+}
+
+const (
+	// pathOfLongTextEditor 创作者中心长文编辑页，正文编辑器输入 # 会弹出话题候选
+	pathOfLongTextEditor = "/publish/publish?source=official&target=article"
+	// pathOfInspiration 创作者中心的笔记灵感页，展示当前热门话题
+	pathOfInspiration = "/new/inspiration?source=official"
+)
+
+// countInText 匹配文本中的数量，如 "1.2亿次浏览" 中的 "1.2亿"
+var countInText = regexp.MustCompile(`[\d.,]+\s*[万亿wWkK]?`)
+
+// TopicSearchAction 话题搜索
+type TopicSearchAction struct {
+	page *rod.Page
+}
+
+// NewTopicSearchAction 创建话题搜索 action
+func NewTopicSearchAction(page *rod.Page) *TopicSearchAction {
+	return &TopicSearchAction{page: page}
+}
+
+// Search 在编辑器中输入 #关键词，读取弹出的话题候选及其浏览量
+func (t *TopicSearchAction) Search(ctx context.Context, keyword string) ([]Topic, error) {
+	page := t.page.Context(ctx)
+
+	editor, err := openTopicEditor(page)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := humanInput(editor, "#"+strings.TrimPrefix(strings.TrimSpace(keyword), "#")); err != nil {
+		return nil, errors.Wrap(err, "输入话题关键词失败")
+	}
+	time.Sleep(2 * time.Second)
+
+	result, err := page.Eval(`() => JSON.stringify(Array.from(document.querySelectorAll('#creator-editor-topic-container .item')).map(item => ({
+		name: (item.querySelector('.name') || item).innerText.split('\n')[0].replace(/^#/, '').trim(),
+		view: (item.querySelector('.num') || {}).innerText || '',
+	})))`)
+	if err != nil {
+		return nil, errors.Wrap(err, "获取话题候选失败")
+	}
+
+	var items []struct {
+		Name string `json:"name"`
+		View string `json:"view"`
+	}
+	if err := json.Unmarshal([]byte(result.Value.String()), &items); err != nil {
+		return nil, errors.Wrap(err, "解析话题候选失败")
+	}
+
+	topics := make([]Topic, 0, len(items))
+	for _, item := range items {
+		if item.Name == "" {
+			continue
+		}
+		topics = append(topics, Topic{
+			Name:         item.Name,
+			ViewCount:    item.View,
+			ViewCountNum: countInTextOrZero(item.View),
+		})
+	}
+
+	return topics, nil
+}
+
+// Trending 读取笔记灵感页的热门话题及其参与量、浏览量
+func (t *TopicSearchAction) Trending(ctx context.Context) ([]Topic, error) {
+	page := t.page.Context(ctx)
+
+	if err := Navigate(page, configs.CreatorURL(pathOfInspiration)); err != nil {
+		return nil, errors.Wrap(err, "打开笔记灵感页失败")
+	}
+
+	result, err := page.Eval(`() => JSON.stringify(Array.from(document.querySelectorAll('.topic-list .topic-item, .hot-topic .item')).map(item => ({
+		name: ((item.querySelector('.title, .name') || item).innerText || '').split('\n')[0].replace(/^#/, '').trim(),
+		view: (item.querySelector('.view, .view-count') || {}).innerText || '',
+		participant: (item.querySelector('.participant, .join-count') || {}).innerText || '',
+	})))`)
+	if err != nil {
+		return nil, errors.Wrap(err, "获取热门话题失败")
+	}
+
+	var items []struct {
+		Name        string `json:"name"`
+		View        string `json:"view"`
+		Participant string `json:"participant"`
+	}
+	if err := json.Unmarshal([]byte(result.Value.String()), &items); err != nil {
+		return nil, errors.Wrap(err, "解析热门话题失败")
+	}
+
+	topics := make([]Topic, 0, len(items))
+	for _, item := range items {
+		if item.Name == "" {
+			continue
+		}
+		topics = append(topics, Topic{
+			Name:             item.Name,
+			ViewCount:        item.View,
+			ViewCountNum:     countInTextOrZero(item.View),
+			ParticipantCount: item.Participant,
+			ParticipantNum:   countInTextOrZero(item.Participant),
+		})
+	}
+
+	return topics, nil
+}
+
+// openTopicEditor 打开长文编辑器并返回正文输入框，话题候选与发布时使用的是同一个组件
+func openTopicEditor(page *rod.Page) (*rod.Element, error) {
+	if err := Navigate(page, configs.CreatorURL(pathOfLongTextEditor)); err != nil {
+		return nil, errors.Wrap(err, "打开创作者中心失败")
+	}
+
+	if button, err := page.Timeout(5*time.Second).ElementR("button", "新的创作"); err == nil {
+		if err := humanClick(button); err != nil {
+			return nil, errors.Wrap(err, "打开编辑器失败")
+		}
+		time.Sleep(time.Second)
+	}
+
+	editor, err := findElement(page, "div.tiptap.ProseMirror, div.ql-editor")
+	if err != nil {
+		return nil, errors.Wrap(err, "没有找到编辑器，可能未登录创作者中心")
+	}
+
+	return editor, nil
+}
+
+// countInTextOrZero 从 "1.2亿次浏览" 这类文本中解析数量，解析失败返回 0
+func countInTextOrZero(text string) int {
+	return countOrZero(countInText.FindString(text))
+}
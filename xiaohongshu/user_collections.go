@@ -0,0 +1,123 @@
+package xiaohongshu
+
+import (
+	"context"
+	"encoding/json"
+	"net/url"
+	"time"
+
+	"github.com/go-rod/rod"
+	"github.com/pkg/errors"
+	"github.com/xpzouying/xiaohongshu-mcp/configs"
+)
+
+// ErrCollectionsHidden 用户隐藏了收藏，主页的收藏页签不可见或提示仅自己可见
+var ErrCollectionsHidden = errors.New("该用户隐藏了收藏，无法获取收藏专辑")
+
+// UserCollection 用户公开的收藏专辑
+type UserCollection struct {
+	ID        string `json:"id"`
+	Name      string `json:"name"`
+	NoteCount int    `json:"note_count"` // 专辑中的笔记数量
+	URL       string `json:"url"`        // 专辑页链接
+}
+
+// makeBoardURL 收藏专辑页链接
+func makeBoardURL(boardID string) string {
+	return configs.SiteURL("/board/" + url.PathEscape(boardID) + "?source=web_user_page")
+}
+
+// Collections 打开用户主页的“收藏”页签并切换到“专辑”，返回用户公开的收藏专辑。
+// 私密账号返回 ErrProfilePrivate，用户隐藏了收藏时返回 ErrCollectionsHidden，没有专辑时返回空列表
+func (u *UserProfileAction) Collections(ctx context.Context, userID, xsecToken string) ([]UserCollection, error) {
+	page := u.page.Context(ctx)
+
+	if err := Navigate(page, makeUserProfileURL(userID, xsecToken)); err != nil {
+		return nil, errors.Wrap(err, "打开用户主页失败")
+	}
+
+	if isPrivateProfile(page) {
+		return nil, ErrProfilePrivate
+	}
+
+	collectTab, err := findElementR(page, ".reds-tab-item, .tab-content-item, .sub-tab-list span", "^收藏")
+	if err != nil {
+		return nil, ErrCollectionsHidden
+	}
+	if err := humanClick(collectTab); err != nil {
+		return nil, errors.Wrap(err, "打开收藏页签失败")
+	}
+	time.Sleep(time.Second)
+
+	if collectionsHidden(page) {
+		return nil, ErrCollectionsHidden
+	}
+
+	// 收藏页签默认显示收藏的笔记，专辑在“专辑”子页签中；没有子页签时说明用户没有专辑
+	if boardTab, err := page.Timeout(5*time.Second).ElementR(".reds-tab-item, .sub-tab-list span, .tab-content-item", "^专辑"); err == nil {
+		if err := humanClick(boardTab); err != nil {
+			return nil, errors.Wrap(err, "打开专辑页签失败")
+		}
+		time.Sleep(time.Second)
+	}
+
+	return loadedUserCollections(page)
+}
+
+// loadedUserCollections 读取当前已加载的收藏专辑，优先读取 __INITIAL_STATE__.board.userBoardList，没有时读取页面上的专辑卡片
+func loadedUserCollections(page *rod.Page) ([]UserCollection, error) {
+	result, err := page.Eval(`() => {
+		const unwrap = v => (v && (v._value || v.value)) || v;
+		const board = unwrap(window.__INITIAL_STATE__ && window.__INITIAL_STATE__.board) || {};
+		const list = unwrap(board.userBoardList);
+		const count = v => parseInt(String(v || '0').replace(/[^\d]/g, ''), 10) || 0;
+		if (Array.isArray(list) && list.length > 0) {
+			return JSON.stringify(list.flat().filter(b => b && b.id).map(b => ({
+				id: b.id,
+				name: b.name || b.title || '',
+				count: count(b.total || b.noteCount || b.note_count),
+			})));
+		}
+		return JSON.stringify(Array.from(document.querySelectorAll('a[href*="/board/"]')).map(a => {
+			const id = ((a.getAttribute('href') || '').match(/\/board\/([^/?#]+)/) || [])[1] || '';
+			const lines = (a.innerText || '').split('\n').map(s => s.trim()).filter(Boolean);
+			const countLine = lines.find(s => /\d+\s*(篇|个|条)?\s*笔记/.test(s)) || '';
+			return { id, name: lines.find(s => s !== countLine) || '', count: count(countLine) };
+		}).filter(b => b.id));
+	}`)
+	if err != nil {
+		return nil, errors.Wrap(err, "获取收藏专辑失败")
+	}
+
+	var raw []struct {
+		ID    string `json:"id"`
+		Name  string `json:"name"`
+		Count int    `json:"count"`
+	}
+	if err := json.Unmarshal([]byte(result.Value.String()), &raw); err != nil {
+		return nil, errors.Wrap(err, "解析收藏专辑失败")
+	}
+
+	collections := make([]UserCollection, 0, len(raw))
+	seen := make(map[string]bool, len(raw))
+	for _, b := range raw {
+		if seen[b.ID] {
+			continue
+		}
+		seen[b.ID] = true
+		collections = append(collections, UserCollection{
+			ID:        b.ID,
+			Name:      b.Name,
+			NoteCount: b.Count,
+			URL:       makeBoardURL(b.ID),
+		})
+	}
+
+	return collections, nil
+}
+
+// collectionsHidden 判断收藏页签是否提示用户隐藏了收藏
+func collectionsHidden(page *rod.Page) bool {
+	result, err := page.Eval(`() => /隐藏了?收藏|收藏.{0,6}(仅自己可见|不可见|未公开)|暂无权限查看/.test((document.querySelector('.user-page, #userPageContainer, .feeds-tab-container') || document.body).innerText || '')`)
+	return err == nil && result.Value.Bool()
+}
@@ -0,0 +1,122 @@
+package xiaohongshu
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"time"
+
+	"github.com/go-rod/rod"
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+	"github.com/xpzouying/xiaohongshu-mcp/configs"
+)
+
+// ErrProfilePrivate 用户主页为私密账号，看不到笔记列表
+var ErrProfilePrivate = errors.New("该用户为私密账号，无法获取笔记列表")
+
+const (
+	// DefaultUserFeedsLimit 每次获取用户笔记的默认数量
+	DefaultUserFeedsLimit = 30
+	// MaxUserFeedsLimit 每次获取用户笔记的最大数量
+	MaxUserFeedsLimit = 200
+	// userFeedsIdleScrolls 连续多少次滚动没有加载出新笔记时认为已到底
+	userFeedsIdleScrolls = 3
+)
+
+// makeUserProfileURL 用户主页链接
+func makeUserProfileURL(userID, xsecToken string) string {
+	return configs.SiteURL(fmt.Sprintf("/user/profile/%s?xsec_token=%s&xsec_source=pc_feed", userID, url.QueryEscape(xsecToken)))
+}
+
+// UserFeeds 滚动用户主页加载笔记，返回从 cursor 开始的最多 limit 条笔记及下一页的游标。
+// 每次请求都会重新打开主页并滚动到游标对应的位置；没有更多笔记时下一页游标为空。
+// 滚动次数达到 -max-scrolls 仍未加载够时 truncated 为 true，返回已加载的部分
+func (u *UserProfileAction) UserFeeds(ctx context.Context, userID, xsecToken string, limit int, cursor Cursor) (feeds []Feed, next string, truncated bool, err error) {
+	if limit <= 0 {
+		limit = DefaultUserFeedsLimit
+	}
+	limit = min(limit, MaxUserFeedsLimit)
+
+	page := u.page.Context(ctx)
+
+	if err := Navigate(page, makeUserProfileURL(userID, xsecToken)); err != nil {
+		return nil, "", false, errors.Wrap(err, "打开用户主页失败")
+	}
+
+	if isPrivateProfile(page) {
+		return nil, "", false, ErrProfilePrivate
+	}
+
+	// 多取一条，用于判断是否还有下一页
+	want := cursor.Offset + limit + 1
+	feeds, err = loadedUserFeeds(page)
+	if err != nil {
+		return nil, "", false, err
+	}
+
+	idle, scrolls, maxScrolls := 0, 0, configs.GetMaxScrolls()
+	for ; len(feeds) < want && idle < userFeedsIdleScrolls && scrolls < maxScrolls; scrolls++ {
+		if _, err := page.Eval(`() => window.scrollTo(0, document.body.scrollHeight)`); err != nil {
+			return nil, "", false, errors.Wrap(err, "滚动用户主页失败")
+		}
+		time.Sleep(1500 * time.Millisecond)
+
+		more, err := loadedUserFeeds(page)
+		if err != nil {
+			return nil, "", false, err
+		}
+		if len(more) > len(feeds) {
+			idle = 0
+		} else {
+			idle++
+		}
+		feeds = more
+	}
+
+	truncated = len(feeds) < want && idle < userFeedsIdleScrolls && scrolls >= maxScrolls
+	if truncated {
+		logrus.WithContext(ctx).Warnf("用户 %s 主页滚动 %d 次后停止，只加载了 %d 条笔记", userID, scrolls, len(feeds))
+	} else {
+		logrus.WithContext(ctx).Infof("用户 %s 主页已加载 %d 条笔记", userID, len(feeds))
+	}
+
+	offset := cursor.ResumeOffset(FeedIDs(feeds))
+	if offset >= len(feeds) {
+		return []Feed{}, "", truncated, nil
+	}
+
+	end := min(offset+limit, len(feeds))
+	if end < len(feeds) {
+		next = NextCursor(UserFeedsCursorScope(userID), end, feeds[end-1].ID)
+	}
+
+	return feeds[offset:end], next, truncated, nil
+}
+
+// loadedUserFeeds 读取主页当前已加载的全部笔记。主页笔记按页存放在 __INITIAL_STATE__.user.notes 中
+func loadedUserFeeds(page *rod.Page) ([]Feed, error) {
+	result, err := page.Eval(`() => {
+		const user = window.__INITIAL_STATE__ && window.__INITIAL_STATE__.user;
+		const notes = user && user.notes;
+		const pages = notes && (notes._value || notes.value || notes);
+		return JSON.stringify(Array.isArray(pages) ? pages.flat() : []);
+	}`)
+	if err != nil {
+		return nil, errors.Wrap(err, "获取用户笔记失败")
+	}
+
+	var feeds []Feed
+	if err := json.Unmarshal([]byte(result.Value.String()), &feeds); err != nil {
+		return nil, errors.Wrap(err, "解析用户笔记失败")
+	}
+
+	return feeds, nil
+}
+
+// isPrivateProfile 判断主页是否显示私密账号提示
+func isPrivateProfile(page *rod.Page) bool {
+	result, err := page.Eval(`() => /私密账号|仅自己可见|暂时无法查看/.test((document.querySelector('.user-page, #userPageContainer, .feeds-tab-container') || document.body).innerText || '')`)
+	return err == nil && result.Value.Bool()
+}
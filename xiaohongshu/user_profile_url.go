@@ -0,0 +1,84 @@
+package xiaohongshu
+
+import (
+	"context"
+	"net/url"
+	"strings"
+
+	"github.com/pkg/errors"
+	"github.com/xpzouying/xiaohongshu-mcp/configs"
+)
+
+// userProfilePathPrefix 用户主页路径前缀，形如 /user/profile/<user_id>
+const userProfilePathPrefix = "/user/profile/"
+
+// UserProfileByURL 直接打开用户主页链接获取用户信息，并从页面最终地址中解析出 user_id。
+// 适用于只知道主页链接、没有从 Feed 列表拿到 xsec_token 的场景。
+// xhslink.com 短链先跟随跳转得到主页地址；只知道小红书号时无法查找，需要先取得主页链接
+func (u *UserProfileAction) UserProfileByURL(ctx context.Context, profileURL string) (*UserProfileResponse, string, error) {
+	profileURL, err := resolveProfileURL(ctx, profileURL)
+	if err != nil {
+		return nil, "", err
+	}
+
+	page := u.page.Context(ctx)
+
+	if err := Navigate(page, profileURL); err != nil {
+		return nil, "", errors.Wrap(err, "打开用户主页失败")
+	}
+
+	// 短链或带参数的链接可能发生跳转，以最终地址为准
+	info, err := page.Info()
+	if err != nil {
+		return nil, "", errors.Wrap(err, "获取页面地址失败")
+	}
+	userID, err := userIDFromProfileURL(info.URL)
+	if err != nil {
+		return nil, "", err
+	}
+
+	result, err := u.extractUserProfileData(page)
+	if err != nil {
+		return nil, "", err
+	}
+
+	return result, userID, nil
+}
+
+// resolveProfileURL 校验用户主页链接，xhslink.com 短链跟随跳转后返回最终的主页地址
+func resolveProfileURL(ctx context.Context, rawURL string) (string, error) {
+	u, err := url.Parse(strings.TrimSpace(rawURL))
+	if err != nil || u.Host == "" {
+		return "", errors.Errorf("无效的用户主页链接: %s", rawURL)
+	}
+
+	if isShortLinkHost(u.Hostname()) {
+		if u, err = followShortLink(ctx, u.String()); err != nil {
+			return "", err
+		}
+	}
+
+	if _, err := userIDFromProfileURL(u.String()); err != nil {
+		return "", err
+	}
+	return u.String(), nil
+}
+
+// userIDFromProfileURL 从用户主页链接中解析 user_id，短链需先由 resolveProfileURL 跟随跳转
+func userIDFromProfileURL(rawURL string) (string, error) {
+	u, err := url.Parse(strings.TrimSpace(rawURL))
+	if err != nil || u.Host == "" {
+		return "", errors.Errorf("无效的用户主页链接: %s", rawURL)
+	}
+
+	if !configs.IsSiteHost(u.Hostname()) {
+		return "", errors.Errorf("不是小红书用户主页链接: %s", rawURL)
+	}
+
+	userID := strings.Trim(strings.TrimPrefix(u.Path, userProfilePathPrefix), "/")
+	if !strings.HasPrefix(u.Path, userProfilePathPrefix) || userID == "" || strings.Contains(userID, "/") {
+		return "", errors.Errorf("链接中没有用户ID，应形如 https://www.xiaohongshu.com/user/profile/<user_id>: %s", rawURL)
+	}
+
+	return userID, nil
+}
@@ -0,0 +1,60 @@
+package xiaohongshu
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestUserIDFromProfileURL(t *testing.T) {
+	tests := []struct {
+		url     string
+		want    string
+		wantErr bool
+	}{
+		{url: "https://www.xiaohongshu.com/user/profile/5a1b2c3d4e5f6a7b8c9d0e1f", want: "5a1b2c3d4e5f6a7b8c9d0e1f"},
+		{url: " https://www.xiaohongshu.com/user/profile/5a1b2c3d4e5f6a7b8c9d0e1f/?xsec_source=pc_note ", want: "5a1b2c3d4e5f6a7b8c9d0e1f"},
+		{url: "https://www.xiaohongshu.com/explore/64f0a1b2c3d4e5f6a7b8c901", wantErr: true},
+		{url: "https://www.xiaohongshu.com/user/profile/", wantErr: true},
+		{url: "https://example.com/user/profile/5a1b2c3d4e5f6a7b8c9d0e1f", wantErr: true},
+		{url: "http://xhslink.com/m/AbCdEf", wantErr: true},
+		{url: "5a1b2c3d4e5f6a7b8c9d0e1f", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.url, func(t *testing.T) {
+			got, err := userIDFromProfileURL(tt.url)
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestResolveProfileURL(t *testing.T) {
+	got, err := resolveProfileURL(context.Background(), "https://www.xiaohongshu.com/user/profile/5a1b2c3d4e5f6a7b8c9d0e1f")
+	require.NoError(t, err)
+	assert.Equal(t, "https://www.xiaohongshu.com/user/profile/5a1b2c3d4e5f6a7b8c9d0e1f", got)
+
+	_, err = resolveProfileURL(context.Background(), "https://example.com/user/profile/5a1b2c3d4e5f6a7b8c9d0e1f")
+	assert.Error(t, err)
+
+	// 短链在跟随跳转时才校验，这里取消 ctx，确认短链没有在域名检查时被拒绝
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	_, err = resolveProfileURL(ctx, "http://xhslink.com/m/AbCdEf")
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "解析短链失败")
+}
+
+func TestIsShortLinkHost(t *testing.T) {
+	assert.True(t, isShortLinkHost("xhslink.com"))
+	assert.True(t, isShortLinkHost("www.XHSLINK.com"))
+	assert.False(t, isShortLinkHost("evilxhslink.com"))
+	assert.False(t, isShortLinkHost("www.xiaohongshu.com"))
+}
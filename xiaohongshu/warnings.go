@@ -0,0 +1,143 @@
+package xiaohongshu
+
+import (
+	"encoding/json"
+	"fmt"
+)
+
+// 数据提取警告
+//
+// 页面结构局部变化（笔记被删除、互动数被隐藏等）时，不让整个提取失败，
+// 而是把不完整的地方作为警告随结果一起返回，由调用方决定是否需要处理。
+
+// profileInteractionNames 主页互动数据的类型及展示名称
+var profileInteractionNames = map[string]string{
+	"follows":     "关注数",
+	"fans":        "粉丝数",
+	"interaction": "获赞与收藏数",
+}
+
+// FeedWarnings 检查笔记列表中不完整的数据，例如缺少 ID、xsec_token 或互动数无法解析
+func FeedWarnings(feeds []Feed) []string {
+	var warnings []string
+
+	for i, feed := range feeds {
+		label := fmt.Sprintf("第 %d 条笔记", i+1)
+		if feed.ID != "" {
+			label += "（" + feed.ID + "）"
+		}
+
+		if feed.ID == "" {
+			warnings = append(warnings, label+"缺少笔记 ID")
+		}
+		if feed.XsecToken == "" {
+			warnings = append(warnings, label+"缺少 xsec_token，无法获取详情")
+		}
+		if _, err := parseCount(feed.NoteCard.InteractInfo.LikedCount); err != nil {
+			warnings = append(warnings, label+"的点赞数无法解析: "+feed.NoteCard.InteractInfo.LikedCount)
+		}
+	}
+
+	return warnings
+}
+
+// feedDetailFields 笔记详情中需要检查的字段，详情数据按 JSON 读取，不依赖具体的结构体
+type feedDetailFields struct {
+	Note struct {
+		NoteID string `json:"noteId"`
+		Type   string `json:"type"`
+		User   struct {
+			UserID string `json:"userId"`
+		} `json:"user"`
+		InteractInfo struct {
+			LikedCount     string `json:"likedCount"`
+			CollectedCount string `json:"collectedCount"`
+			CommentCount   string `json:"commentCount"`
+			SharedCount    string `json:"sharedCount"`
+		} `json:"interactInfo"`
+		ImageList []json.RawMessage `json:"imageList"`
+	} `json:"note"`
+}
+
+// FeedDetailWarnings 检查笔记详情中不完整的数据，例如缺少笔记 ID、作者、图片，或互动数无法解析。
+// 详情无法按 JSON 读取时返回一条说明
+func FeedDetailWarnings(detail any) []string {
+	data, err := json.Marshal(detail)
+	if err != nil {
+		return []string{"笔记详情无法检查: " + err.Error()}
+	}
+	var fields feedDetailFields
+	if err := json.Unmarshal(data, &fields); err != nil {
+		return []string{"笔记详情无法检查: " + err.Error()}
+	}
+
+	var warnings []string
+	note := fields.Note
+	if note.NoteID == "" {
+		warnings = append(warnings, "笔记详情缺少笔记 ID")
+	}
+	if note.User.UserID == "" {
+		warnings = append(warnings, "笔记详情缺少作者信息")
+	}
+	if note.Type != "video" && len(note.ImageList) == 0 {
+		warnings = append(warnings, "图文笔记缺少图片")
+	}
+
+	for _, count := range []struct{ name, value string }{
+		{"点赞数", note.InteractInfo.LikedCount},
+		{"收藏数", note.InteractInfo.CollectedCount},
+		{"评论数", note.InteractInfo.CommentCount},
+		{"分享数", note.InteractInfo.SharedCount},
+	} {
+		if _, err := parseCount(count.value); err != nil {
+			warnings = append(warnings, "笔记的"+count.name+"无法解析: "+count.value)
+		}
+	}
+
+	return warnings
+}
+
+// ProfileWarnings 检查用户主页中不完整的数据，例如互动数被隐藏或无法解析
+func ProfileWarnings(profile *UserProfileResponse) []string {
+	var warnings []string
+
+	if profile.UserBasicInfo.Nickname == "" {
+		warnings = append(warnings, "主页缺少昵称")
+	}
+
+	found := make(map[string]bool, len(profile.Interactions))
+	for _, interaction := range profile.Interactions {
+		name, ok := profileInteractionNames[interaction.Type]
+		if !ok {
+			continue
+		}
+		found[interaction.Type] = true
+
+		if _, err := parseCount(interaction.Count); err != nil {
+			warnings = append(warnings, fmt.Sprintf("主页的%s无法解析: %s", name, interaction.Count))
+		}
+	}
+	for _, typ := range []string{"follows", "fans", "interaction"} {
+		if !found[typ] {
+			warnings = append(warnings, "主页缺少"+profileInteractionNames[typ]+"，可能已被用户隐藏")
+		}
+	}
+
+	return append(warnings, FeedWarnings(profile.Feeds)...)
+}
+
+// TopicWarnings 检查话题列表中无法解析的浏览量、参与量
+func TopicWarnings(topics []Topic) []string {
+	var warnings []string
+
+	for _, topic := range topics {
+		if topic.ViewCount != "" && countInText.FindString(topic.ViewCount) == "" {
+			warnings = append(warnings, fmt.Sprintf("话题 %s 的浏览量无法解析: %s", topic.Name, topic.ViewCount))
+		}
+		if topic.ParticipantCount != "" && countInText.FindString(topic.ParticipantCount) == "" {
+			warnings = append(warnings, fmt.Sprintf("话题 %s 的参与量无法解析: %s", topic.Name, topic.ParticipantCount))
+		}
+	}
+
+	return warnings
+}
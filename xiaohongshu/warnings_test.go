@@ -0,0 +1,49 @@
+package xiaohongshu
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFeedDetailWarnings(t *testing.T) {
+	tests := []struct {
+		name   string
+		detail string
+		want   []string
+	}{
+		{
+			name: "complete",
+			detail: `{"note": {"noteId": "1", "type": "normal", "user": {"userId": "u"},
+				"interactInfo": {"likedCount": "1.2万", "collectedCount": "10", "commentCount": "", "sharedCount": "3"},
+				"imageList": [{"urlDefault": "https://example.com/1.jpg"}]}}`,
+		},
+		{
+			name: "video without images",
+			detail: `{"note": {"noteId": "1", "type": "video", "user": {"userId": "u"},
+				"interactInfo": {"likedCount": "5"}}}`,
+		},
+		{
+			name: "missing fields",
+			detail: `{"note": {"type": "normal",
+				"interactInfo": {"likedCount": "赞", "collectedCount": "10"}}}`,
+			want: []string{
+				"笔记详情缺少笔记 ID",
+				"笔记详情缺少作者信息",
+				"图文笔记缺少图片",
+				"笔记的点赞数无法解析: 赞",
+			},
+		},
+		{
+			name:   "not an object",
+			detail: `["note"]`,
+			want:   []string{"笔记详情无法检查: json: cannot unmarshal array into Go value of type xiaohongshu.feedDetailFields"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, FeedDetailWarnings(json.RawMessage(tt.detail)))
+		})
+	}
+}